@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Taras-Rm/shipment/jwt"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+	tenantHeader = "X-Tenant-ID"
+	// client of the authenticated key in the request context (empty for the staff)
+	clientContextKey = "client"
+	// tenant of the authenticated key in the request context (not set for the staff)
+	keyTenantContextKey = "keyTenant"
+	// role of the authenticated client or staff member in the request context
+	roleContextKey = "role"
+	// tenant of the request in the request context (empty without tenants)
+	tenantContextKey = "tenant"
+)
+
+// machine clients manage their own shipments
+const apiKeyRole = models.RoleOperator
+
+// allow only the requests with a valid API key of the client or a bearer token of the staff member
+func authenticate(apiKeyService services.APIKeyService, tokenVerifier jwt.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// staff members see the shipments of all clients
+		if authorization := c.GetHeader("Authorization"); authorization != "" {
+			if !strings.HasPrefix(authorization, bearerPrefix) {
+				newErrorResponse(c, http.StatusUnauthorized, errors.New("api key or bearer token is required"))
+				return
+			}
+
+			claims, err := tokenVerifier.Verify(strings.TrimPrefix(authorization, bearerPrefix))
+			if err != nil {
+				newErrorResponse(c, http.StatusUnauthorized, err)
+				return
+			}
+
+			c.Set(roleContextKey, models.Role(claims.Role))
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader(apiKeyHeader)
+		if key == "" {
+			newErrorResponse(c, http.StatusUnauthorized, errors.New("api key or bearer token is required"))
+			return
+		}
+
+		apiKey, err := apiKeyService.Authenticate(key)
+		if errors.Is(err, services.ErrorInvalidAPIKey) {
+			newErrorResponse(c, http.StatusUnauthorized, err)
+			return
+		}
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Set(clientContextKey, apiKey.Client)
+		c.Set(keyTenantContextKey, apiKey.Tenant)
+		c.Set(roleContextKey, apiKeyRole)
+		c.Next()
+	}
+}
+
+// allow only the roles with the permission
+func authorize(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get(roleContextKey)
+		if role, ok := role.(models.Role); !ok || !role.Can(permission) {
+			newErrorResponse(c, http.StatusForbidden, errors.New("forbidden"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// resolve the tenant of the request by the API key, the tenant header or the host name
+func resolveTenant(tenantService services.TenantService, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant := ""
+		if id := c.GetHeader(tenantHeader); id != "" {
+			if _, err := tenantService.GetTenant(id); err != nil {
+				newErrorResponse(c, http.StatusBadRequest, err)
+				return
+			}
+			tenant = id
+		} else if byHost, err := tenantService.GetTenantByHost(c.Request.Host); err == nil {
+			tenant = byHost.Id
+		}
+
+		// API key clients work only in the tenant of the key
+		if keyTenant, ok := c.Get(keyTenantContextKey); ok {
+			if tenant != "" && tenant != keyTenant {
+				newErrorResponse(c, http.StatusForbidden, errors.New("api key doesn't belong to the tenant"))
+				return
+			}
+			tenant = keyTenant.(string)
+		}
+
+		if tenant == "" && required && tenantService.MultiTenant() {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("tenant is required"))
+			return
+		}
+
+		c.Set(tenantContextKey, tenant)
+		c.Next()
+	}
+}
+
+// handler with the shipment service of the tenant and of the authenticated client (it sees only the shipments created by the client)
+func scoped(shipmentService services.ShipmentService, tenantService services.TenantService, handler func(services.ShipmentService) gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		service := shipmentService
+		if tenant := c.GetString(tenantContextKey); tenant != "" {
+			service = service.WithTenant(tenant, tenantService.PricingEngine(tenant))
+		}
+
+		handler(service.WithOwner(c.GetString(clientContextKey)))(c)
+	}
+}
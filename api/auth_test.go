@@ -0,0 +1,324 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/jwt"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/services"
+	mock_services "github.com/Taras-Rm/shipment/services/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var testTokenNow = time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC)
+
+// HS256 token of the claims signed by the test secret
+func testToken(claims string) string {
+	signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte("test-secret"))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthenticate(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockAPIKeyService)
+
+	testCases := []struct {
+		name                 string
+		key                  string
+		authorization        string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			key:  "sk_010203040506_secret",
+			mockBehaviur: func(r *mock_services.MockAPIKeyService) {
+				r.EXPECT().Authenticate("sk_010203040506_secret").Return(models.APIKey{Id: 1, Client: "acme"}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"client":"acme","role":"operator"}`,
+		},
+		{
+			name:                 "OK with bearer token",
+			authorization:        "Bearer " + testToken(`{"sub":"mark","role":"viewer","exp":1641810600}`),
+			mockBehaviur:         func(r *mock_services.MockAPIKeyService) {},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"client":"","role":"viewer"}`,
+		},
+		{
+			name:                 "expired bearer token",
+			authorization:        "Bearer " + testToken(`{"sub":"mark","role":"viewer","exp":1641803400}`),
+			mockBehaviur:         func(r *mock_services.MockAPIKeyService) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"token expired"}`,
+		},
+		{
+			name:                 "not bearer authorization",
+			authorization:        "Basic bWFyazpzZWNyZXQ=",
+			mockBehaviur:         func(r *mock_services.MockAPIKeyService) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"api key or bearer token is required"}`,
+		},
+		{
+			name:                 "no key",
+			mockBehaviur:         func(r *mock_services.MockAPIKeyService) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"api key or bearer token is required"}`,
+		},
+		{
+			name: "invalid key",
+			key:  "sk_010203040506_wrong",
+			mockBehaviur: func(r *mock_services.MockAPIKeyService) {
+				r.EXPECT().Authenticate("sk_010203040506_wrong").Return(models.APIKey{}, services.ErrorInvalidAPIKey)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"invalid api key"}`,
+		},
+		{
+			name: "some internal error",
+			key:  "sk_010203040506_secret",
+			mockBehaviur: func(r *mock_services.MockAPIKeyService) {
+				r.EXPECT().Authenticate("sk_010203040506_secret").Return(models.APIKey{}, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			apiKey := mock_services.NewMockAPIKeyService(c)
+			tC.mockBehaviur(apiKey)
+
+			tokenVerifier := jwt.InitVerifier(jwt.Keys{Secret: []byte("test-secret")}, "", "", func() time.Time { return testTokenNow })
+
+			// Init endpoint (the handler returns the authenticated client and role)
+			api := gin.New()
+			api.GET("/", authenticate(apiKey, tokenVerifier), func(c *gin.Context) {
+				role, _ := c.Get(roleContextKey)
+				c.JSON(http.StatusOK, gin.H{"client": c.GetString(clientContextKey), "role": role})
+			})
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if tC.key != "" {
+				req.Header.Set("X-API-Key", tC.key)
+			}
+			if tC.authorization != "" {
+				req.Header.Set("Authorization", tC.authorization)
+			}
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	testCases := []struct {
+		name               string
+		role               interface{}
+		permission         models.Permission
+		expectedStatusCode int
+	}{
+		{name: "viewer views", role: models.RoleViewer, permission: models.PermissionViewShipments, expectedStatusCode: http.StatusOK},
+		{name: "viewer manages", role: models.RoleViewer, permission: models.PermissionManageShipments, expectedStatusCode: http.StatusForbidden},
+		{name: "operator manages", role: models.RoleOperator, permission: models.PermissionManageShipments, expectedStatusCode: http.StatusOK},
+		{name: "operator changes rate cards", role: models.RoleOperator, permission: models.PermissionManageRateCards, expectedStatusCode: http.StatusForbidden},
+		{name: "admin changes rate cards", role: models.RoleAdmin, permission: models.PermissionManageRateCards, expectedStatusCode: http.StatusOK},
+		{name: "unknown role", role: models.Role("owner"), permission: models.PermissionViewShipments, expectedStatusCode: http.StatusForbidden},
+		{name: "no role", permission: models.PermissionViewShipments, expectedStatusCode: http.StatusForbidden},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init endpoint
+			api := gin.New()
+			api.Use(func(c *gin.Context) {
+				if tC.role != nil {
+					c.Set(roleContextKey, tC.role)
+				}
+			})
+			api.GET("/", authorize(tC.permission), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			// Make request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+		})
+	}
+}
+
+func TestResolveTenant(t *testing.T) {
+	tenants, err := services.InitTenantService([]models.Tenant{
+		{Id: "brand-a", Hosts: []string{"brand-a.example.com"}},
+		{Id: "brand-b", Hosts: []string{"brand-b.example.com"}},
+	}, nil)
+	require.NoError(t, err)
+	singleTenant, err := services.InitTenantService(nil, nil)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name                 string
+		tenants              services.TenantService
+		required             bool
+		host                 string
+		header               string
+		keyTenant            interface{}
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "by header",
+			tenants:              tenants,
+			required:             true,
+			header:               "brand-a",
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"tenant":"brand-a"}`,
+		},
+		{
+			name:                 "by host",
+			tenants:              tenants,
+			required:             true,
+			host:                 "brand-b.example.com:8080",
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"tenant":"brand-b"}`,
+		},
+		{
+			name:                 "by api key",
+			tenants:              tenants,
+			required:             true,
+			keyTenant:            "brand-a",
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"tenant":"brand-a"}`,
+		},
+		{
+			name:                 "api key of another tenant",
+			tenants:              tenants,
+			required:             true,
+			header:               "brand-b",
+			keyTenant:            "brand-a",
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"error":"api key doesn't belong to the tenant"}`,
+		},
+		{
+			name:                 "api key without tenant",
+			tenants:              tenants,
+			required:             true,
+			keyTenant:            "",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"tenant is required"}`,
+		},
+		{
+			name:                 "unknown tenant",
+			tenants:              tenants,
+			required:             true,
+			header:               "brand-c",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"tenant not found"}`,
+		},
+		{
+			name:                 "not required",
+			tenants:              tenants,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"tenant":""}`,
+		},
+		{
+			name:                 "single tenant",
+			tenants:              singleTenant,
+			required:             true,
+			keyTenant:            "",
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"tenant":""}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init endpoint (the handler returns the tenant of the request)
+			api := gin.New()
+			api.Use(func(c *gin.Context) {
+				if tC.keyTenant != nil {
+					c.Set(keyTenantContextKey, tC.keyTenant)
+				}
+			})
+			api.GET("/", resolveTenant(tC.tenants, tC.required), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"tenant": c.GetString(tenantContextKey)})
+			})
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if tC.host != "" {
+				req.Host = tC.host
+			}
+			if tC.header != "" {
+				req.Header.Set("X-Tenant-ID", tC.header)
+			}
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestScoped(t *testing.T) {
+	// Init deps
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	shipment := mock_services.NewMockShipmentService(c)
+	tenantShipment := mock_services.NewMockShipmentService(c)
+	clientShipment := mock_services.NewMockShipmentService(c)
+	tenants := mock_services.NewMockTenantService(c)
+	tenants.EXPECT().PricingEngine("brand-a").Return(nil)
+	shipment.EXPECT().WithTenant("brand-a", nil).Return(tenantShipment)
+	tenantShipment.EXPECT().WithOwner("acme").Return(clientShipment)
+	clientShipment.EXPECT().GetShipmentByID(uint(2)).Return(models.Shipment{Id: 2, FromName: "Mark"}, nil)
+
+	// Init endpoint
+	api := gin.New()
+	api.Use(func(c *gin.Context) {
+		c.Set(clientContextKey, "acme")
+		c.Set(tenantContextKey, "brand-a")
+	})
+	api.GET("/:id", scoped(shipment, tenants, getShipmentByID))
+
+	// Make request
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/2", nil)
+	api.ServeHTTP(w, req)
+
+	// Require
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"FromName":"Mark"`)
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Taras-Rm/shipment/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	// set on the replayed response
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// writer which keeps a copy of the response body
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// replay the saved JSON response of the request with the same Idempotency-Key header
+// (requests without the header are handled as usual)
+func idempotent(idempotencyService services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		// the body is read to compare it with the body of the first request
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid input body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// keys of different clients (and of the clients with the same name in different tenants) don't collide
+		owner := c.GetString(clientContextKey)
+		if tenant := c.GetString(tenantContextKey); tenant != "" {
+			owner = tenant + "/" + owner
+		}
+		saved, replay, err := idempotencyService.StartRequest(owner, key, body)
+		switch {
+		case errors.Is(err, services.ErrorInvalidIdempotencyKey):
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		case errors.Is(err, services.ErrorIdempotencyKeyReused):
+			newErrorResponse(c, http.StatusUnprocessableEntity, err)
+			return
+		case errors.Is(err, services.ErrorIdempotencyKeyInProgress):
+			newErrorResponse(c, http.StatusConflict, err)
+			return
+		case err != nil:
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		if replay {
+			c.Header(idempotentReplayedHeader, "true")
+			c.Data(saved.StatusCode, "application/json; charset=utf-8", saved.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// the key is released if the handler panics
+		defer func() {
+			if p := recover(); p != nil {
+				if err := idempotencyService.FinishRequest(owner, key, http.StatusInternalServerError, nil); err != nil {
+					c.Error(err)
+				}
+				panic(p)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if err := idempotencyService.FinishRequest(owner, key, recorder.Status(), recorder.body.Bytes()); err != nil {
+			c.Error(err)
+		}
+	}
+}
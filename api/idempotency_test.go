@@ -0,0 +1,182 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/services"
+	mock_services "github.com/Taras-Rm/shipment/services/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotent(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockIdempotencyService)
+
+	body := []byte(`{"fromName":"Mark"}`)
+
+	testCases := []struct {
+		name                 string
+		key                  string
+		handlerStatusCode    int
+		mockBehaviur         mockBehaviur
+		expectedHandled      bool
+		expectedStatusCode   int
+		expectedReplayed     string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "without key",
+			handlerStatusCode:    http.StatusCreated,
+			mockBehaviur:         func(r *mock_services.MockIdempotencyService) {},
+			expectedHandled:      true,
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"body":"{\"fromName\":\"Mark\"}"}`,
+		},
+		{
+			name:              "new request",
+			key:               "key-1",
+			handlerStatusCode: http.StatusCreated,
+			mockBehaviur: func(r *mock_services.MockIdempotencyService) {
+				r.EXPECT().StartRequest("acme", "key-1", body).Return(models.IdempotencyKey{}, false, nil)
+				r.EXPECT().FinishRequest("acme", "key-1", http.StatusCreated, []byte(`{"body":"{\"fromName\":\"Mark\"}"}`)).Return(nil)
+			},
+			expectedHandled:      true,
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"body":"{\"fromName\":\"Mark\"}"}`,
+		},
+		{
+			name:              "failed request",
+			key:               "key-1",
+			handlerStatusCode: http.StatusInternalServerError,
+			mockBehaviur: func(r *mock_services.MockIdempotencyService) {
+				r.EXPECT().StartRequest("acme", "key-1", body).Return(models.IdempotencyKey{}, false, nil)
+				r.EXPECT().FinishRequest("acme", "key-1", http.StatusInternalServerError, []byte(`{"body":"{\"fromName\":\"Mark\"}"}`)).Return(nil)
+			},
+			expectedHandled:      true,
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"body":"{\"fromName\":\"Mark\"}"}`,
+		},
+		{
+			name: "replay",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_services.MockIdempotencyService) {
+				r.EXPECT().StartRequest("acme", "key-1", body).Return(models.IdempotencyKey{Key: "key-1", StatusCode: http.StatusCreated, ResponseBody: []byte(`{"id":2}`)}, true, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedReplayed:     "true",
+			expectedResponseBody: `{"id":2}`,
+		},
+		{
+			name: "key reused with another body",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_services.MockIdempotencyService) {
+				r.EXPECT().StartRequest("acme", "key-1", body).Return(models.IdempotencyKey{}, false, services.ErrorIdempotencyKeyReused)
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"idempotency key is already used with another request"}`,
+		},
+		{
+			name: "in progress",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_services.MockIdempotencyService) {
+				r.EXPECT().StartRequest("acme", "key-1", body).Return(models.IdempotencyKey{}, false, services.ErrorIdempotencyKeyInProgress)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"request with the same idempotency key is in progress"}`,
+		},
+		{
+			name: "invalid key",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_services.MockIdempotencyService) {
+				r.EXPECT().StartRequest("acme", "key-1", body).Return(models.IdempotencyKey{}, false, services.ErrorInvalidIdempotencyKey)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid idempotency key"}`,
+		},
+		{
+			name: "some internal error",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_services.MockIdempotencyService) {
+				r.EXPECT().StartRequest("acme", "key-1", body).Return(models.IdempotencyKey{}, false, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			idempotency := mock_services.NewMockIdempotencyService(c)
+			tC.mockBehaviur(idempotency)
+
+			// Init endpoint (the handler echoes the body it gets)
+			handled := false
+			api := gin.New()
+			api.Use(func(c *gin.Context) { c.Set(clientContextKey, "acme") })
+			api.POST("/", idempotent(idempotency), func(c *gin.Context) {
+				handled = true
+				body, err := io.ReadAll(c.Request.Body)
+				require.NoError(t, err)
+				c.JSON(tC.handlerStatusCode, gin.H{"body": string(body)})
+			})
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+			if tC.key != "" {
+				req.Header.Set("Idempotency-Key", tC.key)
+			}
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedHandled, handled)
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedReplayed, w.Header().Get("Idempotent-Replayed"))
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestIdempotent_panic(t *testing.T) {
+	// Init deps
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	idempotency := mock_services.NewMockIdempotencyService(c)
+	idempotency.EXPECT().StartRequest("brand-a/acme", "key-1", []byte(`{}`)).Return(models.IdempotencyKey{}, false, nil)
+	idempotency.EXPECT().FinishRequest("brand-a/acme", "key-1", http.StatusInternalServerError, nil).Return(nil)
+
+	// Init endpoint
+	api := gin.New()
+	api.Use(gin.RecoveryWithWriter(io.Discard))
+	// keys of the client are kept in its tenant
+	api.Use(func(c *gin.Context) {
+		c.Set(clientContextKey, "acme")
+		c.Set(tenantContextKey, "brand-a")
+	})
+	api.POST("/", idempotent(idempotency), func(c *gin.Context) {
+		panic("some panic")
+	})
+
+	// Make request
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{}`))
+	req.Header.Set("Idempotency-Key", "key-1")
+	api.ServeHTTP(w, req)
+
+	// Require
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Taras-Rm/shipment/jwt"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	"github.com/Taras-Rm/shipment/services"
+	"github.com/gin-gonic/gin"
+)
+
+func UseRateCards(gr *gin.RouterGroup, rateCardService services.RateCardService, apiKeyService services.APIKeyService, tokenVerifier jwt.Verifier) {
+	handler := gr.Group("ratecards", authenticate(apiKeyService, tokenVerifier))
+
+	// endpoints (the default rate card, tenants with their own rate cards are not changed)
+	handler.GET("active", authorize(models.PermissionViewShipments), getActiveRateCard(rateCardService))
+	handler.POST("", authorize(models.PermissionManageRateCards), createRateCard(rateCardService))
+}
+
+func getActiveRateCard(rateCardService services.RateCardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"rateCard": rateCardService.GetActiveRateCard(),
+		})
+	}
+}
+
+func createRateCard(rateCardService services.RateCardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var inp models.RateCard
+		if err := bindJSON(c, &inp); err != nil {
+			respondBindError(c, err)
+			return
+		}
+
+		// validate and save a new version of the rate card
+		rateCard, err := rateCardService.CreateRateCard(inp)
+		switch {
+		case errors.Is(err, services.ErrorInvalidRateCard):
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		case errors.Is(err, repositories.ErrorRateCardVersionExists), errors.Is(err, services.ErrorRateCardsNotStored):
+			newErrorResponse(c, http.StatusConflict, err)
+			return
+		case err != nil:
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"rateCard": rateCard,
+		})
+	}
+}
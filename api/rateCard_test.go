@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/jwt"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	"github.com/Taras-Rm/shipment/services"
+	mock_services "github.com/Taras-Rm/shipment/services/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_rateCards(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockRateCardService)
+
+	admin := "Bearer " + testToken(`{"sub":"mark","role":"admin","exp":1641810600}`)
+	operator := "Bearer " + testToken(`{"sub":"iryna","role":"operator","exp":1641810600}`)
+	card := models.RateCard{Version: "2022-02", EffectiveFrom: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC), Currency: "EUR"}
+	cardJSON := `{"version":"2022-02","effectiveFrom":"2022-02-01T00:00:00Z","currency":"EUR","regions":null,"defaultRegion":"","defaultFactor":0,"domesticFactor":0,"zoneMatrix":null,"countryOverrides":null,"weightBrackets":null,"maxWeight":0,"volumetricDivisor":0,"multipliers":null,"returnsFactor":0}`
+
+	testCases := []struct {
+		name                 string
+		method               string
+		authorization        string
+		inputBody            string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "get active",
+			method:        "GET",
+			authorization: operator,
+			mockBehaviur: func(r *mock_services.MockRateCardService) {
+				r.EXPECT().GetActiveRateCard().Return(card)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"rateCard":` + cardJSON + `}`,
+		},
+		{
+			name:          "OK",
+			method:        "POST",
+			authorization: admin,
+			inputBody:     `{"version":"2022-02","effectiveFrom":"2022-02-01T00:00:00Z","currency":"EUR"}`,
+			mockBehaviur: func(r *mock_services.MockRateCardService) {
+				r.EXPECT().CreateRateCard(card).Return(card, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"rateCard":` + cardJSON + `}`,
+		},
+		{
+			name:                 "operator can't create",
+			method:               "POST",
+			authorization:        operator,
+			inputBody:            `{"version":"2022-02"}`,
+			mockBehaviur:         func(r *mock_services.MockRateCardService) {},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"error":"forbidden"}`,
+		},
+		{
+			name:                 "without token",
+			method:               "POST",
+			inputBody:            `{"version":"2022-02"}`,
+			mockBehaviur:         func(r *mock_services.MockRateCardService) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"error":"api key or bearer token is required"}`,
+		},
+		{
+			name:                 "invalid body",
+			method:               "POST",
+			authorization:        admin,
+			inputBody:            `{"version":`,
+			mockBehaviur:         func(r *mock_services.MockRateCardService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:          "invalid rate card",
+			method:        "POST",
+			authorization: admin,
+			inputBody:     `{"version":"2022-02","effectiveFrom":"2022-02-01T00:00:00Z","currency":"EUR"}`,
+			mockBehaviur: func(r *mock_services.MockRateCardService) {
+				r.EXPECT().CreateRateCard(card).Return(models.RateCard{}, fmt.Errorf("%w: %v", services.ErrorInvalidRateCard, errors.New("rate card has no default region")))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid rate card: rate card has no default region"}`,
+		},
+		{
+			name:          "version exists",
+			method:        "POST",
+			authorization: admin,
+			inputBody:     `{"version":"2022-02","effectiveFrom":"2022-02-01T00:00:00Z","currency":"EUR"}`,
+			mockBehaviur: func(r *mock_services.MockRateCardService) {
+				r.EXPECT().CreateRateCard(card).Return(models.RateCard{}, repositories.ErrorRateCardVersionExists)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"rate card version already exists"}`,
+		},
+		{
+			name:          "rate cards are not stored",
+			method:        "POST",
+			authorization: admin,
+			inputBody:     `{"version":"2022-02","effectiveFrom":"2022-02-01T00:00:00Z","currency":"EUR"}`,
+			mockBehaviur: func(r *mock_services.MockRateCardService) {
+				r.EXPECT().CreateRateCard(card).Return(models.RateCard{}, services.ErrorRateCardsNotStored)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"rate cards are not loaded from the db"}`,
+		},
+		{
+			name:          "some internal error",
+			method:        "POST",
+			authorization: admin,
+			inputBody:     `{"version":"2022-02","effectiveFrom":"2022-02-01T00:00:00Z","currency":"EUR"}`,
+			mockBehaviur: func(r *mock_services.MockRateCardService) {
+				r.EXPECT().CreateRateCard(card).Return(models.RateCard{}, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			rateCard := mock_services.NewMockRateCardService(c)
+			tC.mockBehaviur(rateCard)
+
+			apiKey := mock_services.NewMockAPIKeyService(c)
+			tokenVerifier := jwt.InitVerifier(jwt.Keys{Secret: []byte("test-secret")}, "", "", func() time.Time { return testTokenNow })
+
+			// Init endpoint
+			api := gin.New()
+			UseRateCards(api.Group("api"), rateCard, apiKey, tokenVerifier)
+
+			// Create request
+			w := httptest.NewRecorder()
+			path := "/api/ratecards"
+			if tC.method == "GET" {
+				path += "/active"
+			}
+			req := httptest.NewRequest(tC.method, path, bytes.NewBufferString(tC.inputBody))
+			if tC.authorization != "" {
+				req.Header.Set("Authorization", tC.authorization)
+			}
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -1,15 +1,29 @@
 package api
 
 import (
+	"errors"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/services"
 	"github.com/gin-gonic/gin"
 )
 
 type errorResponse struct {
 	Error string `json:"error"`
+	// every invalid field of the request
+	Fields []models.FieldError `json:"fields,omitempty"`
 }
 
 func newErrorResponse(c *gin.Context, status int, err error) {
-	c.AbortWithStatusJSON(status, errorResponse{
+	response := errorResponse{
 		Error: err.Error(),
-	})
+	}
+
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		response.Error = "invalid fields"
+		response.Fields = validationErr.Fields
+	}
+
+	c.AbortWithStatusJSON(status, response)
 }
@@ -2,33 +2,160 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/Taras-Rm/shipment/fx"
+	"github.com/Taras-Rm/shipment/jwt"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/presenters"
+	"github.com/Taras-Rm/shipment/pricing"
+	"github.com/Taras-Rm/shipment/repositories"
 	"github.com/Taras-Rm/shipment/services"
+	"github.com/Taras-Rm/shipment/tracking"
 	"github.com/gin-gonic/gin"
 )
 
-func UseShipment(gr *gin.RouterGroup, shipmentService services.ShipmentService) {
-	handler := gr.Group("shipment")
+func UseShipment(gr *gin.RouterGroup, shipmentService services.ShipmentService, idempotencyService services.IdempotencyService, apiKeyService services.APIKeyService, tokenVerifier jwt.Verifier, tenantService services.TenantService) {
+	handler := gr.Group("shipment", authenticate(apiKeyService, tokenVerifier), resolveTenant(tenantService, true))
+	view := authorize(models.PermissionViewShipments)
+	manage := authorize(models.PermissionManageShipments)
+	scope := func(h func(services.ShipmentService) gin.HandlerFunc) gin.HandlerFunc {
+		return scoped(shipmentService, tenantService, h)
+	}
+
+	// endpoints (a client works only with the shipments it created in its tenant)
+	handler.GET("", view, scope(listShipments))
+	handler.POST("", manage, idempotent(idempotencyService), scope(addShipment))
+	handler.POST("batch", manage, scope(addShipments))
+	handler.POST("import", manage, scope(importShipments))
+	handler.POST("quote", view, scope(quoteShipment))
+	handler.GET("search", view, scope(searchShipments))
+	handler.GET("export", view, scope(exportShipments))
+	handler.GET("tracking/:number", view, scope(getShipmentByTrackingNumber))
+	handler.GET(":id", view, scope(getShipmentByID))
+	handler.PUT(":id", manage, scope(updateShipment))
+	handler.PATCH(":id", manage, scope(patchShipment))
+	handler.POST(":id/transitions", manage, scope(transitionShipment))
+	handler.POST(":id/cancel", manage, scope(cancelShipment))
+	handler.POST(":id/return", manage, scope(returnShipment))
+	handler.GET(":id/events", view, scope(getTrackingEvents))
+	handler.POST(":id/events", manage, scope(addTrackingEvent))
+}
+
+func listShipments(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var inp services.ListShipmentsInput
+		if err := c.ShouldBindQuery(&inp); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid query parameters"))
+			return
+		}
+
+		// validate filters, sort and page size
+		if err := inp.Validate(); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// get a page of shipments
+		page, err := shipmentService.ListShipments(inp)
+		if errors.Is(err, repositories.ErrorInvalidCursor) {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
 
-	// endpoints
-	handler.GET("", getAllShipments(shipmentService))
-	handler.POST("", addShipment(shipmentService))
-	handler.GET(":id", getShipmentByID(shipmentService))
+		c.JSON(http.StatusOK, page)
+	}
 }
 
-func getAllShipments(shipmentService services.ShipmentService) gin.HandlerFunc {
+func exportShipments(shipmentService services.ShipmentService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// get all shipments
-		shipments, err := shipmentService.GetAllShipments()
+		var inp services.ListShipmentsInput
+		if err := c.ShouldBindQuery(&inp); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid query parameters"))
+			return
+		}
+
+		// validate filters and sort (all pages are exported)
+		inp.Limit = 0
+		if err := inp.Validate(); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		format := c.DefaultQuery("format", "csv")
+		writer, contentType, err := presenters.NewShipmentWriter(format, c.Writer)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// the response is started with the first shipment, so an error before it is still returned as JSON
+		started := false
+		start := func() {
+			if started {
+				return
+			}
+			started = true
+			c.Header("Content-Type", contentType)
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="shipments.%s"`, format))
+			c.Status(http.StatusOK)
+		}
+
+		// shipments are written while they are read from the database
+		err = shipmentService.ExportShipments(inp, func(shipment models.Shipment) error {
+			start()
+			return writer.Write(shipment)
+		})
+		if errors.Is(err, repositories.ErrorInvalidCursor) && !started {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil && !started {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+		if err == nil {
+			start()
+			err = writer.Flush()
+		}
+
+		// the export is cut off if it fails in the middle
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+		}
+	}
+}
+
+func searchShipments(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var inp services.SearchShipmentsInput
+		if err := c.ShouldBindQuery(&inp); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid query parameters"))
+			return
+		}
+
+		// validate search text and page size
+		if err := inp.Validate(); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// find shipments (the best matches first)
+		results, err := shipmentService.SearchShipments(inp)
 		if err != nil {
 			newErrorResponse(c, http.StatusInternalServerError, err)
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"shipments": shipments,
+			"results": results,
 		})
 	}
 }
@@ -36,26 +163,163 @@ func getAllShipments(shipmentService services.ShipmentService) gin.HandlerFunc {
 func addShipment(shipmentService services.ShipmentService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var inp services.AddShipmentInput
-		if err := c.BindJSON(&inp); err != nil {
-			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid input body"))
+		if err := bindJSON(c, &inp); err != nil {
+			respondBindError(c, err)
 			return
 		}
 
 		// validate add shipment request
 		if err := inp.Validate(); err != nil {
-			newErrorResponse(c, http.StatusBadRequest, err)
+			newErrorResponse(c, http.StatusUnprocessableEntity, err)
 			return
 		}
 
 		// add new shipment to database
-		price, err := shipmentService.AddShipment(inp)
+		shipment, err := shipmentService.AddShipment(inp)
+		if errors.Is(err, pricing.ErrorWeightNotSupported) || errors.Is(err, fx.ErrorRateNotFound) {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
 		if err != nil {
 			newErrorResponse(c, http.StatusInternalServerError, err)
 			return
 		}
 
 		c.JSON(http.StatusCreated, gin.H{
-			"price": price,
+			"id":             shipment.Id,
+			"trackingNumber": shipment.TrackingNumber,
+			"price":          shipment.Price,
+		})
+	}
+}
+
+func addShipments(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var inp services.AddShipmentsInput
+		if err := bindJSON(c, &inp); err != nil {
+			respondBindError(c, err)
+			return
+		}
+
+		// all-or-nothing mode
+		atomic, err := strconv.ParseBool(c.DefaultQuery("atomic", "false"))
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid query parameters"))
+			return
+		}
+
+		// validate batch size
+		if err := inp.Validate(); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// add valid shipments of the batch
+		results, err := shipmentService.AddShipments(inp, atomic)
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		created := 0
+		for _, result := range results {
+			if result.Error == "" {
+				created++
+			}
+		}
+
+		// some of the shipments may be not created
+		status := http.StatusMultiStatus
+		switch created {
+		case len(results):
+			status = http.StatusCreated
+		case 0:
+			status = http.StatusUnprocessableEntity
+		}
+
+		c.JSON(status, gin.H{
+			"created": created,
+			"failed":  len(results) - created,
+			"results": results,
+		})
+	}
+}
+
+func importShipments(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// CSV file is uploaded as multipart form
+		header, err := c.FormFile("file")
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("file is required"))
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+		defer file.Close()
+
+		// add shipments of the valid rows
+		result, err := shipmentService.ImportShipments(file)
+		if errors.Is(err, services.ErrorInvalidImportFile) {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		// some of the rows may be not created
+		status := http.StatusMultiStatus
+		switch {
+		case result.Failed == 0:
+			status = http.StatusCreated
+		case result.Created == 0:
+			status = http.StatusUnprocessableEntity
+		}
+
+		// errors of the rows are downloaded as CSV report
+		c.Header("Content-Disposition", `attachment; filename="import-report.csv"`)
+		c.Header("X-Import-Created", strconv.Itoa(result.Created))
+		c.Header("X-Import-Failed", strconv.Itoa(result.Failed))
+		c.Header("Content-Type", "text/csv")
+		c.Status(status)
+		if err := presenters.WriteImportReport(c.Writer, result.Errors); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+func quoteShipment(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var inp services.AddShipmentInput
+		if err := bindJSON(c, &inp); err != nil {
+			respondBindError(c, err)
+			return
+		}
+
+		// validate and price the shipment (nothing is saved)
+		breakdown, pieces, err := shipmentService.Quote(inp)
+		var validationErr *services.ValidationError
+		if errors.As(err, &validationErr) {
+			newErrorResponse(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if errors.Is(err, pricing.ErrorWeightNotSupported) || errors.Is(err, fx.ErrorRateNotFound) {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"price":     breakdown.Total,
+			"breakdown": breakdown,
+			"pieces":    pieces,
 		})
 	}
 }
@@ -72,11 +336,287 @@ func getShipmentByID(shipmentService services.ShipmentService) gin.HandlerFunc {
 
 		// get shipment by ID
 		shipment, err := shipmentService.GetShipmentByID(uint(shipmentId))
+		if errors.Is(err, repositories.ErrorShipmentNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err)
+			return
+		}
 		if err != nil {
 			newErrorResponse(c, http.StatusInternalServerError, err)
 			return
 		}
 
+		// show amounts in the requested currency
+		currency := c.Query("currency")
+		if currency == "" {
+			c.JSON(http.StatusOK, gin.H{
+				"shipment": shipment,
+			})
+			return
+		}
+
+		shipment, rate, err := shipmentService.ConvertShipment(shipment, currency)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"shipment": shipment,
+			"fxRate":   rate,
+		})
+	}
+}
+
+func updateShipment(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// get ID param
+		id := c.Param("id")
+		shipmentId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var inp services.AddShipmentInput
+		if err := bindJSON(c, &inp); err != nil {
+			respondBindError(c, err)
+			return
+		}
+
+		// replace all data of the shipment
+		shipment, err := shipmentService.UpdateShipment(uint(shipmentId), inp)
+		respondUpdatedShipment(c, shipment, err)
+	}
+}
+
+func patchShipment(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// get ID param
+		id := c.Param("id")
+		shipmentId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var inp services.PatchShipmentInput
+		if err := bindJSON(c, &inp); err != nil {
+			respondBindError(c, err)
+			return
+		}
+
+		// change only the given fields
+		shipment, err := shipmentService.PatchShipment(uint(shipmentId), inp)
+		respondUpdatedShipment(c, shipment, err)
+	}
+}
+
+func respondUpdatedShipment(c *gin.Context, shipment models.Shipment, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		newErrorResponse(c, http.StatusUnprocessableEntity, err)
+		return
+	case errors.Is(err, pricing.ErrorWeightNotSupported), errors.Is(err, fx.ErrorRateNotFound):
+		newErrorResponse(c, http.StatusBadRequest, err)
+		return
+	case errors.Is(err, repositories.ErrorShipmentNotFound):
+		newErrorResponse(c, http.StatusNotFound, err)
+		return
+	case errors.Is(err, services.ErrorShipmentNotEditable), errors.Is(err, repositories.ErrorStatusConflict):
+		newErrorResponse(c, http.StatusConflict, err)
+		return
+	case err != nil:
+		newErrorResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shipment": shipment,
+	})
+}
+
+func transitionShipment(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// get ID param
+		id := c.Param("id")
+		shipmentId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var inp services.TransitionShipmentInput
+		if err := c.BindJSON(&inp); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid input body"))
+			return
+		}
+
+		// move shipment to the requested status
+		shipment, err := shipmentService.TransitionShipment(uint(shipmentId), inp.Status)
+		switch {
+		case errors.Is(err, services.ErrorUnknownStatus):
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		case errors.Is(err, repositories.ErrorShipmentNotFound):
+			newErrorResponse(c, http.StatusNotFound, err)
+			return
+		case errors.Is(err, services.ErrorIllegalTransition), errors.Is(err, repositories.ErrorStatusConflict):
+			newErrorResponse(c, http.StatusConflict, err)
+			return
+		case err != nil:
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"shipment": shipment,
+		})
+	}
+}
+
+func cancelShipment(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// get ID param
+		id := c.Param("id")
+		shipmentId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// cancel shipment and calculate its refund
+		shipment, err := shipmentService.CancelShipment(uint(shipmentId))
+		switch {
+		case errors.Is(err, repositories.ErrorShipmentNotFound):
+			newErrorResponse(c, http.StatusNotFound, err)
+			return
+		case errors.Is(err, services.ErrorIllegalTransition), errors.Is(err, repositories.ErrorStatusConflict):
+			newErrorResponse(c, http.StatusConflict, err)
+			return
+		case err != nil:
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"shipment": shipment,
+		})
+	}
+}
+
+func returnShipment(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// get ID param
+		id := c.Param("id")
+		shipmentId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// create reverse shipment linked to the original one
+		shipment, err := shipmentService.ReturnShipment(uint(shipmentId))
+		switch {
+		case errors.Is(err, repositories.ErrorShipmentNotFound):
+			newErrorResponse(c, http.StatusNotFound, err)
+			return
+		case errors.Is(err, services.ErrorReturnNotAllowed):
+			newErrorResponse(c, http.StatusConflict, err)
+			return
+		case err != nil:
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"shipment": shipment,
+		})
+	}
+}
+
+func getTrackingEvents(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// get ID param
+		id := c.Param("id")
+		shipmentId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// get events of the shipment (oldest first)
+		events, err := shipmentService.GetTrackingEvents(uint(shipmentId))
+		if errors.Is(err, repositories.ErrorShipmentNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err)
+			return
+		}
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"events": events,
+		})
+	}
+}
+
+func addTrackingEvent(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// get ID param
+		id := c.Param("id")
+		shipmentId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var inp services.AddTrackingEventInput
+		if err := c.BindJSON(&inp); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, errors.New("invalid input body"))
+			return
+		}
+
+		// validate add event request
+		if err := inp.Validate(); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// append event to the shipment
+		event, err := shipmentService.AddTrackingEvent(uint(shipmentId), inp)
+		if errors.Is(err, repositories.ErrorShipmentNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err)
+			return
+		}
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"event": event,
+		})
+	}
+}
+
+func getShipmentByTrackingNumber(shipmentService services.ShipmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// get shipment by tracking number
+		shipment, err := shipmentService.GetShipmentByTrackingNumber(c.Param("number"))
+		switch {
+		case errors.Is(err, tracking.ErrorInvalidTrackingNumber):
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		case errors.Is(err, repositories.ErrorShipmentNotFound):
+			newErrorResponse(c, http.StatusNotFound, err)
+			return
+		case err != nil:
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
 		c.JSON(http.StatusOK, gin.H{
 			"shipment": shipment,
 		})
@@ -4,14 +4,22 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/Taras-Rm/shipment/fx"
 	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/Taras-Rm/shipment/pricing"
+	"github.com/Taras-Rm/shipment/repositories"
 	"github.com/Taras-Rm/shipment/services"
 	mock_services "github.com/Taras-Rm/shipment/services/mocks"
+	"github.com/Taras-Rm/shipment/tracking"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -23,6 +31,7 @@ func TestHandler_getShipmentById(t *testing.T) {
 	testCases := []struct {
 		name                 string
 		inputId              uint
+		query                string
 		outputShipment       models.Shipment
 		mockBehaviur         mockBehaviur
 		expectedStatusCode   int
@@ -42,13 +51,99 @@ func TestHandler_getShipmentById(t *testing.T) {
 				ToAddress:       "Toronto, 34",
 				ToCountryCode:   "CA",
 				Weight:          234.4,
-				Price:           99.99,
+				Price:           money.New(9999, "EUR"),
 			},
 			mockBehaviur: func(r *mock_services.MockShipmentService, id uint, shipment models.Shipment) {
 				r.EXPECT().GetShipmentByID(gomock.Eq(id)).Return(shipment, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"shipment":{"Id":2,"FromName":"Mark","FromEmail":"testFrom@g.c","FromAddress":"Lviv, 45","FromCountryCode":"UA","ToName":"Iryna","ToEmail":"testTo@g.c","ToAddress":"Toronto, 34","ToCountryCode":"CA","Weight":234.4,"Price":99.99}}`,
+			expectedResponseBody: `{"shipment":{"Id":2,"FromName":"Mark","FromEmail":"testFrom@g.c","FromAddress":"Lviv, 45","FromCountryCode":"UA","ToName":"Iryna","ToEmail":"testTo@g.c","ToAddress":"Toronto, 34","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"99.99","currency":"EUR"}}}`,
+		},
+		{
+			name:    "OK with price breakdown",
+			inputId: 2,
+			outputShipment: models.Shipment{
+				Id:              2,
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+				Price:           money.New(500000, "EUR"),
+				PriceBreakdown: &models.PriceBreakdown{
+					RateCardVersion: "default",
+					Lane:            models.Lane{Type: models.LaneIntercontinental, Origin: "europe", Destination: "world", Factor: 2.5},
+					BillableWeight:  234.4,
+					Components: []models.PriceComponent{
+						{Type: models.PriceComponentBase, Name: "weight class", Amount: money.New(200000, "EUR")},
+						{Type: models.PriceComponentLane, Name: "intercontinental", Factor: 2.5, Amount: money.New(300000, "EUR")},
+					},
+					Total: money.New(500000, "EUR"),
+				},
+			},
+			mockBehaviur: func(r *mock_services.MockShipmentService, id uint, shipment models.Shipment) {
+				r.EXPECT().GetShipmentByID(gomock.Eq(id)).Return(shipment, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"shipment":{"Id":2,"FromName":"Mark","FromEmail":"testFrom@g.c","FromAddress":"Lviv, 45","FromCountryCode":"UA","ToName":"Iryna","ToEmail":"testTo@g.c","ToAddress":"Toronto, 34","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"5000.00","currency":"EUR"},"PriceBreakdown":{"rateCardVersion":"default","lane":{"type":"intercontinental","origin":"europe","destination":"world","factor":2.5},"billableWeight":234.4,"components":[{"type":"base","name":"weight class","amount":{"amount":"2000.00","currency":"EUR"}},{"type":"lane","name":"intercontinental","factor":2.5,"amount":{"amount":"3000.00","currency":"EUR"}}],"total":{"amount":"5000.00","currency":"EUR"}}}}`,
+		},
+		{
+			name:    "OK in requested currency",
+			inputId: 2,
+			query:   "?currency=SEK",
+			outputShipment: models.Shipment{
+				Id:              2,
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+				Price:           money.New(9999, "EUR"),
+			},
+			mockBehaviur: func(r *mock_services.MockShipmentService, id uint, shipment models.Shipment) {
+				converted := shipment
+				converted.Price = money.New(104557, "SEK")
+
+				r.EXPECT().GetShipmentByID(gomock.Eq(id)).Return(shipment, nil)
+				r.EXPECT().ConvertShipment(gomock.Eq(shipment), gomock.Eq("SEK")).Return(converted, models.FxRate{
+					From:          "EUR",
+					To:            "SEK",
+					Rate:          10.4567,
+					EffectiveFrom: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"fxRate":{"from":"EUR","to":"SEK","rate":10.4567,"effectiveFrom":"2022-01-01T00:00:00Z"},"shipment":{"Id":2,"FromName":"Mark","FromEmail":"testFrom@g.c","FromAddress":"Lviv, 45","FromCountryCode":"UA","ToName":"Iryna","ToEmail":"testTo@g.c","ToAddress":"Toronto, 34","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"1045.57","currency":"SEK"}}}`,
+		},
+		{
+			name:           "no fx rate for requested currency",
+			inputId:        2,
+			query:          "?currency=USD",
+			outputShipment: models.Shipment{Id: 2, Price: money.New(9999, "EUR")},
+			mockBehaviur: func(r *mock_services.MockShipmentService, id uint, shipment models.Shipment) {
+				r.EXPECT().GetShipmentByID(gomock.Eq(id)).Return(shipment, nil)
+				r.EXPECT().ConvertShipment(gomock.Eq(shipment), gomock.Eq("USD")).Return(models.Shipment{}, models.FxRate{}, errors.New("EUR/USD: fx rate not found"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"EUR/USD: fx rate not found"}`,
+		},
+		{
+			name:           "shipment not found",
+			inputId:        2,
+			outputShipment: models.Shipment{},
+			mockBehaviur: func(r *mock_services.MockShipmentService, id uint, shipment models.Shipment) {
+				r.EXPECT().GetShipmentByID(gomock.Eq(id)).Return(shipment, repositories.ErrorShipmentNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"shipment not found"}`,
 		},
 		{
 			name:           "some internal error",
@@ -77,7 +172,7 @@ func TestHandler_getShipmentById(t *testing.T) {
 
 			// Create request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("GET", fmt.Sprintf("/%d", tC.inputId), bytes.NewBufferString(""))
+			req := httptest.NewRequest("GET", fmt.Sprintf("/%d%s", tC.inputId, tC.query), bytes.NewBufferString(""))
 
 			// Make request
 			api.ServeHTTP(w, req)
@@ -89,65 +184,194 @@ func TestHandler_getShipmentById(t *testing.T) {
 	}
 }
 
-func TestHandler_getAllShipments(t *testing.T) {
-	type mockBehaviur func(r *mock_services.MockShipmentService, shipments []models.Shipment)
+func TestHandler_listShipments(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
 
 	testCases := []struct {
 		name                 string
-		outputShipments      []models.Shipment
+		query                string
 		mockBehaviur         mockBehaviur
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
 			name: "OK",
-			outputShipments: []models.Shipment{
-				{
-					Id:              2,
-					FromName:        "Mark",
-					FromEmail:       "testFrom@g.c",
-					FromAddress:     "Lviv, 45",
-					FromCountryCode: "UA",
-					ToName:          "Iryna",
-					ToEmail:         "testTo@g.c",
-					ToAddress:       "Toronto, 34",
-					ToCountryCode:   "CA",
-					Weight:          234.4,
-					Price:           99.99,
-				},
-				{
-					Id:              3,
-					FromName:        "Tom",
-					FromEmail:       "testFrom@g.c",
-					FromAddress:     "Lutsk, 34",
-					FromCountryCode: "UA",
-					ToName:          "Viktor",
-					ToEmail:         "testTo@g.c",
-					ToAddress:       "London, 32",
-					ToCountryCode:   "UK",
-					Weight:          5,
-					Price:           234.78,
-				},
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ListShipments(services.ListShipmentsInput{}).Return(models.ShipmentPage{
+					Shipments: []models.Shipment{
+						{
+							Id:              2,
+							FromName:        "Mark",
+							FromEmail:       "testFrom@g.c",
+							FromAddress:     "Lviv, 45",
+							FromCountryCode: "UA",
+							ToName:          "Iryna",
+							ToEmail:         "testTo@g.c",
+							ToAddress:       "Toronto, 34",
+							ToCountryCode:   "CA",
+							Weight:          234.4,
+							Price:           money.New(9999, "EUR"),
+						},
+						{
+							Id:              3,
+							FromName:        "Tom",
+							FromEmail:       "testFrom@g.c",
+							FromAddress:     "Lutsk, 34",
+							FromCountryCode: "UA",
+							ToName:          "Viktor",
+							ToEmail:         "testTo@g.c",
+							ToAddress:       "London, 32",
+							ToCountryCode:   "UK",
+							Weight:          5,
+							Price:           money.New(23478, "EUR"),
+						},
+					},
+					Total: 2,
+				}, nil)
 			},
-			mockBehaviur: func(r *mock_services.MockShipmentService, shipments []models.Shipment) {
-				r.EXPECT().GetAllShipments().Return(shipments, nil)
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"shipments":[{"Id":2,"FromName":"Mark","FromEmail":"testFrom@g.c","FromAddress":"Lviv, 45","FromCountryCode":"UA","ToName":"Iryna","ToEmail":"testTo@g.c","ToAddress":"Toronto, 34","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"99.99","currency":"EUR"}},{"Id":3,"FromName":"Tom","FromEmail":"testFrom@g.c","FromAddress":"Lutsk, 34","FromCountryCode":"UA","ToName":"Viktor","ToEmail":"testTo@g.c","ToAddress":"London, 32","ToCountryCode":"UK","Weight":5,"Price":{"amount":"234.78","currency":"EUR"}}],"total":2}`,
+		},
+		{
+			name:  "OK with filters and next page",
+			query: "?limit=1&sort=-price&fromCountryCode=UA&minPrice=50&priceCurrency=EUR&createdFrom=2022-01-01T00:00:00Z",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ListShipments(services.ListShipmentsInput{
+					Limit:           1,
+					Sort:            "-price",
+					FromCountryCode: "UA",
+					MinPrice:        "50",
+					PriceCurrency:   "EUR",
+					CreatedFrom:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				}).Return(models.ShipmentPage{
+					Shipments:  []models.Shipment{{Id: 2, FromCountryCode: "UA", ToCountryCode: "CA", Weight: 234.4, Price: money.New(500000, "EUR")}},
+					NextCursor: "eyJzIjoicHJpY2UiLCJkIjp0cnVlLCJ2Ijo1MDAwMDAsImlkIjoyfQ",
+					Total:      7,
+				}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"shipments":[{"Id":2,"FromName":"Mark","FromEmail":"testFrom@g.c","FromAddress":"Lviv, 45","FromCountryCode":"UA","ToName":"Iryna","ToEmail":"testTo@g.c","ToAddress":"Toronto, 34","ToCountryCode":"CA","Weight":234.4,"Price":99.99},{"Id":3,"FromName":"Tom","FromEmail":"testFrom@g.c","FromAddress":"Lutsk, 34","FromCountryCode":"UA","ToName":"Viktor","ToEmail":"testTo@g.c","ToAddress":"London, 32","ToCountryCode":"UK","Weight":5,"Price":234.78}]}`,
+			expectedResponseBody: `{"shipments":[{"Id":2,"FromName":"","FromEmail":"","FromAddress":"","FromCountryCode":"UA","ToName":"","ToEmail":"","ToAddress":"","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"5000.00","currency":"EUR"}}],"nextCursor":"eyJzIjoicHJpY2UiLCJkIjp0cnVlLCJ2Ijo1MDAwMDAsImlkIjoyfQ","total":7}`,
 		},
 		{
-			name:            "without shipments",
-			outputShipments: []models.Shipment{},
-			mockBehaviur: func(r *mock_services.MockShipmentService, shipments []models.Shipment) {
-				r.EXPECT().GetAllShipments().Return(shipments, nil)
+			name: "without shipments",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ListShipments(services.ListShipmentsInput{}).Return(models.ShipmentPage{Shipments: []models.Shipment{}}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"shipments":[]}`,
+			expectedResponseBody: `{"shipments":[],"total":0}`,
+		},
+		{
+			name:                 "invalid sort",
+			query:                "?sort=name",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid sort"}`,
+		},
+		{
+			name:                 "not a number limit",
+			query:                "?limit=ten",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:  "invalid cursor",
+			query: "?cursor=abc",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ListShipments(services.ListShipmentsInput{Cursor: "abc"}).Return(models.ShipmentPage{}, repositories.ErrorInvalidCursor)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid cursor"}`,
 		},
 		{
 			name: "some internal error",
-			mockBehaviur: func(r *mock_services.MockShipmentService, shipments []models.Shipment) {
-				r.EXPECT().GetAllShipments().Return(shipments, errors.New("some internal error"))
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ListShipments(services.ListShipmentsInput{}).Return(models.ShipmentPage{}, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.GET("", listShipments(shipment))
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/"+tC.query, bytes.NewBufferString(""))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_searchShipments(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			query: "?q=mark&limit=5",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().SearchShipments(services.SearchShipmentsInput{Q: "mark", Limit: 5}).Return([]models.SearchResult{
+					{
+						Shipment:   models.Shipment{Id: 2, FromName: "Mark", FromCountryCode: "UA", ToCountryCode: "CA", Weight: 5, Price: money.New(9999, "EUR")},
+						Rank:       0.6,
+						Highlights: map[string]string{"fromName": "<mark>Mark</mark>"},
+					},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"results":[{"shipment":{"Id":2,"FromName":"Mark","FromEmail":"","FromAddress":"","FromCountryCode":"UA","ToName":"","ToEmail":"","ToAddress":"","ToCountryCode":"CA","Weight":5,"Price":{"amount":"99.99","currency":"EUR"}},"rank":0.6,"highlights":{"fromName":"\u003cmark\u003eMark\u003c/mark\u003e"}}]}`,
+		},
+		{
+			name:  "nothing found",
+			query: "?q=paris",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().SearchShipments(services.SearchShipmentsInput{Q: "paris"}).Return([]models.SearchResult{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"results":[]}`,
+		},
+		{
+			name:                 "without search text",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:                 "without words",
+			query:                "?q=%21%21",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid search query"}`,
+		},
+		{
+			name:  "some internal error",
+			query: "?q=mark",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().SearchShipments(services.SearchShipmentsInput{Q: "mark"}).Return(nil, errors.New("some internal error"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
 			expectedResponseBody: `{"error":"some internal error"}`,
@@ -161,15 +385,15 @@ func TestHandler_getAllShipments(t *testing.T) {
 			defer c.Finish()
 
 			shipment := mock_services.NewMockShipmentService(c)
-			tC.mockBehaviur(shipment, tC.outputShipments)
+			tC.mockBehaviur(shipment)
 
 			// Init endpoint
 			api := gin.New()
-			api.GET("", getAllShipments(shipment))
+			api.GET("/search", searchShipments(shipment))
 
 			// Create request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("GET", "/", bytes.NewBufferString(""))
+			req := httptest.NewRequest("GET", "/search"+tC.query, bytes.NewBufferString(""))
 
 			// Make request
 			api.ServeHTTP(w, req)
@@ -207,80 +431,164 @@ func TestHandler_addShipment(t *testing.T) {
 				Weight:          234.4,
 			},
 			mockBehaviur: func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {
-				r.EXPECT().AddShipment(gomock.Eq(shipment)).Return(1000.5, nil)
+				r.EXPECT().AddShipment(gomock.Eq(shipment)).Return(models.Shipment{Id: 1, TrackingNumber: "SH169090604UA", Price: money.New(100050, "EUR")}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id":1,"price":{"amount":"1000.50","currency":"EUR"},"trackingNumber":"SH169090604UA"}`,
+		},
+		{
+			name:        "billable weight over the max weight of the rate card",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			inputShipment: services.AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+			},
+			mockBehaviur: func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {
+				r.EXPECT().AddShipment(gomock.Eq(shipment)).Return(models.Shipment{}, pricing.ErrorWeightNotSupported)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"weight is not supported by rate card"}`,
+		},
+		{
+			name:        "no fx rate of the currency",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			inputShipment: services.AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+			},
+			mockBehaviur: func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {
+				r.EXPECT().AddShipment(gomock.Eq(shipment)).Return(models.Shipment{}, fmt.Errorf("%s/%s: %w", "EUR", "SEK", fx.ErrorRateNotFound))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"EUR/SEK: fx rate not found"}`,
+		},
+		{
+			name:        "OK with pieces",
+			fixturePath: "./fixtures/shipments/add.pieces.json",
+			inputShipment: services.AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Pieces: []services.PieceInput{
+					{Weight: 5},
+					{Weight: 2, Length: 100, Width: 50, Height: 40},
+				},
+			},
+			mockBehaviur: func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {
+				r.EXPECT().AddShipment(gomock.Eq(shipment)).Return(models.Shipment{Id: 1, TrackingNumber: "SH169090604UA", Price: money.New(150000, "EUR")}, nil)
 			},
 			expectedStatusCode:   http.StatusCreated,
-			expectedResponseBody: `{"price":1000.5}`,
+			expectedResponseBody: `{"id":1,"price":{"amount":"1500.00","currency":"EUR"},"trackingNumber":"SH169090604UA"}`,
+		},
+		{
+			name:                 "Missing weight of piece",
+			fixturePath:          "./fixtures/shipments/add.no_pieceWeight.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"pieces[1].weight","code":"required","message":"is required"}]}`,
 		},
 		{
 			name:                 "Missing fromName",
 			fixturePath:          "./fixtures/shipments/add.no_fromName.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"fromName","code":"required","message":"is required"}]}`,
 		},
 		{
 			name:                 "Missing fromEmail",
 			fixturePath:          "./fixtures/shipments/add.no_fromEmail.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"fromEmail","code":"required","message":"is required"}]}`,
 		},
 		{
 			name:                 "Missing fromAddress",
 			fixturePath:          "./fixtures/shipments/add.no_fromAddress.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"fromAddress","code":"required","message":"is required"}]}`,
 		},
 		{
 			name:                 "Missing fromCountryCode",
 			fixturePath:          "./fixtures/shipments/add.no_fromCountryCode.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"fromCountryCode","code":"required","message":"is required"}]}`,
 		},
 		{
 			name:                 "Missing toName",
 			fixturePath:          "./fixtures/shipments/add.no_toName.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"toName","code":"required","message":"is required"}]}`,
 		},
 		{
 			name:                 "Missing toEmail",
 			fixturePath:          "./fixtures/shipments/add.no_toEmail.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"toEmail","code":"required","message":"is required"}]}`,
 		},
 		{
 			name:                 "Missing toAddress",
 			fixturePath:          "./fixtures/shipments/add.no_toAddress.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"toAddress","code":"required","message":"is required"}]}`,
 		},
 		{
 			name:                 "Missing toCountryCode",
 			fixturePath:          "./fixtures/shipments/add.no_toCountryCode.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"toCountryCode","code":"required","message":"is required"}]}`,
 		},
 		{
 			name:                 "Missing weight",
 			fixturePath:          "./fixtures/shipments/add.no_weight.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"weight","code":"required","message":"is required"}]}`,
+		},
+		{
+			name:                 "Invalid weight of piece",
+			fixturePath:          "./fixtures/shipments/add.invalid_pieceWeight.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"pieces[1].weight","code":"invalid_type","message":"must be a number"}]}`,
+		},
+		{
+			name:                 "Several invalid fields",
+			fixturePath:          "./fixtures/shipments/add.invalid_fields.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"fromEmail","code":"invalid_email","message":"invalid email"},{"path":"toEmail","code":"required","message":"is required"},{"path":"pieces[0].height","code":"invalid_dimensions","message":"invalid dimensions"}]}`,
 		},
 		{
 			name:                 "Invalid weight",
 			fixturePath:          "./fixtures/shipments/add.invalid_weight.json",
 			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid input body"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"weight","code":"invalid_type","message":"must be a number"}]}`,
 		},
 	}
 
@@ -314,3 +622,1335 @@ func TestHandler_addShipment(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_addShipments(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	// input of the batch.ok.json fixture
+	input := services.AddShipmentsInput{
+		{
+			FromName:        "Mark",
+			FromEmail:       "testFrom@g.c",
+			FromAddress:     "Lviv, 45",
+			FromCountryCode: "UA",
+			ToName:          "Iryna",
+			ToEmail:         "testTo@g.c",
+			ToAddress:       "Toronto, 34",
+			ToCountryCode:   "CA",
+			Weight:          234.4,
+		},
+		{
+			FromName:        "Tom",
+			FromEmail:       "testFrom@g.c",
+			FromAddress:     "Lutsk, 34",
+			FromCountryCode: "UA",
+			ToName:          "Viktor",
+			ToEmail:         "test",
+			ToAddress:       "London, 32",
+			ToCountryCode:   "GB",
+			Weight:          5,
+		},
+	}
+	price := money.New(500000, "EUR")
+
+	testCases := []struct {
+		name                 string
+		query                string
+		fixturePath          string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "all created",
+			fixturePath: "./fixtures/shipments/batch.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().AddShipments(input, false).Return([]models.ShipmentBatchResult{
+					{Index: 0, Id: 1, TrackingNumber: "SH169090604UA", Price: &price},
+					{Index: 1, Id: 2, TrackingNumber: "SH169090618UA", Price: &price},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"created":2,"failed":0,"results":[{"index":0,"id":1,"trackingNumber":"SH169090604UA","price":{"amount":"5000.00","currency":"EUR"}},{"index":1,"id":2,"trackingNumber":"SH169090618UA","price":{"amount":"5000.00","currency":"EUR"}}]}`,
+		},
+		{
+			name:        "partially created",
+			fixturePath: "./fixtures/shipments/batch.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().AddShipments(input, false).Return([]models.ShipmentBatchResult{
+					{Index: 0, Id: 1, TrackingNumber: "SH169090604UA", Price: &price},
+					{Index: 1, Error: "toEmail: invalid email", Fields: []models.FieldError{{Path: "toEmail", Code: services.CodeInvalidEmail, Message: "invalid email"}}},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusMultiStatus,
+			expectedResponseBody: `{"created":1,"failed":1,"results":[{"index":0,"id":1,"trackingNumber":"SH169090604UA","price":{"amount":"5000.00","currency":"EUR"}},{"index":1,"error":"toEmail: invalid email","fields":[{"path":"toEmail","code":"invalid_email","message":"invalid email"}]}]}`,
+		},
+		{
+			name:        "none created in atomic mode",
+			query:       "?atomic=true",
+			fixturePath: "./fixtures/shipments/batch.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().AddShipments(input, true).Return([]models.ShipmentBatchResult{
+					{Index: 0, Error: "not created: batch has invalid shipments"},
+					{Index: 1, Error: "toEmail: invalid email", Fields: []models.FieldError{{Path: "toEmail", Code: services.CodeInvalidEmail, Message: "invalid email"}}},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"created":0,"failed":2,"results":[{"index":0,"error":"not created: batch has invalid shipments"},{"index":1,"error":"toEmail: invalid email","fields":[{"path":"toEmail","code":"invalid_email","message":"invalid email"}]}]}`,
+		},
+		{
+			name:                 "invalid atomic flag",
+			query:                "?atomic=maybe",
+			fixturePath:          "./fixtures/shipments/batch.ok.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid query parameters"}`,
+		},
+		{
+			name:                 "empty batch",
+			fixturePath:          "./fixtures/shipments/batch.empty.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"empty batch"}`,
+		},
+		{
+			name:                 "not an array",
+			fixturePath:          "./fixtures/shipments/add.ok.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:        "some internal error",
+			fixturePath: "./fixtures/shipments/batch.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().AddShipments(input, false).Return(nil, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.POST("/batch", addShipments(shipment))
+
+			// Input body preparing
+			fixturedData, err := os.ReadFile(tC.fixturePath)
+			require.NoError(t, err)
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/batch"+tC.query, bytes.NewBuffer(fixturedData))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_importShipments(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService, file []byte)
+
+	// the uploaded file is passed to the service
+	imported := func(file []byte, result models.ImportResult, err error) func(r io.Reader) (models.ImportResult, error) {
+		return func(r io.Reader) (models.ImportResult, error) {
+			uploaded, readErr := io.ReadAll(r)
+			if readErr != nil || !bytes.Equal(file, uploaded) {
+				return models.ImportResult{}, errors.New("unexpected file")
+			}
+			return result, err
+		}
+	}
+
+	testCases := []struct {
+		name                 string
+		field                string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedCreated      string
+		expectedResponseBody string
+	}{
+		{
+			name:  "all created",
+			field: "file",
+			mockBehaviur: func(r *mock_services.MockShipmentService, file []byte) {
+				r.EXPECT().ImportShipments(gomock.Any()).DoAndReturn(imported(file, models.ImportResult{Created: 2, Errors: []models.ImportError{}}, nil))
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedCreated:      "2",
+			expectedResponseBody: "row,column,error\n",
+		},
+		{
+			name:  "partially created",
+			field: "file",
+			mockBehaviur: func(r *mock_services.MockShipmentService, file []byte) {
+				r.EXPECT().ImportShipments(gomock.Any()).DoAndReturn(imported(file, models.ImportResult{
+					Created: 1,
+					Failed:  1,
+					Errors:  []models.ImportError{{Row: 3, Column: "fromCountryCode", Error: "invalid country code"}},
+				}, nil))
+			},
+			expectedStatusCode:   http.StatusMultiStatus,
+			expectedCreated:      "1",
+			expectedResponseBody: "row,column,error\n3,fromCountryCode,invalid country code\n",
+		},
+		{
+			name:  "none created",
+			field: "file",
+			mockBehaviur: func(r *mock_services.MockShipmentService, file []byte) {
+				r.EXPECT().ImportShipments(gomock.Any()).DoAndReturn(imported(file, models.ImportResult{
+					Failed: 1,
+					Errors: []models.ImportError{{Row: 2, Column: "weight", Error: "invalid number"}},
+				}, nil))
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedCreated:      "0",
+			expectedResponseBody: "row,column,error\n2,weight,invalid number\n",
+		},
+		{
+			name:                 "no file",
+			field:                "upload",
+			mockBehaviur:         func(r *mock_services.MockShipmentService, file []byte) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"file is required"}`,
+		},
+		{
+			name:  "invalid file",
+			field: "file",
+			mockBehaviur: func(r *mock_services.MockShipmentService, file []byte) {
+				r.EXPECT().ImportShipments(gomock.Any()).Return(models.ImportResult{}, fmt.Errorf("%w: missing column %q", services.ErrorInvalidImportFile, "weight"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid import file: missing column \"weight\""}`,
+		},
+		{
+			name:  "some internal error",
+			field: "file",
+			mockBehaviur: func(r *mock_services.MockShipmentService, file []byte) {
+				r.EXPECT().ImportShipments(gomock.Any()).Return(models.ImportResult{}, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			fixturedData, err := os.ReadFile("./fixtures/shipments/import.csv")
+			require.NoError(t, err)
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment, fixturedData)
+
+			// Init endpoint
+			api := gin.New()
+			api.POST("/import", importShipments(shipment))
+
+			// Input body preparing
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, err := form.CreateFormFile(tC.field, "shipments.csv")
+			require.NoError(t, err)
+			_, err = part.Write(fixturedData)
+			require.NoError(t, err)
+			require.NoError(t, form.Close())
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/import", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+			if tC.expectedCreated != "" {
+				require.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+				require.Equal(t, `attachment; filename="import-report.csv"`, w.Header().Get("Content-Disposition"))
+				require.Equal(t, tC.expectedCreated, w.Header().Get("X-Import-Created"))
+			}
+		})
+	}
+}
+
+func TestHandler_exportShipments(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	// the service gives the shipments one by one
+	exported := func(shipments []models.Shipment, err error) func(inp services.ListShipmentsInput, fn func(shipment models.Shipment) error) error {
+		return func(inp services.ListShipmentsInput, fn func(shipment models.Shipment) error) error {
+			for _, shipment := range shipments {
+				if err := fn(shipment); err != nil {
+					return err
+				}
+			}
+			return err
+		}
+	}
+	shipments := []models.Shipment{
+		{Id: 2, TrackingNumber: "SH169090604UA", FromName: "Mark", FromCountryCode: "UA", ToName: "Iryna", ToCountryCode: "CA", Weight: 234.4, Price: money.New(500000, "EUR"), Status: models.StatusCreated},
+		{Id: 3, TrackingNumber: "SH169090618UA", FromName: "Tom", FromCountryCode: "UA", ToName: "Viktor", ToCountryCode: "CA", Weight: 5, Price: money.New(25000, "EUR"), Status: models.StatusDelivered},
+	}
+	header := "id,trackingNumber,status,fromName,fromEmail,fromAddress,fromCountryCode,toName,toEmail,toAddress,toCountryCode,weight,length,width,height,pieces,price,currency,cancellationFee,refund,returnOfId\n"
+
+	testCases := []struct {
+		name                 string
+		query                string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name:  "CSV",
+			query: "?toCountryCode=CA&sort=-weight&limit=1000",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ExportShipments(services.ListShipmentsInput{ToCountryCode: "CA", Sort: "-weight"}, gomock.Any()).DoAndReturn(exported(shipments, nil))
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv",
+			expectedResponseBody: header +
+				"2,SH169090604UA,created,Mark,,,UA,Iryna,,,CA,234.4,,,,0,5000.00,EUR,,,\n" +
+				"3,SH169090618UA,delivered,Tom,,,UA,Viktor,,,CA,5,,,,0,250.00,EUR,,,\n",
+		},
+		{
+			name: "CSV with cancelled shipment",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ExportShipments(services.ListShipmentsInput{}, gomock.Any()).DoAndReturn(exported([]models.Shipment{{
+					Id: 4, TrackingNumber: "SH169090621UA", FromName: "Tom", FromCountryCode: "UA", ToName: "Alex", ToCountryCode: "CA", Weight: 5, Price: money.New(25000, "EUR"), Status: models.StatusCancelled,
+					Cancellation: &models.Cancellation{CancelledAt: time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC), Fee: money.New(5000, "EUR"), Refund: money.New(20000, "EUR")},
+				}}, nil))
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv",
+			expectedResponseBody: header +
+				"4,SH169090621UA,cancelled,Tom,,,UA,Alex,,,CA,5,,,,0,250.00,EUR,50.00,200.00,\n",
+		},
+		{
+			name:  "NDJSON",
+			query: "?format=ndjson",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ExportShipments(services.ListShipmentsInput{}, gomock.Any()).DoAndReturn(exported(shipments[:1], nil))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/x-ndjson",
+			expectedResponseBody: `{"Id":2,"TrackingNumber":"SH169090604UA","FromName":"Mark","FromEmail":"","FromAddress":"","FromCountryCode":"UA","ToName":"Iryna","ToEmail":"","ToAddress":"","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"5000.00","currency":"EUR"},"Status":"created"}` + "\n",
+		},
+		{
+			name:  "no shipments",
+			query: "?fromCountryCode=GB",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ExportShipments(services.ListShipmentsInput{FromCountryCode: "GB"}, gomock.Any()).DoAndReturn(exported(nil, nil))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/csv",
+			expectedResponseBody: header,
+		},
+		{
+			name:                 "unknown format",
+			query:                "?format=xlsx",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"unknown export format"}`,
+		},
+		{
+			name:                 "invalid filters",
+			query:                "?minWeight=20&maxWeight=10",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"invalid weight range"}`,
+		},
+		{
+			name:  "invalid cursor",
+			query: "?cursor=abc",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ExportShipments(services.ListShipmentsInput{Cursor: "abc"}, gomock.Any()).Return(repositories.ErrorInvalidCursor)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"invalid cursor"}`,
+		},
+		{
+			name: "some internal error before the first shipment",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ExportShipments(services.ListShipmentsInput{}, gomock.Any()).Return(errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedContentType:  "application/json; charset=utf-8",
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+		{
+			name:  "export cut off",
+			query: "?format=ndjson",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ExportShipments(services.ListShipmentsInput{}, gomock.Any()).DoAndReturn(exported(shipments[:1], errors.New("some internal error")))
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/x-ndjson",
+			expectedResponseBody: `{"Id":2,"TrackingNumber":"SH169090604UA","FromName":"Mark","FromEmail":"","FromAddress":"","FromCountryCode":"UA","ToName":"Iryna","ToEmail":"","ToAddress":"","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"5000.00","currency":"EUR"},"Status":"created"}` + "\n",
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.GET("/export", exportShipments(shipment))
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/export"+tC.query, nil)
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedContentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_quoteShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput)
+
+	testCases := []struct {
+		name                 string
+		fixturePath          string
+		inputShipment        services.AddShipmentInput
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			inputShipment: services.AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+			},
+			mockBehaviur: func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {
+				r.EXPECT().Quote(gomock.Eq(shipment)).Return(models.PriceBreakdown{
+					RateCardVersion: "default",
+					Lane:            models.Lane{Type: models.LaneIntercontinental, Origin: "europe", Destination: "world", Factor: 2.5},
+					BillableWeight:  234.4,
+					Components: []models.PriceComponent{
+						{Type: models.PriceComponentBase, Name: "weight class", Amount: money.New(200000, "EUR")},
+						{Type: models.PriceComponentLane, Name: "intercontinental", Factor: 2.5, Amount: money.New(300000, "EUR")},
+					},
+					Total: money.New(500000, "EUR"),
+				}, []models.Piece{{Weight: 234.4, BillableWeight: 234.4, Price: money.New(500000, "EUR")}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"breakdown":{"rateCardVersion":"default","lane":{"type":"intercontinental","origin":"europe","destination":"world","factor":2.5},"billableWeight":234.4,"components":[{"type":"base","name":"weight class","amount":{"amount":"2000.00","currency":"EUR"}},{"type":"lane","name":"intercontinental","factor":2.5,"amount":{"amount":"3000.00","currency":"EUR"}}],"total":{"amount":"5000.00","currency":"EUR"}},"pieces":[{"weight":234.4,"billableWeight":234.4,"price":{"amount":"5000.00","currency":"EUR"}}],"price":{"amount":"5000.00","currency":"EUR"}}`,
+		},
+		{
+			name:        "invalid shipment",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			inputShipment: services.AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+			},
+			mockBehaviur: func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {
+				r.EXPECT().Quote(gomock.Eq(shipment)).Return(models.PriceBreakdown{}, nil, pricing.ErrorWeightNotSupported)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"` + pricing.ErrorWeightNotSupported.Error() + `"}`,
+		},
+		{
+			name:        "no fx rate",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {
+				r.EXPECT().Quote(gomock.Any()).Return(models.PriceBreakdown{}, nil, fmt.Errorf("EUR/USD: %w", fx.ErrorRateNotFound))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"EUR/USD: fx rate not found"}`,
+		},
+		{
+			name:        "some internal error",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {
+				r.EXPECT().Quote(gomock.Any()).Return(models.PriceBreakdown{}, nil, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+		{
+			name:        "Missing weight",
+			fixturePath: "./fixtures/shipments/add.no_weight.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {
+				r.EXPECT().Quote(gomock.Any()).Return(models.PriceBreakdown{}, nil, &services.ValidationError{Fields: []models.FieldError{{Path: "weight", Code: services.CodeRequired, Message: "is required"}}})
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"weight","code":"required","message":"is required"}]}`,
+		},
+		{
+			name:                 "Invalid weight type",
+			fixturePath:          "./fixtures/shipments/add.invalid_weight.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService, shipment services.AddShipmentInput) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"weight","code":"invalid_type","message":"must be a number"}]}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment, tC.inputShipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.POST("/quote", quoteShipment(shipment))
+
+			// Input body preparing
+			fixturedData, err := os.ReadFile(tC.fixturePath)
+			require.NoError(t, err)
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/quote", bytes.NewBuffer(fixturedData))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_updateShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	// input of the add.ok.json fixture
+	input := services.AddShipmentInput{
+		FromName:        "Mark",
+		FromEmail:       "testFrom@g.c",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "CA",
+		Weight:          234.4,
+	}
+
+	testCases := []struct {
+		name                 string
+		inputId              string
+		fixturePath          string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().UpdateShipment(uint(2), input).Return(models.Shipment{
+					Id:              2,
+					FromCountryCode: "UA",
+					ToCountryCode:   "CA",
+					Weight:          234.4,
+					Price:           money.New(500000, "EUR"),
+					Status:          models.StatusCreated,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"shipment":{"Id":2,"FromName":"","FromEmail":"","FromAddress":"","FromCountryCode":"UA","ToName":"","ToEmail":"","ToAddress":"","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"5000.00","currency":"EUR"},"Status":"created"}}`,
+		},
+		{
+			name:        "invalid shipment",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().UpdateShipment(uint(2), input).Return(models.Shipment{}, &services.ValidationError{Fields: []models.FieldError{{Path: "weight", Code: services.CodeInvalidWeight, Message: "invalid weight"}}})
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"weight","code":"invalid_weight","message":"invalid weight"}]}`,
+		},
+		{
+			name:        "not editable",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().UpdateShipment(uint(2), input).Return(models.Shipment{}, fmt.Errorf("%w %q", services.ErrorShipmentNotEditable, "picked_up"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"shipment can't be edited in status \"picked_up\""}`,
+		},
+		{
+			name:        "billable weight over the max weight of the rate card",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().UpdateShipment(uint(2), input).Return(models.Shipment{}, pricing.ErrorWeightNotSupported)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"weight is not supported by rate card"}`,
+		},
+		{
+			name:        "no fx rate of the currency",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().UpdateShipment(uint(2), input).Return(models.Shipment{}, fmt.Errorf("%s/%s: %w", "EUR", "SEK", fx.ErrorRateNotFound))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"EUR/SEK: fx rate not found"}`,
+		},
+		{
+			name:        "shipment not found",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/add.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().UpdateShipment(uint(2), input).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"shipment not found"}`,
+		},
+		{
+			name:                 "invalid field type",
+			inputId:              "2",
+			fixturePath:          "./fixtures/shipments/add.invalid_weight.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"error":"invalid fields","fields":[{"path":"weight","code":"invalid_type","message":"must be a number"}]}`,
+		},
+		{
+			name:                 "invalid ID",
+			inputId:              "two",
+			fixturePath:          "./fixtures/shipments/add.ok.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"two\": invalid syntax"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.PUT("/:id", updateShipment(shipment))
+
+			// Input body preparing
+			fixturedData, err := os.ReadFile(tC.fixturePath)
+			require.NoError(t, err)
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", fmt.Sprintf("/%s", tC.inputId), bytes.NewBuffer(fixturedData))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_patchShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	// input of the patch.ok.json fixture
+	toAddress := "Toronto, 35"
+	weight := 10.0
+	input := services.PatchShipmentInput{ToAddress: &toAddress, Weight: &weight}
+
+	testCases := []struct {
+		name                 string
+		fixturePath          string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			fixturePath: "./fixtures/shipments/patch.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().PatchShipment(uint(2), input).Return(models.Shipment{
+					Id:              2,
+					ToAddress:       "Toronto, 35",
+					FromCountryCode: "UA",
+					ToCountryCode:   "CA",
+					Weight:          10,
+					Price:           money.New(30000, "EUR"),
+					Status:          models.StatusCreated,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"shipment":{"Id":2,"FromName":"","FromEmail":"","FromAddress":"","FromCountryCode":"UA","ToName":"","ToEmail":"","ToAddress":"Toronto, 35","ToCountryCode":"CA","Weight":10,"Price":{"amount":"300.00","currency":"EUR"},"Status":"created"}}`,
+		},
+		{
+			name:        "status was changed concurrently",
+			fixturePath: "./fixtures/shipments/patch.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().PatchShipment(uint(2), input).Return(models.Shipment{}, repositories.ErrorStatusConflict)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"shipment status was changed concurrently"}`,
+		},
+		{
+			name:        "some internal error",
+			fixturePath: "./fixtures/shipments/patch.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().PatchShipment(uint(2), input).Return(models.Shipment{}, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+		{
+			name:                 "invalid body",
+			fixturePath:          "./fixtures/shipments/patch.invalid.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.PATCH("/:id", patchShipment(shipment))
+
+			// Input body preparing
+			fixturedData, err := os.ReadFile(tC.fixturePath)
+			require.NoError(t, err)
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PATCH", "/2", bytes.NewBuffer(fixturedData))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_transitionShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	testCases := []struct {
+		name                 string
+		inputId              string
+		fixturePath          string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/transition.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TransitionShipment(uint(2), models.StatusPickedUp).Return(models.Shipment{
+					Id:              2,
+					FromCountryCode: "UA",
+					ToCountryCode:   "CA",
+					Weight:          234.4,
+					Price:           money.New(9999, "EUR"),
+					Status:          models.StatusPickedUp,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"shipment":{"Id":2,"FromName":"","FromEmail":"","FromAddress":"","FromCountryCode":"UA","ToName":"","ToEmail":"","ToAddress":"","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"99.99","currency":"EUR"},"Status":"picked_up"}}`,
+		},
+		{
+			name:        "illegal transition",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/transition.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TransitionShipment(uint(2), models.StatusPickedUp).Return(models.Shipment{}, fmt.Errorf("%w from %q to %q", services.ErrorIllegalTransition, "created", "picked_up"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"illegal status transition from \"created\" to \"picked_up\""}`,
+		},
+		{
+			name:        "status was changed concurrently",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/transition.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TransitionShipment(uint(2), models.StatusPickedUp).Return(models.Shipment{}, repositories.ErrorStatusConflict)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"shipment status was changed concurrently"}`,
+		},
+		{
+			name:        "unknown status",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/transition.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TransitionShipment(uint(2), models.StatusPickedUp).Return(models.Shipment{}, fmt.Errorf("%w %q", services.ErrorUnknownStatus, "picked_up"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"unknown shipment status \"picked_up\""}`,
+		},
+		{
+			name:        "shipment not found",
+			inputId:     "2",
+			fixturePath: "./fixtures/shipments/transition.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TransitionShipment(uint(2), models.StatusPickedUp).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"shipment not found"}`,
+		},
+		{
+			name:                 "Missing status",
+			inputId:              "2",
+			fixturePath:          "./fixtures/shipments/transition.no_status.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+		{
+			name:                 "invalid ID",
+			inputId:              "two",
+			fixturePath:          "./fixtures/shipments/transition.ok.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"two\": invalid syntax"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.POST("/:id/transitions", transitionShipment(shipment))
+
+			// Input body preparing
+			fixturedData, err := os.ReadFile(tC.fixturePath)
+			require.NoError(t, err)
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", fmt.Sprintf("/%s/transitions", tC.inputId), bytes.NewBuffer(fixturedData))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_cancelShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	testCases := []struct {
+		name                 string
+		inputId              string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "OK",
+			inputId: "2",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().CancelShipment(uint(2)).Return(models.Shipment{
+					Id:              2,
+					FromCountryCode: "UA",
+					ToCountryCode:   "CA",
+					Weight:          234.4,
+					Price:           money.New(500000, "EUR"),
+					Status:          models.StatusCancelled,
+					Cancellation: &models.Cancellation{
+						CancelledAt: time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+						Fee:         money.New(100000, "EUR"),
+						Refund:      money.New(400000, "EUR"),
+					},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"shipment":{"Id":2,"FromName":"","FromEmail":"","FromAddress":"","FromCountryCode":"UA","ToName":"","ToEmail":"","ToAddress":"","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"5000.00","currency":"EUR"},"Status":"cancelled","Cancellation":{"cancelledAt":"2022-03-01T12:00:00Z","fee":{"amount":"1000.00","currency":"EUR"},"refund":{"amount":"4000.00","currency":"EUR"}}}}`,
+		},
+		{
+			name:    "already delivered",
+			inputId: "2",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().CancelShipment(uint(2)).Return(models.Shipment{}, fmt.Errorf("%w from %q to %q", services.ErrorIllegalTransition, "delivered", "cancelled"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"illegal status transition from \"delivered\" to \"cancelled\""}`,
+		},
+		{
+			name:    "shipment not found",
+			inputId: "2",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().CancelShipment(uint(2)).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"shipment not found"}`,
+		},
+		{
+			name:    "some internal error",
+			inputId: "2",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().CancelShipment(uint(2)).Return(models.Shipment{}, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+		{
+			name:                 "invalid ID",
+			inputId:              "two",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"two\": invalid syntax"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.POST("/:id/cancel", cancelShipment(shipment))
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", fmt.Sprintf("/%s/cancel", tC.inputId), bytes.NewBufferString(""))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_returnShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	testCases := []struct {
+		name                 string
+		inputId              string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "OK",
+			inputId: "1",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ReturnShipment(uint(1)).Return(models.Shipment{
+					Id:              2,
+					TrackingNumber:  "SH169090604UA",
+					FromCountryCode: "CA",
+					ToCountryCode:   "UA",
+					Weight:          234.4,
+					Price:           money.New(400000, "EUR"),
+					Status:          models.StatusCreated,
+					ReturnOf:        &models.ShipmentLink{Id: 1, TrackingNumber: "SH123456785UA", Status: models.StatusDelivered},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"shipment":{"Id":2,"TrackingNumber":"SH169090604UA","FromName":"","FromEmail":"","FromAddress":"","FromCountryCode":"CA","ToName":"","ToEmail":"","ToAddress":"","ToCountryCode":"UA","Weight":234.4,"Price":{"amount":"4000.00","currency":"EUR"},"Status":"created","ReturnOf":{"id":1,"trackingNumber":"SH123456785UA","status":"delivered"}}}`,
+		},
+		{
+			name:    "not delivered yet",
+			inputId: "1",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ReturnShipment(uint(1)).Return(models.Shipment{}, fmt.Errorf("%w in status %q", services.ErrorReturnNotAllowed, "in_transit"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"error":"shipment can't be returned in status \"in_transit\""}`,
+		},
+		{
+			name:    "shipment not found",
+			inputId: "1",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ReturnShipment(uint(1)).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"shipment not found"}`,
+		},
+		{
+			name:    "some internal error",
+			inputId: "1",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ReturnShipment(uint(1)).Return(models.Shipment{}, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+		{
+			name:                 "invalid ID",
+			inputId:              "one",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"one\": invalid syntax"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.POST("/:id/return", returnShipment(shipment))
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", fmt.Sprintf("/%s/return", tC.inputId), bytes.NewBufferString(""))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getTrackingEvents(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	testCases := []struct {
+		name                 string
+		inputId              string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "OK",
+			inputId: "2",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().GetTrackingEvents(uint(2)).Return([]models.TrackingEvent{
+					{Id: 1, OccurredAt: time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC), Code: "PICKED_UP", Location: models.EventLocation{City: "Lviv", CountryCode: "UA"}},
+					{Id: 2, OccurredAt: time.Date(2022, 1, 11, 9, 0, 0, 0, time.UTC), Code: "ARRIVED_AT_HUB", Location: models.EventLocation{Facility: "Hub 3", City: "Warsaw", CountryCode: "PL"}, Note: "arrived on time"},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"events":[{"id":1,"occurredAt":"2022-01-10T08:30:00Z","code":"PICKED_UP","location":{"city":"Lviv","countryCode":"UA"}},{"id":2,"occurredAt":"2022-01-11T09:00:00Z","code":"ARRIVED_AT_HUB","location":{"facility":"Hub 3","city":"Warsaw","countryCode":"PL"},"note":"arrived on time"}]}`,
+		},
+		{
+			name:    "OK without events",
+			inputId: "2",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().GetTrackingEvents(uint(2)).Return([]models.TrackingEvent{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"events":[]}`,
+		},
+		{
+			name:    "shipment not found",
+			inputId: "2",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().GetTrackingEvents(uint(2)).Return(nil, repositories.ErrorShipmentNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"shipment not found"}`,
+		},
+		{
+			name:    "some internal error",
+			inputId: "2",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().GetTrackingEvents(uint(2)).Return(nil, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.GET("/:id/events", getTrackingEvents(shipment))
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", fmt.Sprintf("/%s/events", tC.inputId), bytes.NewBufferString(""))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_addTrackingEvent(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	input := services.AddTrackingEventInput{
+		OccurredAt: time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC),
+		Code:       "ARRIVED_AT_HUB",
+		Location:   models.EventLocation{Facility: "Hub 3", City: "Warsaw", CountryCode: "PL"},
+		Note:       "arrived on time",
+	}
+
+	testCases := []struct {
+		name                 string
+		fixturePath          string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			fixturePath: "./fixtures/shipments/event.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().AddTrackingEvent(uint(2), gomock.Eq(input)).Return(models.TrackingEvent{
+					Id:         7,
+					OccurredAt: input.OccurredAt,
+					Code:       input.Code,
+					Location:   input.Location,
+					Note:       input.Note,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"event":{"id":7,"occurredAt":"2022-01-10T08:30:00Z","code":"ARRIVED_AT_HUB","location":{"facility":"Hub 3","city":"Warsaw","countryCode":"PL"},"note":"arrived on time"}}`,
+		},
+		{
+			name:        "shipment not found",
+			fixturePath: "./fixtures/shipments/event.ok.json",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().AddTrackingEvent(uint(2), gomock.Eq(input)).Return(models.TrackingEvent{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"shipment not found"}`,
+		},
+		{
+			name:                 "invalid code",
+			fixturePath:          "./fixtures/shipments/event.invalid_code.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid event code"}`,
+		},
+		{
+			name:                 "Missing code",
+			fixturePath:          "./fixtures/shipments/event.no_code.json",
+			mockBehaviur:         func(r *mock_services.MockShipmentService) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid input body"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.POST("/:id/events", addTrackingEvent(shipment))
+
+			// Input body preparing
+			fixturedData, err := os.ReadFile(tC.fixturePath)
+			require.NoError(t, err)
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/2/events", bytes.NewBuffer(fixturedData))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getShipmentByTrackingNumber(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	testCases := []struct {
+		name                 string
+		number               string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			number: "SH169090604UA",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().GetShipmentByTrackingNumber("SH169090604UA").Return(models.Shipment{
+					Id:              2,
+					TrackingNumber:  "SH169090604UA",
+					FromName:        "Mark",
+					FromEmail:       "testFrom@g.c",
+					FromAddress:     "Lviv, 45",
+					FromCountryCode: "UA",
+					ToName:          "Iryna",
+					ToEmail:         "testTo@g.c",
+					ToAddress:       "Toronto, 34",
+					ToCountryCode:   "CA",
+					Weight:          234.4,
+					Price:           money.New(9999, "EUR"),
+					Status:          models.StatusCreated,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"shipment":{"Id":2,"TrackingNumber":"SH169090604UA","FromName":"Mark","FromEmail":"testFrom@g.c","FromAddress":"Lviv, 45","FromCountryCode":"UA","ToName":"Iryna","ToEmail":"testTo@g.c","ToAddress":"Toronto, 34","ToCountryCode":"CA","Weight":234.4,"Price":{"amount":"99.99","currency":"EUR"},"Status":"created"}}`,
+		},
+		{
+			name:   "invalid tracking number",
+			number: "SH169090605UA",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().GetShipmentByTrackingNumber("SH169090605UA").Return(models.Shipment{}, tracking.ErrorInvalidTrackingNumber)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid tracking number"}`,
+		},
+		{
+			name:   "not found",
+			number: "SH169090604UA",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().GetShipmentByTrackingNumber("SH169090604UA").Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"shipment not found"}`,
+		},
+		{
+			name:   "some internal error",
+			number: "SH169090604UA",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().GetShipmentByTrackingNumber("SH169090604UA").Return(models.Shipment{}, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			// Init endpoint
+			api := gin.New()
+			api.GET("/tracking/:number", getShipmentByTrackingNumber(shipment))
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/tracking/"+tC.number, bytes.NewBufferString(""))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
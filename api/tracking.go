@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Taras-Rm/shipment/presenters"
+	"github.com/Taras-Rm/shipment/repositories"
+	"github.com/Taras-Rm/shipment/services"
+	"github.com/Taras-Rm/shipment/tracking"
+	"github.com/gin-gonic/gin"
+)
+
+// public endpoints for recipients (without personal data)
+func UseTracking(gr *gin.RouterGroup, shipmentService services.ShipmentService, tenantService services.TenantService) {
+	handler := gr.Group("tracking", resolveTenant(tenantService, false))
+
+	// endpoints (shipments of all tenants are tracked on the host without tenant)
+	handler.GET(":number", scoped(shipmentService, tenantService, func(shipmentService services.ShipmentService) gin.HandlerFunc {
+		return getPublicTracking(shipmentService, tenantService)
+	}))
+}
+
+func getPublicTracking(shipmentService services.ShipmentService, tenantService services.TenantService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// get shipment and its events by tracking number
+		shipment, events, err := shipmentService.TrackShipment(c.Param("number"))
+		switch {
+		case errors.Is(err, tracking.ErrorInvalidTrackingNumber):
+			newErrorResponse(c, http.StatusBadRequest, err)
+			return
+		case errors.Is(err, repositories.ErrorShipmentNotFound):
+			newErrorResponse(c, http.StatusNotFound, err)
+			return
+		case err != nil:
+			newErrorResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		// the shipment is shown with the brand of its tenant (none without tenants)
+		tenant, _ := tenantService.GetTenant(shipment.TenantID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"tracking": presenters.NewPublicTracking(shipment, events, tenant),
+		})
+	}
+}
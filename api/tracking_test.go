@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/Taras-Rm/shipment/repositories"
+	"github.com/Taras-Rm/shipment/services"
+	mock_services "github.com/Taras-Rm/shipment/services/mocks"
+	"github.com/Taras-Rm/shipment/tracking"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_getPublicTracking(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	testCases := []struct {
+		name                 string
+		number               string
+		mockBehaviur         mockBehaviur
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			number: "SH169090604UA",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TrackShipment("SH169090604UA").Return(models.Shipment{
+					Id:              2,
+					TrackingNumber:  "SH169090604UA",
+					FromName:        "Mark",
+					FromEmail:       "testFrom@g.c",
+					FromAddress:     "Lviv, 45",
+					FromCountryCode: "UA",
+					ToName:          "Iryna",
+					ToEmail:         "testTo@g.c",
+					ToAddress:       "Toronto, 34",
+					ToCountryCode:   "CA",
+					Weight:          234.4,
+					Price:           money.New(9999, "EUR"),
+					Status:          models.StatusPickedUp,
+				}, []models.TrackingEvent{
+					{
+						Id:         1,
+						OccurredAt: time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC),
+						Code:       "PICKED_UP",
+						Location:   models.EventLocation{Facility: "Lviv, Shevchenka 12", City: "Lviv", CountryCode: "UA"},
+						Note:       "handed over by Mark",
+					},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"tracking":{"trackingNumber":"SH169090604UA","status":"picked_up","origin":{"countryCode":"UA"},"destination":{"countryCode":"CA"},"events":[{"occurredAt":"2022-01-10T08:30:00Z","code":"PICKED_UP","location":{"city":"Lviv","countryCode":"UA"}}]}}`,
+		},
+		{
+			name:   "OK with brand of the tenant",
+			number: "SH169090604UA",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TrackShipment("SH169090604UA").Return(models.Shipment{
+					Id:              2,
+					TrackingNumber:  "SH169090604UA",
+					FromCountryCode: "UA",
+					ToCountryCode:   "CA",
+					Status:          models.StatusPickedUp,
+					TenantID:        "brand-a",
+				}, nil, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"tracking":{"trackingNumber":"SH169090604UA","status":"picked_up","origin":{"countryCode":"UA"},"destination":{"countryCode":"CA"},"events":[],"brand":{"name":"Brand A Parcels","logoUrl":"https://cdn.example.com/brand-a.png"}}}`,
+		},
+		{
+			name:   "invalid tracking number",
+			number: "42",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TrackShipment("42").Return(models.Shipment{}, nil, tracking.ErrorInvalidTrackingNumber)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"error":"invalid tracking number"}`,
+		},
+		{
+			name:   "not found",
+			number: "SH169090604UA",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TrackShipment("SH169090604UA").Return(models.Shipment{}, nil, repositories.ErrorShipmentNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"error":"shipment not found"}`,
+		},
+		{
+			name:   "some internal error",
+			number: "SH169090604UA",
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().TrackShipment("SH169090604UA").Return(models.Shipment{}, nil, errors.New("some internal error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"error":"some internal error"}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			tenants, err := services.InitTenantService([]models.Tenant{{Id: "brand-a", Name: "Brand A", Branding: models.Branding{DisplayName: "Brand A Parcels", LogoURL: "https://cdn.example.com/brand-a.png"}}}, nil)
+			require.NoError(t, err)
+
+			// Init endpoint
+			api := gin.New()
+			api.GET("/:number", getPublicTracking(shipment, tenants))
+
+			// Create request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/"+tC.number, bytes.NewBufferString(""))
+
+			// Make request
+			api.ServeHTTP(w, req)
+
+			// Require
+			require.Equal(t, tC.expectedStatusCode, w.Code)
+			require.Equal(t, tC.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/services"
+	"github.com/gin-gonic/gin"
+)
+
+// bind JSON body of the request (a value of a wrong type is an invalid field, not a broken body)
+func bindJSON(c *gin.Context, inp interface{}) error {
+	err := c.ShouldBindJSON(inp)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &services.ValidationError{Fields: []models.FieldError{{
+			Path:    fieldPath(typeErr.Field),
+			Code:    services.CodeInvalidType,
+			Message: "must be " + typeName(typeErr.Type),
+		}}}
+	}
+	return errors.New("invalid input body")
+}
+
+// JSON path of the field of the decoder error ("pieces.1.weight" is "pieces[1].weight")
+func fieldPath(field string) string {
+	var path strings.Builder
+	for _, part := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			path.WriteString("[" + part + "]")
+			continue
+		}
+		if path.Len() != 0 {
+			path.WriteString(".")
+		}
+		path.WriteString(part)
+	}
+	return path.String()
+}
+
+// JSON type of the field
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	default:
+		return "a number"
+	}
+}
+
+// invalid fields of the body are 422, broken body is 400
+func respondBindError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		newErrorResponse(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	newErrorResponse(c, http.StatusBadRequest, err)
+}
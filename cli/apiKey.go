@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Taras-Rm/shipment/services"
+)
+
+var ErrorAPIKeyUsage error = errors.New("usage: apikey create -client acme [-tenant brand-a] [-name name] | apikey list | apikey revoke id")
+
+// manage the API keys of the clients: apikey create|list|revoke
+func runAPIKey(args []string, apiKeyService services.APIKeyService, stdout io.Writer) error {
+	if len(args) == 0 {
+		return ErrorAPIKeyUsage
+	}
+
+	switch args[0] {
+	case "create":
+		return runAPIKeyCreate(args[1:], apiKeyService, stdout)
+	case "list":
+		return runAPIKeyList(args[1:], apiKeyService, stdout)
+	case "revoke":
+		return runAPIKeyRevoke(args[1:], apiKeyService, stdout)
+	}
+
+	return ErrorAPIKeyUsage
+}
+
+// the key is printed only once, it can't be got later
+func runAPIKeyCreate(args []string, apiKeyService services.APIKeyService, stdout io.Writer) error {
+	flags := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	client := flags.String("client", "", "client which sees the shipments created with the key")
+	tenant := flags.String("tenant", "", "tenant which the client works in")
+	name := flags.String("name", "", "name of the key")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 || *client == "" {
+		return ErrorAPIKeyUsage
+	}
+
+	apiKey, key, err := apiKeyService.CreateAPIKey(*name, *client, *tenant)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "id: %d\nclient: %s\n", apiKey.Id, apiKey.Client)
+	if apiKey.Tenant != "" {
+		fmt.Fprintf(stdout, "tenant: %s\n", apiKey.Tenant)
+	}
+	fmt.Fprintf(stdout, "key: %s\n", key)
+
+	return nil
+}
+
+func runAPIKeyList(args []string, apiKeyService services.APIKeyService, stdout io.Writer) error {
+	if len(args) != 0 {
+		return ErrorAPIKeyUsage
+	}
+
+	apiKeys, err := apiKeyService.ListAPIKeys()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTENANT\tCLIENT\tNAME\tPREFIX\tCREATED\tREVOKED")
+	for _, apiKey := range apiKeys {
+		revoked := "-"
+		if apiKey.RevokedAt != nil {
+			revoked = apiKey.RevokedAt.Format(time.RFC3339)
+		}
+		tenant := apiKey.Tenant
+		if tenant == "" {
+			tenant = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", apiKey.Id, tenant, apiKey.Client, apiKey.Name, apiKey.Prefix, apiKey.CreatedAt.Format(time.RFC3339), revoked)
+	}
+
+	return w.Flush()
+}
+
+func runAPIKeyRevoke(args []string, apiKeyService services.APIKeyService, stdout io.Writer) error {
+	if len(args) != 1 {
+		return ErrorAPIKeyUsage
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return ErrorAPIKeyUsage
+	}
+
+	if err := apiKeyService.RevokeAPIKey(uint(id)); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "revoked: %d\n", id)
+
+	return nil
+}
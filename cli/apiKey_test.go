@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_services "github.com/Taras-Rm/shipment/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_apiKey(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockAPIKeyService)
+
+	createdAt := time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC)
+	revokedAt := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		args           []string
+		mockBehaviur   mockBehaviur
+		expectedOutput string
+		expectedError  error
+	}{
+		{
+			name: "create",
+			args: []string{"apikey", "create", "-client", "acme", "-name", "warehouse"},
+			mockBehaviur: func(r *mock_services.MockAPIKeyService) {
+				r.EXPECT().CreateAPIKey("warehouse", "acme", "").Return(models.APIKey{Id: 1, Name: "warehouse", Client: "acme"}, "sk_010203040102_secret", nil)
+			},
+			expectedOutput: "id: 1\nclient: acme\nkey: sk_010203040102_secret\n",
+		},
+		{
+			name: "create in tenant",
+			args: []string{"apikey", "create", "-client", "acme", "-tenant", "brand-a"},
+			mockBehaviur: func(r *mock_services.MockAPIKeyService) {
+				r.EXPECT().CreateAPIKey("", "acme", "brand-a").Return(models.APIKey{Id: 1, Client: "acme", Tenant: "brand-a"}, "sk_010203040102_secret", nil)
+			},
+			expectedOutput: "id: 1\nclient: acme\ntenant: brand-a\nkey: sk_010203040102_secret\n",
+		},
+		{
+			name:          "create without client",
+			args:          []string{"apikey", "create", "-name", "warehouse"},
+			mockBehaviur:  func(r *mock_services.MockAPIKeyService) {},
+			expectedError: ErrorAPIKeyUsage,
+		},
+		{
+			name: "list",
+			args: []string{"apikey", "list"},
+			mockBehaviur: func(r *mock_services.MockAPIKeyService) {
+				r.EXPECT().ListAPIKeys().Return([]models.APIKey{
+					{Id: 1, Name: "warehouse", Client: "acme", Tenant: "brand-a", Prefix: "010203040102", CreatedAt: createdAt},
+					{Id: 2, Client: "globex", Prefix: "0a0b0c0d0e0f", CreatedAt: createdAt, RevokedAt: &revokedAt},
+				}, nil)
+			},
+			expectedOutput: "ID  TENANT   CLIENT  NAME       PREFIX        CREATED               REVOKED\n" +
+				"1   brand-a  acme    warehouse  010203040102  2022-01-10T08:30:00Z  -\n" +
+				"2   -        globex             0a0b0c0d0e0f  2022-01-10T08:30:00Z  2022-02-01T00:00:00Z\n",
+		},
+		{
+			name: "revoke",
+			args: []string{"apikey", "revoke", "2"},
+			mockBehaviur: func(r *mock_services.MockAPIKeyService) {
+				r.EXPECT().RevokeAPIKey(uint(2)).Return(nil)
+			},
+			expectedOutput: "revoked: 2\n",
+		},
+		{
+			name: "revoke not existing",
+			args: []string{"apikey", "revoke", "3"},
+			mockBehaviur: func(r *mock_services.MockAPIKeyService) {
+				r.EXPECT().RevokeAPIKey(uint(3)).Return(repositories.ErrorAPIKeyNotFound)
+			},
+			expectedError: repositories.ErrorAPIKeyNotFound,
+		},
+		{
+			name:          "revoke invalid id",
+			args:          []string{"apikey", "revoke", "acme"},
+			mockBehaviur:  func(r *mock_services.MockAPIKeyService) {},
+			expectedError: ErrorAPIKeyUsage,
+		},
+		{
+			name:          "unknown subcommand",
+			args:          []string{"apikey", "rotate"},
+			mockBehaviur:  func(r *mock_services.MockAPIKeyService) {},
+			expectedError: ErrorAPIKeyUsage,
+		},
+		{
+			name: "some internal error",
+			args: []string{"apikey", "list"},
+			mockBehaviur: func(r *mock_services.MockAPIKeyService) {
+				r.EXPECT().ListAPIKeys().Return(nil, errors.New("some internal error"))
+			},
+			expectedError: errors.New("some internal error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			apiKey := mock_services.NewMockAPIKeyService(c)
+			tC.mockBehaviur(apiKey)
+
+			// Run command
+			var output bytes.Buffer
+			err := Run(tC.args, nil, apiKey, nil, &output)
+
+			// Require
+			require.Equal(t, tC.expectedError, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tC.expectedOutput, output.String())
+		})
+	}
+}
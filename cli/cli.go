@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Taras-Rm/shipment/services"
+)
+
+var ErrorUnknownCommand error = errors.New("unknown command")
+
+// run the subcommand of the command line (the server is not started)
+func Run(args []string, shipmentService services.ShipmentService, apiKeyService services.APIKeyService, tenantService services.TenantService, stdout io.Writer) error {
+	switch args[0] {
+	case "import":
+		return runImport(args[1:], shipmentService, tenantService, stdout)
+	case "apikey":
+		return runAPIKey(args[1:], apiKeyService, stdout)
+	}
+
+	return fmt.Errorf("%w %q", ErrorUnknownCommand, args[0])
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Taras-Rm/shipment/presenters"
+	"github.com/Taras-Rm/shipment/services"
+)
+
+// import shipments of the CSV file: import [-tenant brand-a] [-client acme] [-report import-report.csv] shipments.csv
+func runImport(args []string, shipmentService services.ShipmentService, tenantService services.TenantService, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	reportPath := flags.String("report", "import-report.csv", "path of the error report CSV")
+	client := flags.String("client", "", "client which owns the imported shipments")
+	tenant := flags.String("tenant", "", "tenant of the imported shipments")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import [-tenant brand-a] [-client acme] [-report import-report.csv] shipments.csv")
+	}
+
+	// shipments without the tenant would not be seen by any tenant
+	if *tenant == "" && tenantService.MultiTenant() {
+		return errors.New("tenant is required: import -tenant brand-a shipments.csv")
+	}
+
+	// shipments are priced by the rate card of the tenant
+	if *tenant != "" {
+		if _, err := tenantService.GetTenant(*tenant); err != nil {
+			return fmt.Errorf("tenant %q: %w", *tenant, err)
+		}
+		shipmentService = shipmentService.WithTenant(*tenant, tenantService.PricingEngine(*tenant))
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// shipments without the client are seen only by the command line
+	if *client != "" {
+		shipmentService = shipmentService.WithOwner(*client)
+	}
+
+	result, err := shipmentService.ImportShipments(file)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "created: %d, failed: %d\n", result.Created, result.Failed)
+
+	// the report is written only if some rows are invalid
+	if len(result.Errors) == 0 {
+		return nil
+	}
+
+	report, err := os.Create(*reportPath)
+	if err != nil {
+		return err
+	}
+	defer report.Close()
+
+	if err := presenters.WriteImportReport(report, result.Errors); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "error report: %s\n", *reportPath)
+
+	return report.Close()
+}
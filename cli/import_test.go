@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/services"
+	mock_services "github.com/Taras-Rm/shipment/services/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_import(t *testing.T) {
+	type mockBehaviur func(r *mock_services.MockShipmentService)
+
+	testCases := []struct {
+		name           string
+		tenants        []models.Tenant
+		args           func(file, report string) []string
+		mockBehaviur   mockBehaviur
+		expectedOutput func(report string) string
+		expectedReport string
+		expectedError  error
+	}{
+		{
+			name: "all created",
+			args: func(file, report string) []string { return []string{"import", "-report", report, file} },
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ImportShipments(gomock.Any()).Return(models.ImportResult{Created: 2, Errors: []models.ImportError{}}, nil)
+			},
+			expectedOutput: func(report string) string { return "created: 2, failed: 0\n" },
+		},
+		{
+			name: "report of the errors",
+			args: func(file, report string) []string { return []string{"import", "-report", report, file} },
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ImportShipments(gomock.Any()).Return(models.ImportResult{
+					Created: 1,
+					Failed:  1,
+					Errors:  []models.ImportError{{Row: 3, Column: "fromCountryCode", Error: "invalid country code"}},
+				}, nil)
+			},
+			expectedOutput: func(report string) string {
+				return fmt.Sprintf("created: 1, failed: 1\nerror report: %s\n", report)
+			},
+			expectedReport: "row,column,error\n3,fromCountryCode,invalid country code\n",
+		},
+		{
+			name: "shipments of the client",
+			args: func(file, report string) []string {
+				return []string{"import", "-client", "acme", "-report", report, file}
+			},
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().WithOwner("acme").Return(r)
+				r.EXPECT().ImportShipments(gomock.Any()).Return(models.ImportResult{Created: 1, Errors: []models.ImportError{}}, nil)
+			},
+			expectedOutput: func(report string) string { return "created: 1, failed: 0\n" },
+		},
+		{
+			name:    "shipments of the tenant",
+			tenants: []models.Tenant{{Id: "brand-a"}},
+			args: func(file, report string) []string {
+				return []string{"import", "-tenant", "brand-a", "-report", report, file}
+			},
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().WithTenant("brand-a", nil).Return(r)
+				r.EXPECT().ImportShipments(gomock.Any()).Return(models.ImportResult{Created: 1, Errors: []models.ImportError{}}, nil)
+			},
+			expectedOutput: func(report string) string { return "created: 1, failed: 0\n" },
+		},
+		{
+			name:          "unknown tenant",
+			tenants:       []models.Tenant{{Id: "brand-a"}},
+			args:          func(file, report string) []string { return []string{"import", "-tenant", "brand-c", file} },
+			mockBehaviur:  func(r *mock_services.MockShipmentService) {},
+			expectedError: fmt.Errorf("tenant %q: %w", "brand-c", services.ErrorTenantNotFound),
+		},
+		{
+			name:          "no tenant",
+			tenants:       []models.Tenant{{Id: "brand-a"}},
+			args:          func(file, report string) []string { return []string{"import", file} },
+			mockBehaviur:  func(r *mock_services.MockShipmentService) {},
+			expectedError: errors.New("tenant is required: import -tenant brand-a shipments.csv"),
+		},
+		{
+			name:          "no file",
+			args:          func(file, report string) []string { return []string{"import"} },
+			mockBehaviur:  func(r *mock_services.MockShipmentService) {},
+			expectedError: errors.New("usage: import [-tenant brand-a] [-client acme] [-report import-report.csv] shipments.csv"),
+		},
+		{
+			name: "some internal error",
+			args: func(file, report string) []string { return []string{"import", file} },
+			mockBehaviur: func(r *mock_services.MockShipmentService) {
+				r.EXPECT().ImportShipments(gomock.Any()).Return(models.ImportResult{}, errors.New("some internal error"))
+			},
+			expectedError: errors.New("some internal error"),
+		},
+		{
+			name:          "unknown command",
+			args:          func(file, report string) []string { return []string{"export", file} },
+			mockBehaviur:  func(r *mock_services.MockShipmentService) {},
+			expectedError: fmt.Errorf("%w %q", ErrorUnknownCommand, "export"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipment := mock_services.NewMockShipmentService(c)
+			tC.mockBehaviur(shipment)
+
+			tenants, err := services.InitTenantService(tC.tenants, nil)
+			require.NoError(t, err)
+
+			dir := t.TempDir()
+			file := filepath.Join(dir, "shipments.csv")
+			require.NoError(t, os.WriteFile(file, []byte("fromName\n"), 0o600))
+			report := filepath.Join(dir, "report.csv")
+
+			// Run command
+			var output bytes.Buffer
+			err = Run(tC.args(file, report), shipment, nil, tenants, &output)
+
+			// Require
+			require.Equal(t, tC.expectedError, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tC.expectedOutput(report), output.String())
+
+			written, err := os.ReadFile(report)
+			if tC.expectedReport == "" {
+				require.True(t, os.IsNotExist(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tC.expectedReport, string(written))
+		})
+	}
+}
@@ -55,3 +55,158 @@ func GetDBPassword() string {
 	}
 	return str
 }
+
+// get rate card source from .env ("file", "db" or empty for the default rate card)
+func GetRateCardSource() string {
+	str, ok := os.LookupEnv("RATE_CARD_SOURCE")
+	if !ok {
+		return ""
+	}
+	return str
+}
+
+// get rate card file path from .env
+func GetRateCardPath() string {
+	str, ok := os.LookupEnv("RATE_CARD_PATH")
+	if !ok {
+		logrus.Error("can`t read .env file (rate card path)")
+		return ""
+	}
+	return str
+}
+
+// get fx rates source from .env ("file", "db" or empty for no conversion)
+func GetFxRatesSource() string {
+	str, ok := os.LookupEnv("FX_RATES_SOURCE")
+	if !ok {
+		return ""
+	}
+	return str
+}
+
+// get fx rates file path from .env
+func GetFxRatesPath() string {
+	str, ok := os.LookupEnv("FX_RATES_PATH")
+	if !ok {
+		logrus.Error("can`t read .env file (fx rates path)")
+		return ""
+	}
+	return str
+}
+
+// get prefix (service indicator) of tracking numbers from .env
+func GetTrackingPrefix() string {
+	str, ok := os.LookupEnv("TRACKING_PREFIX")
+	if !ok {
+		return "SH"
+	}
+	return str
+}
+
+// get country code of tracking numbers from .env
+func GetTrackingCountry() string {
+	str, ok := os.LookupEnv("TRACKING_COUNTRY")
+	if !ok {
+		return "SE"
+	}
+	return str
+}
+
+// get the last status in which shipments may be edited from .env
+func GetEditableUntilStatus() string {
+	str, ok := os.LookupEnv("SHIPMENT_EDITABLE_UNTIL")
+	if !ok {
+		return "created"
+	}
+	return str
+}
+
+// get the last status in which shipments are cancelled for free from .env
+func GetFreeCancellationUntilStatus() string {
+	str, ok := os.LookupEnv("FREE_CANCELLATION_UNTIL")
+	if !ok {
+		return "label_printed"
+	}
+	return str
+}
+
+// get share of the price (percent) which is kept on late cancellation from .env
+func GetCancellationFeePercent() string {
+	str, ok := os.LookupEnv("CANCELLATION_FEE_PERCENT")
+	if !ok {
+		return "20"
+	}
+	return str
+}
+
+// get time for which idempotency keys are kept (Go duration, e.g. 24h) from .env
+func GetIdempotencyKeyTTL() string {
+	str, ok := os.LookupEnv("IDEMPOTENCY_KEY_TTL")
+	if !ok {
+		return "24h"
+	}
+	return str
+}
+
+// get comma-separated origins which may call the API from a browser from .env (none by default)
+func GetCORSAllowedOrigins() string {
+	str, ok := os.LookupEnv("CORS_ALLOWED_ORIGINS")
+	if !ok {
+		return ""
+	}
+	return str
+}
+
+// get HS256 secret of the staff tokens from .env (HS256 tokens are rejected without it)
+func GetJWTSecret() string {
+	str, ok := os.LookupEnv("JWT_HS256_SECRET")
+	if !ok {
+		return ""
+	}
+	return str
+}
+
+// get path of the PEM public key of the RS256 staff tokens from .env
+func GetJWTPublicKeyPath() string {
+	str, ok := os.LookupEnv("JWT_RS256_PUBLIC_KEY_PATH")
+	if !ok {
+		return ""
+	}
+	return str
+}
+
+// get path of the JWKS file with the public keys of the RS256 staff tokens from .env
+func GetJWKSPath() string {
+	str, ok := os.LookupEnv("JWT_JWKS_PATH")
+	if !ok {
+		return ""
+	}
+	return str
+}
+
+// get expected issuer of the staff tokens from .env (not checked by default)
+func GetJWTIssuer() string {
+	str, ok := os.LookupEnv("JWT_ISSUER")
+	if !ok {
+		return ""
+	}
+	return str
+}
+
+// get expected audience of the staff tokens from .env (not checked by default)
+func GetJWTAudience() string {
+	str, ok := os.LookupEnv("JWT_AUDIENCE")
+	if !ok {
+		return ""
+	}
+	return str
+}
+
+// get path of the tenants file from .env (single tenant if empty)
+func GetTenantsPath() string {
+	str, ok := os.LookupEnv("TENANTS_PATH")
+	if !ok {
+		return ""
+	}
+	return str
+}
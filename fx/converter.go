@@ -0,0 +1,133 @@
+package fx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	ErrorUnsupportedRatesFormat error = errors.New("unsupported fx rates format")
+	ErrorInvalidRate            error = errors.New("fx rate must be positive")
+	ErrorRateNotFound           error = errors.New("fx rate not found")
+)
+
+type Converter interface {
+	Rate(from, to string, at time.Time) (models.FxRate, error)
+	Convert(amount money.Money, currency string, at time.Time) (money.Money, models.FxRate, error)
+}
+
+type converter struct {
+	// rates by currency pair, sorted by effective date
+	rates map[string][]models.FxRate
+}
+
+func InitConverter(rates []models.FxRate) (Converter, error) {
+	c := &converter{rates: map[string][]models.FxRate{}}
+
+	for _, rate := range rates {
+		if err := helpers.ValidateCurrencyCode(rate.From); err != nil {
+			return nil, fmt.Errorf("fx rate %s/%s: %w", rate.From, rate.To, err)
+		}
+		if err := helpers.ValidateCurrencyCode(rate.To); err != nil {
+			return nil, fmt.Errorf("fx rate %s/%s: %w", rate.From, rate.To, err)
+		}
+		if rate.Rate <= 0 {
+			return nil, fmt.Errorf("fx rate %s/%s: %w", rate.From, rate.To, ErrorInvalidRate)
+		}
+
+		key := pair(rate.From, rate.To)
+		c.rates[key] = append(c.rates[key], rate)
+	}
+
+	for _, pairRates := range c.rates {
+		sort.Slice(pairRates, func(i, j int) bool {
+			return pairRates[i].EffectiveFrom.Before(pairRates[j].EffectiveFrom)
+		})
+	}
+
+	return c, nil
+}
+
+// get the latest rate which is effective at the given time
+// (inverse rate is used when there is no direct one)
+func (c *converter) Rate(from, to string, at time.Time) (models.FxRate, error) {
+	if from == to {
+		return models.FxRate{From: from, To: to, Rate: 1}, nil
+	}
+
+	if rate, ok := c.effective(from, to, at); ok {
+		return rate, nil
+	}
+
+	if rate, ok := c.effective(to, from, at); ok {
+		return models.FxRate{
+			From:          from,
+			To:            to,
+			Rate:          1 / rate.Rate,
+			EffectiveFrom: rate.EffectiveFrom,
+		}, nil
+	}
+
+	return models.FxRate{}, fmt.Errorf("%s/%s: %w", from, to, ErrorRateNotFound)
+}
+
+// convert money to the currency by the rate effective at the given time
+func (c *converter) Convert(amount money.Money, currency string, at time.Time) (money.Money, models.FxRate, error) {
+	rate, err := c.Rate(amount.Currency, currency, at)
+	if err != nil {
+		return money.Money{}, models.FxRate{}, err
+	}
+
+	return amount.Convert(currency, rate.Rate), rate, nil
+}
+
+func (c *converter) effective(from, to string, at time.Time) (models.FxRate, bool) {
+	pairRates := c.rates[pair(from, to)]
+	for i := len(pairRates) - 1; i >= 0; i-- {
+		if !pairRates[i].EffectiveFrom.After(at) {
+			return pairRates[i], true
+		}
+	}
+	return models.FxRate{}, false
+}
+
+func pair(from, to string) string {
+	return from + "/" + to
+}
+
+// load fx rates from YAML or JSON file
+func LoadRatesFile(path string) ([]models.FxRate, error) {
+	var table struct {
+		Rates []models.FxRate `json:"rates" yaml:"rates"`
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &table)
+	case ".json":
+		err = json.Unmarshal(data, &table)
+	default:
+		return nil, ErrorUnsupportedRatesFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return table.Rates, nil
+}
@@ -0,0 +1,152 @@
+package fx
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	jan = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb = time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestConverter_Rate(t *testing.T) {
+	converter, err := InitConverter([]models.FxRate{
+		{From: "EUR", To: "SEK", Rate: 10.5, EffectiveFrom: feb},
+		{From: "EUR", To: "SEK", Rate: 10, EffectiveFrom: jan},
+		{From: "USD", To: "EUR", Rate: 0.8, EffectiveFrom: jan},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		at       time.Time
+		expected models.FxRate
+		err      error
+	}{
+		{
+			name:     "the same currency",
+			from:     "EUR",
+			to:       "EUR",
+			at:       jan,
+			expected: models.FxRate{From: "EUR", To: "EUR", Rate: 1},
+		},
+		{
+			name:     "rate effective in January",
+			from:     "EUR",
+			to:       "SEK",
+			at:       feb.Add(-time.Second),
+			expected: models.FxRate{From: "EUR", To: "SEK", Rate: 10, EffectiveFrom: jan},
+		},
+		{
+			name:     "latest rate",
+			from:     "EUR",
+			to:       "SEK",
+			at:       feb.AddDate(1, 0, 0),
+			expected: models.FxRate{From: "EUR", To: "SEK", Rate: 10.5, EffectiveFrom: feb},
+		},
+		{
+			name:     "inverse rate",
+			from:     "EUR",
+			to:       "USD",
+			at:       feb,
+			expected: models.FxRate{From: "EUR", To: "USD", Rate: 1.25, EffectiveFrom: jan},
+		},
+		{
+			name: "no rate yet",
+			from: "EUR",
+			to:   "SEK",
+			at:   jan.Add(-time.Second),
+			err:  ErrorRateNotFound,
+		},
+		{
+			name: "unknown pair",
+			from: "SEK",
+			to:   "USD",
+			at:   feb,
+			err:  ErrorRateNotFound,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual, err := converter.Rate(tC.from, tC.to, tC.at)
+			require.True(t, errors.Is(err, tC.err), "expected %v, got %v", tC.err, err)
+			require.Equal(t, tC.expected, actual)
+		})
+	}
+}
+
+func TestConverter_Convert(t *testing.T) {
+	converter, err := InitConverter([]models.FxRate{
+		{From: "EUR", To: "SEK", Rate: 10.4567, EffectiveFrom: jan},
+	})
+	require.NoError(t, err)
+
+	actual, rate, err := converter.Convert(money.New(500000, "EUR"), "SEK", feb)
+	require.NoError(t, err)
+	require.Equal(t, money.New(5228350, "SEK"), actual)
+	require.Equal(t, 10.4567, rate.Rate)
+}
+
+func TestInitConverter(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rates []models.FxRate
+		err   error
+	}{
+		{
+			name:  "invalid currency",
+			rates: []models.FxRate{{From: "EURO", To: "SEK", Rate: 10}},
+			err:   helpers.ErrorInvalidCurrencyCode,
+		},
+		{
+			name:  "not existing currency",
+			rates: []models.FxRate{{From: "EUR", To: "ABC", Rate: 10}},
+			err:   helpers.ErrorNotExistingCurrencyCode,
+		},
+		{
+			name:  "zero rate",
+			rates: []models.FxRate{{From: "EUR", To: "SEK"}},
+			err:   ErrorInvalidRate,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			_, err := InitConverter(tC.rates)
+			require.True(t, errors.Is(err, tC.err), "expected %v, got %v", tC.err, err)
+		})
+	}
+}
+
+func TestLoadRatesFile(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		rates, err := LoadRatesFile("../ratecards/fx.yaml")
+		require.NoError(t, err)
+		require.Len(t, rates, 4)
+		require.Equal(t, models.FxRate{From: "EUR", To: "SEK", Rate: 10.4567, EffectiveFrom: jan}, rates[0])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		rates, err := LoadRatesFile("./fixtures/rates.json")
+		require.NoError(t, err)
+		require.Equal(t, []models.FxRate{
+			{From: "EUR", To: "SEK", Rate: 10.4567, EffectiveFrom: jan},
+			{From: "USD", To: "EUR", Rate: 0.8836, EffectiveFrom: jan},
+		}, rates)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := LoadRatesFile("./fixtures/rates.csv")
+		require.Equal(t, ErrorUnsupportedRatesFormat, err)
+	})
+}
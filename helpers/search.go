@@ -0,0 +1,23 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// words of the text (emails are kept as a single word)
+var searchWordRegexp = regexp.MustCompile(`[\p{L}\p{N}]+(?:[@._-][\p{L}\p{N}]+)*`)
+
+// split search text into lower case words
+func SearchWords(text string) []string {
+	words := searchWordRegexp.FindAllString(text, -1)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return words
+}
+
+// positions of the words in the text
+func SearchWordIndexes(text string) [][]int {
+	return searchWordRegexp.FindAllStringIndex(text, -1)
+}
@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchWords(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{
+			name:     "sentence",
+			text:     "the parcel to Iryna in Toronto",
+			expected: []string{"the", "parcel", "to", "iryna", "in", "toronto"},
+		},
+		{
+			name:     "email and address",
+			text:     "testTo@g.c, Toronto, 34",
+			expected: []string{"testto@g.c", "toronto", "34"},
+		},
+		{
+			name:     "cyrillic letters",
+			text:     "Ірина Київ",
+			expected: []string{"ірина", "київ"},
+		},
+		{
+			name:     "only punctuation",
+			text:     " ,. ",
+			expected: []string{},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual := SearchWords(tC.text)
+			if len(tC.expected) == 0 {
+				require.Empty(t, actual)
+				return
+			}
+			require.Equal(t, tC.expected, actual)
+		})
+	}
+}
@@ -9,10 +9,12 @@ import (
 
  var (
 	ErrorInvalidEmail error = errors.New("invalid email")
-	ErrorInvalidName error = errors.New("invalid email")
+	ErrorInvalidName error = errors.New("invalid name")
 	ErrorInvalidAddress error = errors.New("invalid address")
 	ErrorInvalidCountryCode error = errors.New("invalid country code")
 	ErrorNotExistingCountryCode error = errors.New("not existing country code")
+	ErrorInvalidCurrencyCode error = errors.New("invalid currency code")
+	ErrorNotExistingCurrencyCode error = errors.New("not existing currency code")
  )
 
 // validate email
@@ -54,6 +56,21 @@ func ValidateCountryCode(code string) error {
 	return nil
 }
 
+// validate currency code
+func ValidateCurrencyCode(code string) error {
+	var codeReg = regexp.MustCompile("^[A-Z]{3}$")
+	if !codeReg.MatchString(code) {
+		return ErrorInvalidCurrencyCode
+	}
+
+	// check if there is a currency with such a code
+	if !countries.CurrencyCodeByName(code).IsValid() {
+		return ErrorNotExistingCurrencyCode
+	}
+
+	return nil
+}
+
 // validate address
 func ValidateAddress(address string) error {
 	if len(address) > 100 {
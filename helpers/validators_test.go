@@ -117,6 +117,42 @@ func TestValidateCountryCode(t *testing.T) {
 	}
 }
 
+func TestValidateCurrencyCode(t *testing.T) {
+	testCases := []struct{
+		name string
+		code string
+		err error
+	}{
+		{
+			name: "correct currency code",
+			code: "SEK",
+			err: nil,
+		},
+		{
+			name: "invalid currency code (long word)",
+			code: "EURO",
+			err: ErrorInvalidCurrencyCode,
+		},
+		{
+			name: "invalid currency code (lovercase)",
+			code: "eur",
+			err: ErrorInvalidCurrencyCode,
+		},
+		{
+			name: "not existing currency code",
+			code: "ABC",
+			err: ErrorNotExistingCurrencyCode,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual := ValidateCurrencyCode(tC.code)
+			require.Equal(t, tC.err, actual)
+		})
+	}
+}
+
 func TestValidateAddress(t *testing.T) {
 	testCases := []struct{
 		name string
@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRSAPublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	parsed, err := ParseRSAPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}))
+	require.NoError(t, err)
+	require.Equal(t, &key.PublicKey, parsed)
+
+	pkcs1 := x509.MarshalPKCS1PublicKey(&key.PublicKey)
+	parsed, err = ParseRSAPublicKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: pkcs1}))
+	require.NoError(t, err)
+	require.Equal(t, &key.PublicKey, parsed)
+
+	_, err = ParseRSAPublicKey([]byte("secret"))
+	require.Equal(t, fmt.Errorf("%w: no PEM block", ErrorInvalidKey), err)
+}
+
+func TestParseJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+
+	// keys of other types and uses are skipped
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "use": "sig", "alg": "RS256", "kid": "key-1", "n": %q, "e": %q},
+		{"kty": "RSA", "use": "enc", "kid": "key-2", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "key-3", "crv": "P-256", "x": "AQ", "y": "AQ"}
+	]}`, n, e, n, e)
+
+	keys, err := ParseJWKS([]byte(jwks))
+	require.NoError(t, err)
+	require.Equal(t, map[string]*rsa.PublicKey{"key-1": &key.PublicKey}, keys)
+
+	_, err = ParseJWKS([]byte(fmt.Sprintf(`{"keys": [{"kty": "RSA", "kid": "key-1", "n": %q, "e": %q}, {"kty": "RSA", "kid": "key-1", "n": %q, "e": %q}]}`, n, e, n, e)))
+	require.Equal(t, fmt.Errorf("%w: duplicated key %q", ErrorInvalidKey, "key-1"), err)
+
+	_, err = ParseJWKS([]byte(`{"keys": [{"kty": "RSA", "kid": "key-1", "n": "AQ", "e": ""}]}`))
+	require.Equal(t, fmt.Errorf("%w: key %q", ErrorInvalidKey, "key-1"), err)
+
+	_, err = ParseJWKS([]byte(`keys`))
+	require.True(t, errors.Is(err, ErrorInvalidKey))
+}
@@ -0,0 +1,165 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrorInvalidToken         error = errors.New("invalid token")
+	ErrorTokenExpired         error = errors.New("token expired")
+	ErrorUnsupportedAlgorithm error = errors.New("unsupported token algorithm")
+	ErrorUnknownKey           error = errors.New("unknown token key")
+)
+
+// keys which sign the accepted tokens
+type Keys struct {
+	// HS256 secret (HS256 tokens are rejected without it)
+	Secret []byte
+	// RS256 public keys by key ID (the key without ID is used for the tokens without "kid")
+	RSA map[string]*rsa.PublicKey
+}
+
+// claims of the staff token
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Role      string   `json:"role"`
+	Issuer    string   `json:"iss"`
+	Audience  Audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+}
+
+// "aud" claim is either a string or an array of strings
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a Audience) contains(audience string) bool {
+	for _, aud := range a {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
+
+type Verifier interface {
+	Verify(token string) (Claims, error)
+}
+
+type verifier struct {
+	keys Keys
+	// expected "iss" and "aud" claims (not checked if empty)
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+func InitVerifier(keys Keys, issuer, audience string, now func() time.Time) Verifier {
+	return &verifier{keys: keys, issuer: issuer, audience: audience, now: now}
+}
+
+// check the signature and the claims of the compact JWS token
+func (v *verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrorInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Claims{}, ErrorInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrorInvalidToken
+	}
+	if err := v.verifySignature(header.Alg, header.Kid, parts[0]+"."+parts[1], signature); err != nil {
+		return Claims{}, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Claims{}, ErrorInvalidToken
+	}
+
+	// tokens without expiration are not accepted
+	now := v.now().Unix()
+	if claims.ExpiresAt == 0 || now >= claims.ExpiresAt {
+		return Claims{}, ErrorTokenExpired
+	}
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return Claims{}, ErrorInvalidToken
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return Claims{}, ErrorInvalidToken
+	}
+	if v.audience != "" && !claims.Audience.contains(v.audience) {
+		return Claims{}, ErrorInvalidToken
+	}
+
+	return claims, nil
+}
+
+// the algorithm of the token is accepted only if a key of its kind is configured
+func (v *verifier) verifySignature(alg, kid, signed string, signature []byte) error {
+	switch alg {
+	case "HS256":
+		if len(v.keys.Secret) == 0 {
+			return ErrorUnsupportedAlgorithm
+		}
+		mac := hmac.New(sha256.New, v.keys.Secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrorInvalidToken
+		}
+		return nil
+	case "RS256":
+		if len(v.keys.RSA) == 0 {
+			return ErrorUnsupportedAlgorithm
+		}
+		key, ok := v.keys.RSA[kid]
+		if !ok {
+			return ErrorUnknownKey
+		}
+		hash := sha256.Sum256([]byte(signed))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature); err != nil {
+			return ErrorInvalidToken
+		}
+		return nil
+	}
+
+	return ErrorUnsupportedAlgorithm
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,187 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC)
+
+// compact token of the header and claims signed by the HS256 secret or the RSA private key
+func signToken(t *testing.T, header, claims map[string]interface{}, key interface{}) string {
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(header) + "." + encode(claims)
+
+	var signature []byte
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		hash := sha256.Sum256([]byte(signed))
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+		require.NoError(t, err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	secret := []byte("test-secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	claims := func(changes map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"sub":  "mark",
+			"role": "operator",
+			"iss":  "https://auth.example.com",
+			"aud":  "shipment",
+			"exp":  testNow.Add(time.Hour).Unix(),
+		}
+		for k, v := range changes {
+			c[k] = v
+		}
+		return c
+	}
+	hs256 := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+	rs256 := map[string]interface{}{"alg": "RS256", "typ": "JWT", "kid": "key-1"}
+
+	expected := Claims{
+		Subject:   "mark",
+		Role:      "operator",
+		Issuer:    "https://auth.example.com",
+		Audience:  Audience{"shipment"},
+		ExpiresAt: testNow.Add(time.Hour).Unix(),
+	}
+
+	testCases := []struct {
+		name           string
+		token          string
+		expectedClaims Claims
+		expectedError  error
+	}{
+		{
+			name:           "HS256",
+			token:          signToken(t, hs256, claims(nil), secret),
+			expectedClaims: expected,
+		},
+		{
+			name:           "RS256",
+			token:          signToken(t, rs256, claims(nil), rsaKey),
+			expectedClaims: expected,
+		},
+		{
+			name:  "audience array",
+			token: signToken(t, hs256, claims(map[string]interface{}{"aud": []string{"billing", "shipment"}}), secret),
+			expectedClaims: Claims{
+				Subject:   "mark",
+				Role:      "operator",
+				Issuer:    "https://auth.example.com",
+				Audience:  Audience{"billing", "shipment"},
+				ExpiresAt: testNow.Add(time.Hour).Unix(),
+			},
+		},
+		{
+			name:          "wrong secret",
+			token:         signToken(t, hs256, claims(nil), []byte("other-secret")),
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name:          "wrong RSA key",
+			token:         signToken(t, rs256, claims(nil), otherKey),
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name:          "unknown key ID",
+			token:         signToken(t, map[string]interface{}{"alg": "RS256", "kid": "key-2"}, claims(nil), rsaKey),
+			expectedError: ErrorUnknownKey,
+		},
+		{
+			name:          "none algorithm",
+			token:         signToken(t, map[string]interface{}{"alg": "none"}, claims(nil), nil),
+			expectedError: ErrorUnsupportedAlgorithm,
+		},
+		{
+			name:          "expired",
+			token:         signToken(t, hs256, claims(map[string]interface{}{"exp": testNow.Unix()}), secret),
+			expectedError: ErrorTokenExpired,
+		},
+		{
+			name:          "without expiration",
+			token:         signToken(t, hs256, claims(map[string]interface{}{"exp": 0}), secret),
+			expectedError: ErrorTokenExpired,
+		},
+		{
+			name:          "not valid yet",
+			token:         signToken(t, hs256, claims(map[string]interface{}{"nbf": testNow.Add(time.Minute).Unix()}), secret),
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name:          "wrong issuer",
+			token:         signToken(t, hs256, claims(map[string]interface{}{"iss": "https://evil.example.com"}), secret),
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name:          "wrong audience",
+			token:         signToken(t, hs256, claims(map[string]interface{}{"aud": "billing"}), secret),
+			expectedError: ErrorInvalidToken,
+		},
+		{
+			name:          "malformed",
+			token:         "not.a-token",
+			expectedError: ErrorInvalidToken,
+		},
+	}
+
+	verifier := InitVerifier(Keys{
+		Secret: secret,
+		RSA:    map[string]*rsa.PublicKey{"key-1": &rsaKey.PublicKey},
+	}, "https://auth.example.com", "shipment", func() time.Time { return testNow })
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			claims, err := verifier.Verify(tC.token)
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedClaims, claims)
+		})
+	}
+}
+
+func TestVerifier_Verify_notConfiguredAlgorithm(t *testing.T) {
+	secret := []byte("test-secret")
+
+	// an RSA public key must not be used as an HS256 secret, and the other way round
+	verifier := InitVerifier(Keys{Secret: secret}, "", "", func() time.Time { return testNow })
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	exp := map[string]interface{}{"exp": testNow.Add(time.Hour).Unix()}
+	_, err = verifier.Verify(signToken(t, map[string]interface{}{"alg": "RS256"}, exp, rsaKey))
+	require.Equal(t, ErrorUnsupportedAlgorithm, err)
+
+	verifier = InitVerifier(Keys{RSA: map[string]*rsa.PublicKey{"": &rsaKey.PublicKey}}, "", "", func() time.Time { return testNow })
+	_, err = verifier.Verify(signToken(t, map[string]interface{}{"alg": "HS256"}, exp, secret))
+	require.Equal(t, ErrorUnsupportedAlgorithm, err)
+
+	// the key without ID is used for the tokens without "kid"
+	_, err = verifier.Verify(signToken(t, map[string]interface{}{"alg": "RS256"}, exp, rsaKey))
+	require.NoError(t, err)
+}
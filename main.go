@@ -1,8 +1,13 @@
 package main
 
 import (
+	"crypto/rand"
+	"os"
+
 	"github.com/Taras-Rm/shipment/api"
+	"github.com/Taras-Rm/shipment/cli"
 	"github.com/Taras-Rm/shipment/config"
+	"github.com/Taras-Rm/shipment/pricing"
 	"github.com/Taras-Rm/shipment/repositories"
 	"github.com/Taras-Rm/shipment/services"
 	"github.com/Taras-Rm/shipment/setup"
@@ -16,6 +21,70 @@ func main() {
 		panic(err)
 	}
 
+	// db connection
+	db, err := setup.ConnectDB()
+	if err != nil {
+		panic(err)
+	}
+
+	// pricing (rate card is validated at startup)
+	rateCardRepository := repositories.InitRateCardRepository(db)
+	defaultPricingEngine, err := setup.InitPricing(rateCardRepository)
+	if err != nil {
+		panic(err)
+	}
+
+	// shipments priced before the money type were priced in the currency of the loaded rate card
+	err = repositories.MigrateShipmentPrice(db, defaultPricingEngine.RateCard().Currency)
+	if err != nil {
+		panic(err)
+	}
+
+	// admins create new rate card versions while the server runs
+	pricingEngine := pricing.InitSwitchableEngine(defaultPricingEngine)
+
+	// currency conversion
+	fxRateRepository := repositories.InitFxRateRepository(db)
+	fxConverter, err := setup.InitFx(fxRateRepository)
+	if err != nil {
+		panic(err)
+	}
+
+	// tracking numbers of new shipments
+	trackingNumbers, err := setup.InitTracking()
+	if err != nil {
+		panic(err)
+	}
+
+	// rules of changing added shipments
+	shipmentPolicy, err := setup.InitShipmentPolicy()
+	if err != nil {
+		panic(err)
+	}
+
+	shipmentRepository := repositories.InitShipmentRepository(db)
+	trackingEventRepository := repositories.InitTrackingEventRepository(db)
+	shipmentSearchRepository := repositories.InitShipmentSearchRepository(db)
+	shipmentService := services.InitShipmentService(shipmentRepository, trackingEventRepository, shipmentSearchRepository, pricingEngine, fxConverter, trackingNumbers, shipmentPolicy)
+
+	// brands with their own shipments and rate cards
+	tenantService, err := setup.InitTenants()
+	if err != nil {
+		panic(err)
+	}
+
+	// keys of the API clients
+	apiKeyRepository := repositories.InitAPIKeyRepository(db)
+	apiKeyService := services.InitAPIKeyService(apiKeyRepository, tenantService, rand.Reader)
+
+	// subcommand of the command line instead of the server
+	if len(os.Args) > 1 {
+		if err := cli.Run(os.Args[1:], shipmentService, apiKeyService, tenantService, os.Stdout); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	// getting server port
 	port := config.GetServerPort()
 
@@ -23,15 +92,25 @@ func main() {
 	handler := setup.ServerStart()
 	group := handler.Group("api")
 
-	// db connection
-	db, err := setup.ConnectDB()
+	// replay of the retried requests
+	idempotencyRepository := repositories.InitIdempotencyRepository(db)
+	idempotencyService, err := setup.InitIdempotency(idempotencyRepository)
 	if err != nil {
 		panic(err)
 	}
 
-	shipmentRepository := repositories.InitShipmentRepository(db)
-	shipmentService := services.InitShipmentService(shipmentRepository)
-	api.UseShipment(group, shipmentService)
+	// tokens of the staff members
+	tokenVerifier, err := setup.InitJWT()
+	if err != nil {
+		panic(err)
+	}
+
+	api.UseShipment(group, shipmentService, idempotencyService, apiKeyService, tokenVerifier, tenantService)
+	api.UseTracking(group, shipmentService, tenantService)
+
+	// versions of the default rate card
+	rateCardService := setup.InitRateCards(rateCardRepository, pricingEngine)
+	api.UseRateCards(group, rateCardService, apiKeyService, tokenVerifier)
 
 	// start server
 	handler.Run(port)
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// API key of a client (the key itself is shown only once, when it is created)
+type APIKey struct {
+	Id     uint   `json:"id"`
+	Name   string `json:"name"`
+	Client string `json:"client"`
+	// tenant which the client works in (empty without tenants)
+	Tenant string `json:"tenant,omitempty"`
+	// public part of the key which is used to find it
+	Prefix    string     `json:"prefix"`
+	Hash      string     `json:"-"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "github.com/Taras-Rm/shipment/money"
+
+// result of a single shipment of the batch (the shipment is created or has an error)
+type ShipmentBatchResult struct {
+	Index          int          `json:"index"`
+	Id             uint         `json:"id,omitempty"`
+	TrackingNumber string       `json:"trackingNumber,omitempty"`
+	Price          *money.Money `json:"price,omitempty"`
+	Error          string       `json:"error,omitempty"`
+	// invalid fields of the shipment
+	Fields []FieldError `json:"fields,omitempty"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Taras-Rm/shipment/money"
+)
+
+// cancellation of the shipment (price = fee + refund)
+type Cancellation struct {
+	CancelledAt time.Time   `json:"cancelledAt"`
+	Fee         money.Money `json:"fee"`
+	Refund      money.Money `json:"refund"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// exchange rate: 1 unit of From currency costs Rate units of To currency
+type FxRate struct {
+	From          string    `json:"from" yaml:"from"`
+	To            string    `json:"to" yaml:"to"`
+	Rate          float64   `json:"rate" yaml:"rate"`
+	EffectiveFrom time.Time `json:"effectiveFrom" yaml:"effectiveFrom"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// key of the idempotent request with its saved response (no status code while the request is in progress)
+type IdempotencyKey struct {
+	// client which sent the request (keys of different clients don't collide)
+	Owner        string
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
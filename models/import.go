@@ -0,0 +1,15 @@
+package models
+
+// problem of a row of the imported CSV (row is the line number, the header is row 1)
+type ImportError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+}
+
+// result of the CSV import (counts of the rows without the header)
+type ImportResult struct {
+	Created int           `json:"created"`
+	Failed  int           `json:"failed"`
+	Errors  []ImportError `json:"errors"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "github.com/Taras-Rm/shipment/money"
+
+type PriceComponentType string
+
+const (
+	PriceComponentBase      PriceComponentType = "base"
+	PriceComponentLane      PriceComponentType = "lane"
+	PriceComponentSurcharge PriceComponentType = "surcharge"
+	PriceComponentDiscount  PriceComponentType = "discount"
+	PriceComponentReturns   PriceComponentType = "returns"
+)
+
+// single line of the price breakdown (amount is added to the total)
+type PriceComponent struct {
+	Type   PriceComponentType `json:"type"`
+	Name   string             `json:"name"`
+	Factor float64            `json:"factor,omitempty"`
+	Amount money.Money        `json:"amount"`
+}
+
+// physical parcel (weight in kg, dimensions in cm)
+type Parcel struct {
+	Weight float64
+	Length float64
+	Width  float64
+	Height float64
+}
+
+// itemized price of the shipment
+type PriceBreakdown struct {
+	RateCardVersion  string           `json:"rateCardVersion"`
+	Lane             Lane             `json:"lane"`
+	VolumetricWeight float64          `json:"volumetricWeight,omitempty"`
+	BillableWeight   float64          `json:"billableWeight"`
+	Components       []PriceComponent `json:"components"`
+	Total            money.Money      `json:"total"`
+	FxRate           *FxRate          `json:"fxRate,omitempty"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// rate card describes all the rules which are used for pricing
+type RateCard struct {
+	Version          string             `json:"version" yaml:"version"`
+	EffectiveFrom    time.Time          `json:"effectiveFrom" yaml:"effectiveFrom"`
+	Currency         string             `json:"currency" yaml:"currency"`
+	Regions          []Region           `json:"regions" yaml:"regions"`
+	DefaultRegion    string             `json:"defaultRegion" yaml:"defaultRegion"`
+	DefaultFactor    float64            `json:"defaultFactor" yaml:"defaultFactor"`
+	DomesticFactor   float64            `json:"domesticFactor" yaml:"domesticFactor"`
+	ZoneMatrix       ZoneMatrix         `json:"zoneMatrix" yaml:"zoneMatrix"`
+	CountryOverrides map[string]float64 `json:"countryOverrides" yaml:"countryOverrides"`
+	WeightBrackets   []WeightBracket    `json:"weightBrackets" yaml:"weightBrackets"`
+	MaxWeight        float64            `json:"maxWeight" yaml:"maxWeight"`
+	// cm³ per kg of volumetric weight (0 disables volumetric pricing)
+	VolumetricDivisor float64      `json:"volumetricDivisor" yaml:"volumetricDivisor"`
+	Multipliers       []Multiplier `json:"multipliers" yaml:"multipliers"`
+	// factor of the return shipments price (0 prices returns as regular shipments)
+	ReturnsFactor float64 `json:"returnsFactor" yaml:"returnsFactor"`
+}
+
+// region is matched by country code or by continent
+type Region struct {
+	Name      string   `json:"name" yaml:"name"`
+	Factor    float64  `json:"factor" yaml:"factor"`
+	Countries []string `json:"countries" yaml:"countries"`
+	Continent string   `json:"continent" yaml:"continent"`
+}
+
+// origin zone -> destination zone -> factor
+type ZoneMatrix map[string]map[string]float64
+
+type LaneType string
+
+const (
+	LaneDomestic         LaneType = "domestic"
+	LaneIntraRegion      LaneType = "intra_region"
+	LaneInterRegion      LaneType = "inter_region"
+	LaneIntercontinental LaneType = "intercontinental"
+)
+
+// lane between origin and destination zones
+type Lane struct {
+	Type        LaneType `json:"type"`
+	Origin      string   `json:"origin"`
+	Destination string   `json:"destination"`
+	Factor      float64  `json:"factor"`
+}
+
+// weight bracket starts at From (inclusive) and lasts until the next bracket
+// (amount is in major units of the rate card currency)
+type WeightBracket struct {
+	From   float64 `json:"from" yaml:"from"`
+	Amount float64 `json:"amount" yaml:"amount"`
+}
+
+// multiplier is applied to the whole price (fuel, peak season, etc.)
+type Multiplier struct {
+	Name   string  `json:"name" yaml:"name"`
+	Factor float64 `json:"factor" yaml:"factor"`
+}
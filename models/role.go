@@ -0,0 +1,35 @@
+package models
+
+// role of the staff member (from the bearer token)
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermissionViewShipments   Permission = "shipments:view"
+	PermissionManageShipments Permission = "shipments:manage"
+	PermissionManageRateCards Permission = "ratecards:manage"
+)
+
+// permissions of every role (a higher role has the permissions of the lower ones)
+var rolePermissions = map[Role][]Permission{
+	RoleViewer:   {PermissionViewShipments},
+	RoleOperator: {PermissionViewShipments, PermissionManageShipments},
+	RoleAdmin:    {PermissionViewShipments, PermissionManageShipments, PermissionManageRateCards},
+}
+
+// unknown roles have no permissions
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
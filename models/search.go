@@ -0,0 +1,21 @@
+package models
+
+// full-text search of shipments by names, emails and addresses
+type SearchQuery struct {
+	// words of the query (shipment matches if it contains any of them)
+	Terms []string
+	Limit int
+}
+
+// shipment found by the search with highlighted matches by field
+type SearchResult struct {
+	Shipment   Shipment          `json:"shipment"`
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// matched words are wrapped into these tags in the highlights (the text of highlights is HTML-escaped)
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
@@ -1,7 +1,10 @@
 package models
 
+import "github.com/Taras-Rm/shipment/money"
+
 type Shipment struct {
 	Id              uint
+	TrackingNumber  string `json:",omitempty"`
 	FromName        string
 	FromEmail       string
 	FromAddress     string
@@ -11,5 +14,36 @@ type Shipment struct {
 	ToAddress       string
 	ToCountryCode   string
 	Weight          float64
-	Price           float64
+	Length          float64 `json:",omitempty"`
+	Width           float64 `json:",omitempty"`
+	Height          float64 `json:",omitempty"`
+	Price           money.Money
+	Status          ShipmentStatus  `json:",omitempty"`
+	PriceBreakdown  *PriceBreakdown `json:",omitempty"`
+	FxRate          *FxRate         `json:",omitempty"`
+	Pieces          []Piece         `json:",omitempty"`
+	Cancellation    *Cancellation   `json:",omitempty"`
+	ReturnOf        *ShipmentLink   `json:",omitempty"`
+	Returns         []ShipmentLink  `json:",omitempty"`
+	// client which created the shipment (it isn't shown to the client)
+	Owner string `json:"-"`
+	// brand which the shipment belongs to
+	TenantID string `json:"-"`
+}
+
+// short reference to the related shipment
+type ShipmentLink struct {
+	Id             uint           `json:"id"`
+	TrackingNumber string         `json:"trackingNumber,omitempty"`
+	Status         ShipmentStatus `json:"status,omitempty"`
+}
+
+// single parcel of the shipment
+type Piece struct {
+	Weight         float64     `json:"weight"`
+	Length         float64     `json:"length,omitempty"`
+	Width          float64     `json:"width,omitempty"`
+	Height         float64     `json:"height,omitempty"`
+	BillableWeight float64     `json:"billableWeight"`
+	Price          money.Money `json:"price"`
 }
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Taras-Rm/shipment/money"
+)
+
+type ShipmentSortField string
+
+const (
+	SortByID        ShipmentSortField = "id"
+	SortByCreatedAt ShipmentSortField = "createdAt"
+	SortByWeight    ShipmentSortField = "weight"
+	SortByPrice     ShipmentSortField = "price"
+)
+
+// filters of the shipments list (zero values are not applied)
+type ShipmentFilter struct {
+	FromCountryCode string
+	ToCountryCode   string
+	MinWeight       float64
+	MaxWeight       float64
+	// price range is applied to the shipments in the currency of the range
+	MinPrice    *money.Money
+	MaxPrice    *money.Money
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// email of the sender or the recipient
+	Email string
+}
+
+// page of the shipments list which starts after the cursor
+type ShipmentQuery struct {
+	Filter ShipmentFilter
+	SortBy ShipmentSortField
+	Desc   bool
+	Cursor string
+	Limit  int
+}
+
+type ShipmentPage struct {
+	Shipments  []Shipment `json:"shipments"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+	Total      int64      `json:"total"`
+}
@@ -0,0 +1,14 @@
+package models
+
+type ShipmentStatus string
+
+const (
+	StatusCreated        ShipmentStatus = "created"
+	StatusLabelPrinted   ShipmentStatus = "label_printed"
+	StatusPickedUp       ShipmentStatus = "picked_up"
+	StatusInTransit      ShipmentStatus = "in_transit"
+	StatusOutForDelivery ShipmentStatus = "out_for_delivery"
+	StatusDelivered      ShipmentStatus = "delivered"
+	StatusReturned       ShipmentStatus = "returned"
+	StatusCancelled      ShipmentStatus = "cancelled"
+)
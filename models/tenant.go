@@ -0,0 +1,24 @@
+package models
+
+// brand which the service is run for (shipments of a tenant are not seen by the other tenants)
+type Tenant struct {
+	Id   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	// host names of the tenant, e.g. brand-a.shipments.example.com
+	Hosts []string `json:"hosts" yaml:"hosts"`
+	// rate card of the tenant (the default rate card is used if empty)
+	RateCardPath string `json:"rateCardPath" yaml:"rateCardPath"`
+	// how the tenant is shown to the recipients
+	Branding Branding `json:"branding" yaml:"branding"`
+}
+
+// branding of the recipient facing outputs (all fields are optional)
+type Branding struct {
+	// name shown to the recipients (the tenant name is used if empty)
+	DisplayName string `json:"displayName" yaml:"displayName"`
+	// absolute https URL of the logo
+	LogoURL string `json:"logoUrl" yaml:"logoUrl"`
+	// hex color, e.g. #1a73e8
+	Color        string `json:"color" yaml:"color"`
+	SupportEmail string `json:"supportEmail" yaml:"supportEmail"`
+}
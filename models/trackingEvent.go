@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// scan event of the shipment
+type TrackingEvent struct {
+	Id         uint          `json:"id"`
+	OccurredAt time.Time     `json:"occurredAt"`
+	Code       string        `json:"code"`
+	Location   EventLocation `json:"location"`
+	Note       string        `json:"note,omitempty"`
+}
+
+// place where the shipment was scanned
+type EventLocation struct {
+	Facility    string `json:"facility,omitempty"`
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
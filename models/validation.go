@@ -0,0 +1,10 @@
+package models
+
+// invalid field of the request
+type FieldError struct {
+	// JSON path of the field, e.g. pieces[1].weight
+	Path string `json:"path"`
+	// machine-readable reason, e.g. invalid_email
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,145 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/biter777/countries"
+)
+
+var (
+	ErrorCurrencyMismatch error = errors.New("currency mismatch")
+	ErrorInvalidAmount    error = errors.New("invalid amount")
+)
+
+// exact amount of money in minor units (cents, öre, ...) of ISO 4217 currency
+//
+// Rounding rules:
+//   - factors and major amounts are taken by their shortest decimal representation (1.1 is exactly 1.1)
+//   - the result of every multiplication is rounded to minor units, half away from zero
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// convert amount in major units (e.g. 12.5 EUR) to money
+func FromMajor(value float64, currency string) Money {
+	return Money{Amount: round(decimal(value), Digits(currency)), Currency: currency}
+}
+
+// parse amount in major units ("12.50") to money
+func Parse(value string, currency string) (Money, error) {
+	r, ok := new(big.Rat).SetString(value)
+	if !ok || strings.ContainsAny(value, "eE/") {
+		return Money{}, ErrorInvalidAmount
+	}
+	return Money{Amount: round(r, Digits(currency)), Currency: currency}, nil
+}
+
+// number of minor unit digits of the currency (2 for unknown currencies)
+func Digits(currency string) int {
+	digits := countries.CurrencyCodeByName(currency).Digits()
+	if digits < 0 {
+		return 2
+	}
+	return digits
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrorCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	return m.Add(other.Neg())
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// multiply money by factor (rounded half away from zero)
+func (m Money) Mul(factor float64) Money {
+	r := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), decimal(factor))
+	return Money{Amount: round(r, 0), Currency: m.Currency}
+}
+
+// amount in major units as decimal string ("5000.00")
+func (m Money) Decimal() string {
+	digits := Digits(m.Currency)
+	r := new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(digits))
+	return r.FloatString(digits)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+type jsonMoney struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var j jsonMoney
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	parsed, err := Parse(j.Amount, j.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// exact value of the shortest decimal representation of float
+func decimal(value float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	return r
+}
+
+// round value * 10^digits half away from zero
+func round(value *big.Rat, digits int) int64 {
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(pow10(digits)))
+
+	// add a half and truncate towards zero
+	half := big.NewRat(1, 2)
+	if scaled.Sign() < 0 {
+		half.Neg(half)
+	}
+	scaled.Add(scaled, half)
+
+	return new(big.Int).Quo(scaled.Num(), scaled.Denom()).Int64()
+}
+
+// convert money to another currency by exchange rate (rounded half away from zero)
+func (m Money) Convert(currency string, rate float64) Money {
+	// minor units of both currencies may have different number of digits
+	r := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), decimal(rate))
+	r.Mul(r, new(big.Rat).SetFrac(pow10(Digits(currency)), pow10(Digits(m.Currency))))
+	return Money{Amount: round(r, 0), Currency: currency}
+}
+
+func pow10(digits int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+}
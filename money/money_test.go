@@ -0,0 +1,170 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromMajor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    float64
+		currency string
+		expected Money
+	}{
+		{
+			name:     "whole amount",
+			value:    2000,
+			currency: "EUR",
+			expected: New(200000, "EUR"),
+		},
+		{
+			name:     "fractional amount",
+			value:    19.99,
+			currency: "SEK",
+			expected: New(1999, "SEK"),
+		},
+		{
+			name:     "half of minor unit is rounded away from zero",
+			value:    0.125,
+			currency: "USD",
+			expected: New(13, "USD"),
+		},
+		{
+			name:     "currency without minor units",
+			value:    1234.5,
+			currency: "JPY",
+			expected: New(1235, "JPY"),
+		},
+		{
+			name:     "currency with three digits",
+			value:    1.2345,
+			currency: "BHD",
+			expected: New(1235, "BHD"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual := FromMajor(tC.value, tC.currency)
+			require.Equal(t, tC.expected, actual)
+		})
+	}
+}
+
+func TestMoney_Mul(t *testing.T) {
+	testCases := []struct {
+		name     string
+		money    Money
+		factor   float64
+		expected Money
+	}{
+		{
+			name:     "exact result",
+			money:    New(200000, "EUR"),
+			factor:   2.5,
+			expected: New(500000, "EUR"),
+		},
+		{
+			name:     "decimal factor is exact",
+			money:    New(10, "EUR"),
+			factor:   0.15,
+			expected: New(2, "EUR"),
+		},
+		{
+			name:     "factor with float artifacts",
+			money:    New(10000, "EUR"),
+			factor:   1.1,
+			expected: New(11000, "EUR"),
+		},
+		{
+			name:     "negative half is rounded away from zero",
+			money:    New(-5, "EUR"),
+			factor:   0.5,
+			expected: New(-3, "EUR"),
+		},
+		{
+			name:     "below half is rounded down",
+			money:    New(82500, "EUR"),
+			factor:   0.3331,
+			expected: New(27481, "EUR"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual := tC.money.Mul(tC.factor)
+			require.Equal(t, tC.expected, actual)
+		})
+	}
+}
+
+func TestMoney_Add(t *testing.T) {
+	t.Run("same currency", func(t *testing.T) {
+		actual, err := New(150, "EUR").Add(New(-50, "EUR"))
+		require.NoError(t, err)
+		require.Equal(t, New(100, "EUR"), actual)
+	})
+
+	t.Run("different currencies", func(t *testing.T) {
+		_, err := New(150, "EUR").Add(New(50, "SEK"))
+		require.Equal(t, ErrorCurrencyMismatch, err)
+	})
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(New(100050, "EUR"))
+	require.NoError(t, err)
+	require.Equal(t, `{"amount":"1000.50","currency":"EUR"}`, string(data))
+
+	data, err = json.Marshal(New(-7, "JPY"))
+	require.NoError(t, err)
+	require.Equal(t, `{"amount":"-7","currency":"JPY"}`, string(data))
+
+	var actual Money
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"12.3","currency":"SEK"}`), &actual))
+	require.Equal(t, New(1230, "SEK"), actual)
+
+	require.Equal(t, ErrorInvalidAmount, json.Unmarshal([]byte(`{"amount":"1e3","currency":"SEK"}`), &actual))
+}
+
+func TestMoney_Convert(t *testing.T) {
+	testCases := []struct {
+		name     string
+		money    Money
+		currency string
+		rate     float64
+		expected Money
+	}{
+		{
+			name:     "same digits",
+			money:    New(500000, "EUR"),
+			currency: "SEK",
+			rate:     10.4567,
+			expected: New(5228350, "SEK"),
+		},
+		{
+			name:     "to currency without minor units",
+			money:    New(1999, "EUR"),
+			currency: "JPY",
+			rate:     130.25,
+			expected: New(2604, "JPY"),
+		},
+		{
+			name:     "from currency without minor units",
+			money:    New(2604, "JPY"),
+			currency: "EUR",
+			rate:     0.0076775,
+			expected: New(1999, "EUR"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual := tC.money.Convert(tC.currency, tC.rate)
+			require.Equal(t, tC.expected, actual)
+		})
+	}
+}
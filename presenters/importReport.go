@@ -0,0 +1,29 @@
+package presenters
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/Taras-Rm/shipment/models"
+)
+
+// header of the import error report
+var importReportHeader = []string{"row", "column", "error"}
+
+// write errors of the CSV import as a CSV report (one line per invalid column)
+func WriteImportReport(w io.Writer, importErrors []models.ImportError) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(importReportHeader); err != nil {
+		return err
+	}
+
+	for _, importError := range importErrors {
+		if err := writer.Write([]string{strconv.Itoa(importError.Row), importError.Column, importError.Error}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,24 @@
+package presenters
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteImportReport(t *testing.T) {
+	var report bytes.Buffer
+	err := WriteImportReport(&report, []models.ImportError{
+		{Row: 2, Column: "fromCountryCode", Error: "invalid country code"},
+		{Row: 3, Error: `extraneous or missing " in quoted-field`},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "row,column,error\n2,fromCountryCode,invalid country code\n3,,\"extraneous or missing \"\" in quoted-field\"\n", report.String())
+
+	// only the header without errors
+	report.Reset()
+	require.NoError(t, WriteImportReport(&report, []models.ImportError{}))
+	require.Equal(t, "row,column,error\n", report.String())
+}
@@ -0,0 +1,114 @@
+package presenters
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Taras-Rm/shipment/models"
+)
+
+var ErrorUnknownExportFormat error = errors.New("unknown export format")
+
+// writer of the exported shipments (the output is complete after Flush)
+type ShipmentWriter interface {
+	Write(shipment models.Shipment) error
+	Flush() error
+}
+
+// writer of the export format ("csv" or "ndjson") and its content type
+func NewShipmentWriter(format string, w io.Writer) (ShipmentWriter, string, error) {
+	switch format {
+	case "csv":
+		return &shipmentCSVWriter{writer: csv.NewWriter(w)}, "text/csv", nil
+	case "ndjson":
+		return &shipmentNDJSONWriter{encoder: json.NewEncoder(w)}, "application/x-ndjson", nil
+	}
+
+	return nil, "", ErrorUnknownExportFormat
+}
+
+// columns of the exported CSV (amounts are in the currency of the price)
+var shipmentCSVHeader = []string{
+	"id", "trackingNumber", "status",
+	"fromName", "fromEmail", "fromAddress", "fromCountryCode",
+	"toName", "toEmail", "toAddress", "toCountryCode",
+	"weight", "length", "width", "height", "pieces",
+	"price", "currency", "cancellationFee", "refund", "returnOfId",
+}
+
+type shipmentCSVWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *shipmentCSVWriter) Write(shipment models.Shipment) error {
+	if !w.headerWritten {
+		if err := w.writer.Write(shipmentCSVHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	var cancellationFee, refund, returnOfID string
+	if shipment.Cancellation != nil {
+		cancellationFee = shipment.Cancellation.Fee.Decimal()
+		refund = shipment.Cancellation.Refund.Decimal()
+	}
+	if shipment.ReturnOf != nil {
+		returnOfID = strconv.FormatUint(uint64(shipment.ReturnOf.Id), 10)
+	}
+
+	return w.writer.Write([]string{
+		strconv.FormatUint(uint64(shipment.Id), 10), shipment.TrackingNumber, string(shipment.Status),
+		csvText(shipment.FromName), csvText(shipment.FromEmail), csvText(shipment.FromAddress), shipment.FromCountryCode,
+		csvText(shipment.ToName), csvText(shipment.ToEmail), csvText(shipment.ToAddress), shipment.ToCountryCode,
+		formatFloat(shipment.Weight), formatFloat(shipment.Length), formatFloat(shipment.Width), formatFloat(shipment.Height), strconv.Itoa(len(shipment.Pieces)),
+		shipment.Price.Decimal(), shipment.Price.Currency, cancellationFee, refund, returnOfID,
+	})
+}
+
+// the header is written even if there are no shipments
+func (w *shipmentCSVWriter) Flush() error {
+	if !w.headerWritten {
+		if err := w.writer.Write(shipmentCSVHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// a JSON shipment per line
+type shipmentNDJSONWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *shipmentNDJSONWriter) Write(shipment models.Shipment) error {
+	return w.encoder.Encode(shipment)
+}
+
+func (w *shipmentNDJSONWriter) Flush() error {
+	return nil
+}
+
+// text given by the client is not run as a formula by spreadsheets
+func csvText(value string) string {
+	if value != "" && strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return "'" + value
+	}
+	return value
+}
+
+// float without trailing zeros (empty for zero)
+func formatFloat(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
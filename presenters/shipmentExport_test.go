@@ -0,0 +1,126 @@
+package presenters
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShipmentWriter(t *testing.T) {
+	shipments := []models.Shipment{
+		{
+			Id:              2,
+			TrackingNumber:  "SH169090604UA",
+			FromName:        "Mark",
+			FromEmail:       "testFrom@g.c",
+			FromAddress:     "Lviv, 45",
+			FromCountryCode: "UA",
+			ToName:          "Iryna",
+			ToEmail:         "testTo@g.c",
+			ToAddress:       "Toronto, 34",
+			ToCountryCode:   "CA",
+			Weight:          234.4,
+			Price:           money.New(500000, "EUR"),
+			Status:          models.StatusCancelled,
+			Cancellation: &models.Cancellation{
+				CancelledAt: time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC),
+				Fee:         money.New(100000, "EUR"),
+				Refund:      money.New(400000, "EUR"),
+			},
+		},
+		{
+			Id:              3,
+			FromName:        "Iryna",
+			FromEmail:       "testTo@g.c",
+			FromAddress:     "Toronto, 34",
+			FromCountryCode: "CA",
+			ToName:          "Mark",
+			ToEmail:         "testFrom@g.c",
+			ToAddress:       "Lviv, 45",
+			ToCountryCode:   "UA",
+			Weight:          10,
+			Length:          20,
+			Width:           30.5,
+			Height:          40,
+			Price:           money.New(25000, "EUR"),
+			Status:          models.StatusCreated,
+			ReturnOf:        &models.ShipmentLink{Id: 1},
+		},
+	}
+
+	testCases := []struct {
+		format              string
+		shipments           []models.Shipment
+		expectedContentType string
+		expectedOutput      string
+	}{
+		{
+			format:              "csv",
+			shipments:           shipments,
+			expectedContentType: "text/csv",
+			expectedOutput: "id,trackingNumber,status,fromName,fromEmail,fromAddress,fromCountryCode,toName,toEmail,toAddress,toCountryCode,weight,length,width,height,pieces,price,currency,cancellationFee,refund,returnOfId\n" +
+				"2,SH169090604UA,cancelled,Mark,testFrom@g.c,\"Lviv, 45\",UA,Iryna,testTo@g.c,\"Toronto, 34\",CA,234.4,,,,0,5000.00,EUR,1000.00,4000.00,\n" +
+				"3,,created,Iryna,testTo@g.c,\"Toronto, 34\",CA,Mark,testFrom@g.c,\"Lviv, 45\",UA,10,20,30.5,40,0,250.00,EUR,,,1\n",
+		},
+		{
+			format: "csv",
+			shipments: []models.Shipment{{
+				Id:              4,
+				FromName:        `=HYPERLINK("http://evil.example","Mark")`,
+				FromEmail:       "@mark",
+				FromAddress:     "-1+1",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "+38 Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          1,
+				Price:           money.New(10000, "EUR"),
+				Status:          models.StatusCreated,
+			}},
+			expectedContentType: "text/csv",
+			expectedOutput: "id,trackingNumber,status,fromName,fromEmail,fromAddress,fromCountryCode,toName,toEmail,toAddress,toCountryCode,weight,length,width,height,pieces,price,currency,cancellationFee,refund,returnOfId\n" +
+				"4,,created,\"'=HYPERLINK(\"\"http://evil.example\"\",\"\"Mark\"\")\",'@mark,'-1+1,UA,Iryna,testTo@g.c,\"'+38 Toronto, 34\",CA,1,,,,0,100.00,EUR,,,\n",
+		},
+		{
+			format:              "csv",
+			expectedContentType: "text/csv",
+			expectedOutput:      "id,trackingNumber,status,fromName,fromEmail,fromAddress,fromCountryCode,toName,toEmail,toAddress,toCountryCode,weight,length,width,height,pieces,price,currency,cancellationFee,refund,returnOfId\n",
+		},
+		{
+			format:              "ndjson",
+			shipments:           shipments[1:],
+			expectedContentType: "application/x-ndjson",
+			expectedOutput:      `{"Id":3,"FromName":"Iryna","FromEmail":"testTo@g.c","FromAddress":"Toronto, 34","FromCountryCode":"CA","ToName":"Mark","ToEmail":"testFrom@g.c","ToAddress":"Lviv, 45","ToCountryCode":"UA","Weight":10,"Length":20,"Width":30.5,"Height":40,"Price":{"amount":"250.00","currency":"EUR"},"Status":"created","ReturnOf":{"id":1}}` + "\n",
+		},
+		{
+			format:              "ndjson",
+			expectedContentType: "application/x-ndjson",
+			expectedOutput:      "",
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.format, func(t *testing.T) {
+			var output bytes.Buffer
+			writer, contentType, err := NewShipmentWriter(tC.format, &output)
+			require.NoError(t, err)
+			require.Equal(t, tC.expectedContentType, contentType)
+
+			for _, shipment := range tC.shipments {
+				require.NoError(t, writer.Write(shipment))
+			}
+			require.NoError(t, writer.Flush())
+			require.Equal(t, tC.expectedOutput, output.String())
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, _, err := NewShipmentWriter("xlsx", &bytes.Buffer{})
+		require.Equal(t, ErrorUnknownExportFormat, err)
+	})
+}
@@ -0,0 +1,91 @@
+package presenters
+
+import (
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+)
+
+// shipment as it is shown to anyone who knows the tracking number
+// (no names, emails, addresses or prices)
+type PublicTracking struct {
+	TrackingNumber string                `json:"trackingNumber"`
+	Status         models.ShipmentStatus `json:"status"`
+	Origin         PublicLocation        `json:"origin"`
+	Destination    PublicLocation        `json:"destination"`
+	Events         []PublicEvent         `json:"events"`
+	// brand of the tenant which sent the shipment (not shown without tenants)
+	Brand *PublicBrand `json:"brand,omitempty"`
+}
+
+// brand of the tenant for the tracking page of the recipient
+type PublicBrand struct {
+	Name         string `json:"name"`
+	LogoURL      string `json:"logoUrl,omitempty"`
+	Color        string `json:"color,omitempty"`
+	SupportEmail string `json:"supportEmail,omitempty"`
+}
+
+// location is shown at city/country level only
+type PublicLocation struct {
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+// scan event without facility and free-text note (they may contain personal data)
+type PublicEvent struct {
+	OccurredAt time.Time      `json:"occurredAt"`
+	Code       string         `json:"code"`
+	Location   PublicLocation `json:"location"`
+}
+
+func NewPublicTracking(shipment models.Shipment, events []models.TrackingEvent, tenant models.Tenant) PublicTracking {
+	tracking := PublicTracking{
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         shipment.Status,
+		// addresses are free text, so only the countries are shown
+		Origin:      PublicLocation{CountryCode: shipment.FromCountryCode},
+		Destination: PublicLocation{CountryCode: shipment.ToCountryCode},
+		Events:      make([]PublicEvent, 0, len(events)),
+		Brand:       NewPublicBrand(tenant),
+	}
+
+	for _, event := range events {
+		tracking.Events = append(tracking.Events, NewPublicEvent(event))
+	}
+
+	return tracking
+}
+
+func NewPublicBrand(tenant models.Tenant) *PublicBrand {
+	if tenant.Id == "" {
+		return nil
+	}
+
+	name := tenant.Branding.DisplayName
+	if name == "" {
+		name = tenant.Name
+	}
+
+	return &PublicBrand{
+		Name:         name,
+		LogoURL:      tenant.Branding.LogoURL,
+		Color:        tenant.Branding.Color,
+		SupportEmail: tenant.Branding.SupportEmail,
+	}
+}
+
+func NewPublicEvent(event models.TrackingEvent) PublicEvent {
+	return PublicEvent{
+		OccurredAt: event.OccurredAt,
+		Code:       event.Code,
+		Location:   NewPublicLocation(event.Location),
+	}
+}
+
+func NewPublicLocation(location models.EventLocation) PublicLocation {
+	return PublicLocation{
+		City:        location.City,
+		CountryCode: location.CountryCode,
+	}
+}
@@ -0,0 +1,84 @@
+package presenters
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPublicTracking(t *testing.T) {
+	shipment := models.Shipment{
+		Id:              2,
+		TrackingNumber:  "SH169090604UA",
+		FromName:        "Mark",
+		FromEmail:       "testFrom@g.c",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "CA",
+		Weight:          234.4,
+		Price:           money.New(500000, "EUR"),
+		Status:          models.StatusInTransit,
+	}
+
+	testCases := []struct {
+		name         string
+		events       []models.TrackingEvent
+		tenant       models.Tenant
+		expectedJSON string
+	}{
+		{
+			name: "with events",
+			events: []models.TrackingEvent{
+				{
+					Id:         1,
+					OccurredAt: time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC),
+					Code:       "PICKED_UP",
+					Location:   models.EventLocation{Facility: "Lviv, Shevchenka 12", City: "Lviv", CountryCode: "UA"},
+					Note:       "handed over by Mark",
+				},
+				{
+					Id:         2,
+					OccurredAt: time.Date(2022, 1, 11, 9, 0, 0, 0, time.UTC),
+					Code:       "ARRIVED_AT_HUB",
+					Location:   models.EventLocation{Facility: "Hub 3", City: "Warsaw", CountryCode: "PL"},
+				},
+				{
+					Id:         3,
+					OccurredAt: time.Date(2022, 1, 12, 9, 0, 0, 0, time.UTC),
+					Code:       "CUSTOMS_HOLD",
+				},
+			},
+			expectedJSON: `{"trackingNumber":"SH169090604UA","status":"in_transit","origin":{"countryCode":"UA"},"destination":{"countryCode":"CA"},"events":[{"occurredAt":"2022-01-10T08:30:00Z","code":"PICKED_UP","location":{"city":"Lviv","countryCode":"UA"}},{"occurredAt":"2022-01-11T09:00:00Z","code":"ARRIVED_AT_HUB","location":{"city":"Warsaw","countryCode":"PL"}},{"occurredAt":"2022-01-12T09:00:00Z","code":"CUSTOMS_HOLD","location":{}}]}`,
+		},
+		{
+			name:         "without events",
+			events:       nil,
+			expectedJSON: `{"trackingNumber":"SH169090604UA","status":"in_transit","origin":{"countryCode":"UA"},"destination":{"countryCode":"CA"},"events":[]}`,
+		},
+		{
+			name:         "brand of the tenant",
+			tenant:       models.Tenant{Id: "brand-a", Name: "Brand A", Branding: models.Branding{DisplayName: "Brand A Parcels", LogoURL: "https://cdn.example.com/brand-a.png", Color: "#1a73e8", SupportEmail: "help@brand-a.com"}},
+			expectedJSON: `{"trackingNumber":"SH169090604UA","status":"in_transit","origin":{"countryCode":"UA"},"destination":{"countryCode":"CA"},"events":[],"brand":{"name":"Brand A Parcels","logoUrl":"https://cdn.example.com/brand-a.png","color":"#1a73e8","supportEmail":"help@brand-a.com"}}`,
+		},
+		{
+			name:         "tenant without branding",
+			tenant:       models.Tenant{Id: "brand-b", Name: "Brand B"},
+			expectedJSON: `{"trackingNumber":"SH169090604UA","status":"in_transit","origin":{"countryCode":"UA"},"destination":{"countryCode":"CA"},"events":[],"brand":{"name":"Brand B"}}`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual, err := json.Marshal(NewPublicTracking(shipment, tC.events, tC.tenant))
+			require.NoError(t, err)
+			require.Equal(t, tC.expectedJSON, string(actual))
+		})
+	}
+}
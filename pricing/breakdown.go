@@ -0,0 +1,40 @@
+package pricing
+
+import (
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+)
+
+// sum up breakdowns of several parcels of the same shipment
+// (components with the same type and name are merged, order is kept)
+func Aggregate(breakdowns []models.PriceBreakdown) models.PriceBreakdown {
+	if len(breakdowns) == 0 {
+		return models.PriceBreakdown{}
+	}
+
+	aggregated := models.PriceBreakdown{
+		RateCardVersion: breakdowns[0].RateCardVersion,
+		Lane:            breakdowns[0].Lane,
+		Total:           money.New(0, breakdowns[0].Total.Currency),
+		FxRate:          breakdowns[0].FxRate,
+	}
+
+	positions := map[string]int{}
+	for _, breakdown := range breakdowns {
+		aggregated.VolumetricWeight += breakdown.VolumetricWeight
+		aggregated.BillableWeight += breakdown.BillableWeight
+		aggregated.Total.Amount += breakdown.Total.Amount
+
+		for _, component := range breakdown.Components {
+			key := string(component.Type) + "/" + component.Name
+			if i, ok := positions[key]; ok {
+				aggregated.Components[i].Amount.Amount += component.Amount.Amount
+				continue
+			}
+			positions[key] = len(aggregated.Components)
+			aggregated.Components = append(aggregated.Components, component)
+		}
+	}
+
+	return aggregated
+}
@@ -0,0 +1,209 @@
+package pricing
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/biter777/countries"
+)
+
+var ErrorWeightNotSupported error = errors.New("weight is not supported by rate card")
+
+type Engine interface {
+	Price(fromCountryCode, toCountryCode string, parcel models.Parcel) (money.Money, error)
+	Quote(fromCountryCode, toCountryCode string, parcel models.Parcel) (models.PriceBreakdown, error)
+	QuoteReturn(fromCountryCode, toCountryCode string, parcel models.Parcel) (models.PriceBreakdown, error)
+	BillableWeight(parcel models.Parcel) (billable float64, volumetric float64)
+	RegionFactor(countryCode string) float64
+	Lane(fromCountryCode, toCountryCode string) models.Lane
+	WeightAmount(weight float64) (money.Money, error)
+	RateCard() models.RateCard
+}
+
+type engine struct {
+	card models.RateCard
+}
+
+func InitEngine(card models.RateCard) (Engine, error) {
+	if err := ValidateRateCard(card); err != nil {
+		return nil, err
+	}
+	return &engine{card: card}, nil
+}
+
+// calculate price of the shipment
+func (e *engine) Price(fromCountryCode, toCountryCode string, parcel models.Parcel) (money.Money, error) {
+	breakdown, err := e.Quote(fromCountryCode, toCountryCode, parcel)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return breakdown.Total, nil
+}
+
+// calculate itemized price of the shipment
+func (e *engine) Quote(fromCountryCode, toCountryCode string, parcel models.Parcel) (models.PriceBreakdown, error) {
+	return e.quote(fromCountryCode, toCountryCode, parcel, false)
+}
+
+// calculate itemized price of the return shipment (by the returns rate)
+func (e *engine) QuoteReturn(fromCountryCode, toCountryCode string, parcel models.Parcel) (models.PriceBreakdown, error) {
+	return e.quote(fromCountryCode, toCountryCode, parcel, true)
+}
+
+func (e *engine) quote(fromCountryCode, toCountryCode string, parcel models.Parcel, isReturn bool) (models.PriceBreakdown, error) {
+	// large and light parcels are billed by volumetric weight
+	billableWeight, volumetricWeight := e.BillableWeight(parcel)
+
+	amount, err := e.WeightAmount(billableWeight)
+	if err != nil {
+		return models.PriceBreakdown{}, err
+	}
+
+	lane := e.Lane(fromCountryCode, toCountryCode)
+
+	// every component adds the difference it makes to the running total,
+	// so the rounded components always sum up to the total
+	components := []models.PriceComponent{
+		{Type: models.PriceComponentBase, Name: "weight class", Amount: amount},
+	}
+	total := amount
+
+	applyFactor := func(componentType models.PriceComponentType, name string, factor float64) {
+		next := total.Mul(factor)
+		components = append(components, models.PriceComponent{
+			Type:   componentType,
+			Name:   name,
+			Factor: factor,
+			Amount: money.New(next.Amount-total.Amount, total.Currency),
+		})
+		total = next
+	}
+
+	applyFactor(models.PriceComponentLane, string(lane.Type), lane.Factor)
+
+	for _, multiplier := range e.card.Multipliers {
+		componentType := models.PriceComponentSurcharge
+		if multiplier.Factor < 1 {
+			componentType = models.PriceComponentDiscount
+		}
+		applyFactor(componentType, multiplier.Name, multiplier.Factor)
+	}
+
+	if isReturn && e.card.ReturnsFactor > 0 {
+		applyFactor(models.PriceComponentReturns, "returns", e.card.ReturnsFactor)
+	}
+
+	return models.PriceBreakdown{
+		RateCardVersion:  e.card.Version,
+		Lane:             lane,
+		VolumetricWeight: volumetricWeight,
+		BillableWeight:   billableWeight,
+		Components:       components,
+		Total:            total,
+	}, nil
+}
+
+// determining the factor of country region
+func (e *engine) RegionFactor(countryCode string) float64 {
+	// country overrides have the highest priority
+	if factor, ok := e.card.CountryOverrides[countryCode]; ok {
+		return factor
+	}
+
+	_, factor := e.zone(countryCode)
+	return factor
+}
+
+// determining the lane between origin and destination countries
+func (e *engine) Lane(fromCountryCode, toCountryCode string) models.Lane {
+	origin, _ := e.zone(fromCountryCode)
+	destination, _ := e.zone(toCountryCode)
+
+	lane := models.Lane{Origin: origin, Destination: destination}
+
+	// determine lane type
+	switch {
+	case fromCountryCode == toCountryCode:
+		lane.Type = models.LaneDomestic
+	case continent(fromCountryCode) != continent(toCountryCode):
+		lane.Type = models.LaneIntercontinental
+	case origin == destination:
+		lane.Type = models.LaneIntraRegion
+	default:
+		lane.Type = models.LaneInterRegion
+	}
+
+	// determine lane factor
+	if factor, ok := e.card.ZoneMatrix[origin][destination]; ok {
+		lane.Factor = factor
+	} else {
+		// rate cards without zone matrix are priced by origin only
+		lane.Factor = e.RegionFactor(fromCountryCode)
+	}
+	if factor, ok := e.card.CountryOverrides[fromCountryCode]; ok {
+		lane.Factor = factor
+	}
+	if lane.Type == models.LaneDomestic && e.card.DomesticFactor > 0 {
+		lane.Factor = e.card.DomesticFactor
+	}
+
+	return lane
+}
+
+// determining the zone (region) of the country
+func (e *engine) zone(countryCode string) (string, float64) {
+	countryContinent := continent(countryCode)
+
+	// regions listed earlier win
+	for _, region := range e.card.Regions {
+		for _, code := range region.Countries {
+			if code == countryCode {
+				return region.Name, region.Factor
+			}
+		}
+		if region.Continent != "" && region.Continent == countryContinent {
+			return region.Name, region.Factor
+		}
+	}
+
+	return e.card.DefaultRegion, e.card.DefaultFactor
+}
+
+// determining the weight to bill: max(actual, volumetric)
+func (e *engine) BillableWeight(parcel models.Parcel) (float64, float64) {
+	if e.card.VolumetricDivisor <= 0 {
+		return parcel.Weight, 0
+	}
+
+	volumetric := parcel.Length * parcel.Width * parcel.Height / e.card.VolumetricDivisor
+
+	return math.Max(parcel.Weight, volumetric), volumetric
+}
+
+// determining the amount of weight class
+func (e *engine) WeightAmount(weight float64) (money.Money, error) {
+	if weight <= 0 || weight > e.card.MaxWeight {
+		return money.Money{}, ErrorWeightNotSupported
+	}
+
+	var amount float64
+	for _, bracket := range e.card.WeightBrackets {
+		if weight < bracket.From {
+			break
+		}
+		amount = bracket.Amount
+	}
+
+	return money.FromMajor(amount, e.card.Currency), nil
+}
+
+func (e *engine) RateCard() models.RateCard {
+	return e.card
+}
+
+// get country continent
+func continent(countryCode string) string {
+	return countries.ByName(countryCode).Region().String()
+}
@@ -0,0 +1,364 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_RegionFactor(t *testing.T) {
+	card := DefaultRateCard()
+	card.CountryOverrides = map[string]float64{"PL": 1.2}
+
+	engine, err := InitEngine(card)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		code     string
+		expected float64
+	}{
+		{
+			name:     "nordic region country",
+			code:     "SE",
+			expected: 1,
+		},
+		{
+			name:     "europe country",
+			code:     "DE",
+			expected: 1.5,
+		},
+		{
+			name:     "country override",
+			code:     "PL",
+			expected: 1.2,
+		},
+		{
+			name:     "outside of europe country",
+			code:     "AE",
+			expected: 2.5,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual := engine.RegionFactor(tC.code)
+			require.Equal(t, tC.expected, actual)
+		})
+	}
+}
+
+func TestEngine_Lane(t *testing.T) {
+	card := DefaultRateCard()
+	card.CountryOverrides = map[string]float64{"PL": 1.2}
+
+	engine, err := InitEngine(card)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		expected models.Lane
+	}{
+		{
+			name:     "domestic",
+			from:     "PL",
+			to:       "PL",
+			expected: models.Lane{Type: models.LaneDomestic, Origin: "europe", Destination: "europe", Factor: 1},
+		},
+		{
+			name:     "intra region (nordic)",
+			from:     "SE",
+			to:       "NO",
+			expected: models.Lane{Type: models.LaneIntraRegion, Origin: "nordic", Destination: "nordic", Factor: 1},
+		},
+		{
+			name:     "inter region (nordic to europe)",
+			from:     "SE",
+			to:       "DE",
+			expected: models.Lane{Type: models.LaneInterRegion, Origin: "nordic", Destination: "europe", Factor: 1.5},
+		},
+		{
+			name:     "intercontinental",
+			from:     "SE",
+			to:       "US",
+			expected: models.Lane{Type: models.LaneIntercontinental, Origin: "nordic", Destination: "world", Factor: 2.5},
+		},
+		{
+			name:     "intercontinental inside default region",
+			from:     "US",
+			to:       "AE",
+			expected: models.Lane{Type: models.LaneIntercontinental, Origin: "world", Destination: "world", Factor: 2.5},
+		},
+		{
+			name:     "country override of origin",
+			from:     "PL",
+			to:       "US",
+			expected: models.Lane{Type: models.LaneIntercontinental, Origin: "europe", Destination: "world", Factor: 1.2},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual := engine.Lane(tC.from, tC.to)
+			require.Equal(t, tC.expected, actual)
+		})
+	}
+}
+
+func TestEngine_Lane_WithoutZoneMatrix(t *testing.T) {
+	card := DefaultRateCard()
+	card.ZoneMatrix = nil
+	card.DomesticFactor = 0
+
+	engine, err := InitEngine(card)
+	require.NoError(t, err)
+
+	// priced by origin region only
+	require.Equal(t, 1.0, engine.Lane("SE", "US").Factor)
+	require.Equal(t, 1.5, engine.Lane("PL", "PL").Factor)
+}
+
+func TestEngine_WeightAmount(t *testing.T) {
+	engine, err := InitEngine(DefaultRateCard())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		weight float64
+		amount money.Money
+		err    error
+	}{
+		{
+			name:   "small weight class",
+			weight: 10.9,
+			amount: money.New(10000, "EUR"),
+		},
+		{
+			name:   "medium weight class (left border)",
+			weight: 11,
+			amount: money.New(30000, "EUR"),
+		},
+		{
+			name:   "large weight class",
+			weight: 35,
+			amount: money.New(50000, "EUR"),
+		},
+		{
+			name:   "huge weight class (right border)",
+			weight: 1000,
+			amount: money.New(200000, "EUR"),
+		},
+		{
+			name:   "zero weight",
+			weight: 0,
+			err:    ErrorWeightNotSupported,
+		},
+		{
+			name:   "too big weight",
+			weight: 1000.1,
+			err:    ErrorWeightNotSupported,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual, err := engine.WeightAmount(tC.weight)
+			require.Equal(t, tC.err, err)
+			require.Equal(t, tC.amount, actual)
+		})
+	}
+}
+
+func TestEngine_BillableWeight(t *testing.T) {
+	engine, err := InitEngine(DefaultRateCard())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name               string
+		parcel             models.Parcel
+		expectedBillable   float64
+		expectedVolumetric float64
+	}{
+		{
+			name:               "without dimensions",
+			parcel:             models.Parcel{Weight: 12},
+			expectedBillable:   12,
+			expectedVolumetric: 0,
+		},
+		{
+			name:               "heavy and small parcel",
+			parcel:             models.Parcel{Weight: 12, Length: 40, Width: 30, Height: 20},
+			expectedBillable:   12,
+			expectedVolumetric: 4.8,
+		},
+		{
+			name:               "large and light parcel",
+			parcel:             models.Parcel{Weight: 2, Length: 100, Width: 50, Height: 40},
+			expectedBillable:   40,
+			expectedVolumetric: 40,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			billable, volumetric := engine.BillableWeight(tC.parcel)
+			require.Equal(t, tC.expectedBillable, billable)
+			require.Equal(t, tC.expectedVolumetric, volumetric)
+		})
+	}
+
+	t.Run("volumetric pricing is disabled", func(t *testing.T) {
+		card := DefaultRateCard()
+		card.VolumetricDivisor = 0
+
+		engine, err := InitEngine(card)
+		require.NoError(t, err)
+
+		billable, volumetric := engine.BillableWeight(models.Parcel{Weight: 2, Length: 100, Width: 50, Height: 40})
+		require.Equal(t, 2.0, billable)
+		require.Equal(t, 0.0, volumetric)
+	})
+}
+
+func TestEngine_Price(t *testing.T) {
+	card := DefaultRateCard()
+	card.Multipliers = []models.Multiplier{{Name: "fuel", Factor: 1.1}}
+
+	engine, err := InitEngine(card)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		price, err := engine.Price("PL", "SE", models.Parcel{Weight: 20})
+		require.NoError(t, err)
+		require.Equal(t, money.New(49500, "EUR"), price)
+	})
+
+	t.Run("destination is taken into account", func(t *testing.T) {
+		price, err := engine.Price("SE", "US", models.Parcel{Weight: 20})
+		require.NoError(t, err)
+		require.Equal(t, money.New(82500, "EUR"), price)
+	})
+
+	t.Run("billed by volumetric weight", func(t *testing.T) {
+		price, err := engine.Price("PL", "SE", models.Parcel{Weight: 2, Length: 100, Width: 50, Height: 40})
+		require.NoError(t, err)
+		require.Equal(t, money.New(82500, "EUR"), price)
+	})
+
+	t.Run("not supported weight", func(t *testing.T) {
+		_, err := engine.Price("PL", "SE", models.Parcel{Weight: 2000})
+		require.Equal(t, ErrorWeightNotSupported, err)
+	})
+}
+
+func TestEngine_Quote(t *testing.T) {
+	card := DefaultRateCard()
+	card.Multipliers = []models.Multiplier{
+		{Name: "fuel", Factor: 1.1},
+		{Name: "loyalty", Factor: 0.333},
+	}
+
+	engine, err := InitEngine(card)
+	require.NoError(t, err)
+
+	breakdown, err := engine.Quote("SE", "US", models.Parcel{Weight: 20})
+	require.NoError(t, err)
+
+	require.Equal(t, "default", breakdown.RateCardVersion)
+	require.Equal(t, models.LaneIntercontinental, breakdown.Lane.Type)
+	require.Equal(t, 20.0, breakdown.BillableWeight)
+	require.Len(t, breakdown.Components, 4)
+
+	expected := []struct {
+		componentType models.PriceComponentType
+		name          string
+		amount        money.Money
+	}{
+		{models.PriceComponentBase, "weight class", money.New(30000, "EUR")},
+		{models.PriceComponentLane, "intercontinental", money.New(45000, "EUR")},
+		{models.PriceComponentSurcharge, "fuel", money.New(7500, "EUR")},
+		{models.PriceComponentDiscount, "loyalty", money.New(-55027, "EUR")},
+	}
+	sum := money.New(0, "EUR")
+	for i, e := range expected {
+		require.Equal(t, e.componentType, breakdown.Components[i].Type)
+		require.Equal(t, e.name, breakdown.Components[i].Name)
+		require.Equal(t, e.amount, breakdown.Components[i].Amount)
+
+		sum, err = sum.Add(breakdown.Components[i].Amount)
+		require.NoError(t, err)
+	}
+
+	// 825 * 0.333 = 274.725 is rounded half away from zero
+	require.Equal(t, money.New(27473, "EUR"), breakdown.Total)
+	require.Equal(t, sum, breakdown.Total)
+}
+
+func TestAggregate(t *testing.T) {
+	engine, err := InitEngine(DefaultRateCard())
+	require.NoError(t, err)
+
+	small, err := engine.Quote("SE", "US", models.Parcel{Weight: 5})
+	require.NoError(t, err)
+	large, err := engine.Quote("SE", "US", models.Parcel{Weight: 2, Length: 100, Width: 50, Height: 40})
+	require.NoError(t, err)
+
+	aggregated := Aggregate([]models.PriceBreakdown{small, large})
+
+	require.Equal(t, models.PriceBreakdown{
+		RateCardVersion:  "default",
+		Lane:             small.Lane,
+		VolumetricWeight: 40,
+		BillableWeight:   45,
+		Components: []models.PriceComponent{
+			{Type: models.PriceComponentBase, Name: "weight class", Amount: money.New(60000, "EUR")},
+			{Type: models.PriceComponentLane, Name: "intercontinental", Factor: 2.5, Amount: money.New(90000, "EUR")},
+		},
+		Total: money.New(150000, "EUR"),
+	}, aggregated)
+
+	// pieces are not changed
+	require.Equal(t, money.New(10000, "EUR"), small.Components[0].Amount)
+}
+
+func TestEngine_QuoteReturn(t *testing.T) {
+	card := DefaultRateCard()
+	card.Multipliers = []models.Multiplier{{Name: "fuel", Factor: 1.1}}
+
+	engine, err := InitEngine(card)
+	require.NoError(t, err)
+
+	t.Run("returns rate is the last component", func(t *testing.T) {
+		breakdown, err := engine.QuoteReturn("SE", "US", models.Parcel{Weight: 20})
+		require.NoError(t, err)
+
+		require.Len(t, breakdown.Components, 4)
+		require.Equal(t, models.PriceComponent{
+			Type:   models.PriceComponentReturns,
+			Name:   "returns",
+			Factor: 0.8,
+			Amount: money.New(-16500, "EUR"),
+		}, breakdown.Components[3])
+		require.Equal(t, money.New(66000, "EUR"), breakdown.Total)
+	})
+
+	t.Run("regular quote has no returns rate", func(t *testing.T) {
+		breakdown, err := engine.Quote("SE", "US", models.Parcel{Weight: 20})
+		require.NoError(t, err)
+		require.Len(t, breakdown.Components, 3)
+	})
+
+	t.Run("returns priced as regular shipments", func(t *testing.T) {
+		card.ReturnsFactor = 0
+		engine, err := InitEngine(card)
+		require.NoError(t, err)
+
+		breakdown, err := engine.QuoteReturn("SE", "US", models.Parcel{Weight: 20})
+		require.NoError(t, err)
+		require.Equal(t, money.New(82500, "EUR"), breakdown.Total)
+	})
+}
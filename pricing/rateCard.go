@@ -0,0 +1,174 @@
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	ErrorUnsupportedRateCardFormat error = errors.New("unsupported rate card format")
+	ErrorNoRateCardVersion         error = errors.New("rate card has no version")
+	ErrorInvalidFactor             error = errors.New("rate card factor must be positive")
+	ErrorNoWeightBrackets          error = errors.New("rate card has no weight brackets")
+	ErrorInvalidWeightBracket      error = errors.New("invalid weight bracket")
+	ErrorInvalidMaxWeight          error = errors.New("invalid max weight")
+	ErrorInvalidVolumetricDivisor  error = errors.New("invalid volumetric divisor")
+	ErrorNoDefaultRegion           error = errors.New("rate card has no default region")
+	ErrorUnknownZone               error = errors.New("unknown zone")
+)
+
+// rate card with the same rules which were hard-coded in helpers
+func DefaultRateCard() models.RateCard {
+	return models.RateCard{
+		Version:  "default",
+		Currency: "EUR",
+		Regions: []models.Region{
+			{Name: "nordic", Factor: 1, Countries: []string{"SE", "NO", "DK", "FI"}},
+			{Name: "europe", Factor: 1.5, Continent: "Europe"},
+		},
+		DefaultRegion:  "world",
+		DefaultFactor:  2.5,
+		DomesticFactor: 1,
+		ZoneMatrix: models.ZoneMatrix{
+			"nordic": {"nordic": 1, "europe": 1.5, "world": 2.5},
+			"europe": {"nordic": 1.5, "europe": 1.5, "world": 2.5},
+			"world":  {"nordic": 2.5, "europe": 2.5, "world": 2.5},
+		},
+		WeightBrackets: []models.WeightBracket{
+			{From: 0, Amount: 100},
+			{From: 11, Amount: 300},
+			{From: 26, Amount: 500},
+			{From: 51, Amount: 2000},
+		},
+		MaxWeight:         1000,
+		VolumetricDivisor: 5000,
+		// returns are cheaper than regular shipments
+		ReturnsFactor: 0.8,
+	}
+}
+
+// load rate card from YAML or JSON file
+func LoadRateCardFile(path string) (models.RateCard, error) {
+	var card models.RateCard
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return card, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &card)
+	case ".json":
+		err = json.Unmarshal(data, &card)
+	default:
+		return card, ErrorUnsupportedRateCardFormat
+	}
+	if err != nil {
+		return card, err
+	}
+
+	return card, nil
+}
+
+// check that rate card can be used for pricing
+func ValidateRateCard(card models.RateCard) error {
+	if card.Version == "" {
+		return ErrorNoRateCardVersion
+	}
+	if err := helpers.ValidateCurrencyCode(card.Currency); err != nil {
+		return err
+	}
+
+	// check region factors
+	for _, region := range card.Regions {
+		if region.Factor <= 0 {
+			return fmt.Errorf("region %q: %w", region.Name, ErrorInvalidFactor)
+		}
+		for _, code := range region.Countries {
+			if err := helpers.ValidateCountryCode(code); err != nil {
+				return fmt.Errorf("region %q: %w", region.Name, err)
+			}
+		}
+	}
+	if card.DefaultRegion == "" {
+		return ErrorNoDefaultRegion
+	}
+	if card.DefaultFactor <= 0 {
+		return fmt.Errorf("default factor: %w", ErrorInvalidFactor)
+	}
+	if card.DomesticFactor < 0 {
+		return fmt.Errorf("domestic factor: %w", ErrorInvalidFactor)
+	}
+
+	// check zone matrix
+	zones := map[string]bool{card.DefaultRegion: true}
+	for _, region := range card.Regions {
+		zones[region.Name] = true
+	}
+	for origin, destinations := range card.ZoneMatrix {
+		if !zones[origin] {
+			return fmt.Errorf("zone matrix %q: %w", origin, ErrorUnknownZone)
+		}
+		for destination, factor := range destinations {
+			if !zones[destination] {
+				return fmt.Errorf("zone matrix %q -> %q: %w", origin, destination, ErrorUnknownZone)
+			}
+			if factor <= 0 {
+				return fmt.Errorf("zone matrix %q -> %q: %w", origin, destination, ErrorInvalidFactor)
+			}
+		}
+	}
+
+	// check country overrides
+	for code, factor := range card.CountryOverrides {
+		if err := helpers.ValidateCountryCode(code); err != nil {
+			return fmt.Errorf("country override %q: %w", code, err)
+		}
+		if factor <= 0 {
+			return fmt.Errorf("country override %q: %w", code, ErrorInvalidFactor)
+		}
+	}
+
+	// check weight brackets
+	if len(card.WeightBrackets) == 0 {
+		return ErrorNoWeightBrackets
+	}
+	if !sort.SliceIsSorted(card.WeightBrackets, func(i, j int) bool {
+		return card.WeightBrackets[i].From < card.WeightBrackets[j].From
+	}) || card.WeightBrackets[0].From != 0 {
+		return ErrorInvalidWeightBracket
+	}
+	for i, bracket := range card.WeightBrackets {
+		if bracket.Amount <= 0 || (i > 0 && bracket.From == card.WeightBrackets[i-1].From) {
+			return ErrorInvalidWeightBracket
+		}
+	}
+	if card.MaxWeight <= card.WeightBrackets[len(card.WeightBrackets)-1].From {
+		return ErrorInvalidMaxWeight
+	}
+	if card.VolumetricDivisor < 0 {
+		return ErrorInvalidVolumetricDivisor
+	}
+
+	// check multipliers
+	for _, multiplier := range card.Multipliers {
+		if multiplier.Factor <= 0 {
+			return fmt.Errorf("multiplier %q: %w", multiplier.Name, ErrorInvalidFactor)
+		}
+	}
+	if card.ReturnsFactor < 0 {
+		return fmt.Errorf("returns factor: %w", ErrorInvalidFactor)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRateCardFile(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		card, err := LoadRateCardFile("../ratecards/default.yaml")
+		require.NoError(t, err)
+		require.NoError(t, ValidateRateCard(card))
+
+		require.Equal(t, "2022-01", card.Version)
+		require.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), card.EffectiveFrom.UTC())
+		require.Equal(t, DefaultRateCard().Regions, card.Regions)
+		require.Equal(t, DefaultRateCard().WeightBrackets, card.WeightBrackets)
+		require.Equal(t, DefaultRateCard().ZoneMatrix, card.ZoneMatrix)
+		require.Equal(t, DefaultRateCard().VolumetricDivisor, card.VolumetricDivisor)
+		require.Equal(t, DefaultRateCard().ReturnsFactor, card.ReturnsFactor)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		card, err := LoadRateCardFile("./fixtures/ratecard.json")
+		require.NoError(t, err)
+		require.NoError(t, ValidateRateCard(card))
+
+		require.Equal(t, "test-json", card.Version)
+		require.Equal(t, map[string]float64{"US": 2}, card.CountryOverrides)
+		require.Equal(t, []models.Multiplier{{Name: "fuel", Factor: 1.1}}, card.Multipliers)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := LoadRateCardFile("./fixtures/ratecard.txt")
+		require.Equal(t, ErrorUnsupportedRateCardFormat, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadRateCardFile("./fixtures/missing.yaml")
+		require.Error(t, err)
+	})
+}
+
+func TestValidateRateCard(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(card *models.RateCard)
+		err    error
+	}{
+		{
+			name:   "valid default rate card",
+			modify: func(card *models.RateCard) {},
+			err:    nil,
+		},
+		{
+			name:   "no version",
+			modify: func(card *models.RateCard) { card.Version = "" },
+			err:    ErrorNoRateCardVersion,
+		},
+		{
+			name:   "negative region factor",
+			modify: func(card *models.RateCard) { card.Regions[0].Factor = -1 },
+			err:    ErrorInvalidFactor,
+		},
+		{
+			name:   "invalid region country",
+			modify: func(card *models.RateCard) { card.Regions[0].Countries = []string{"UU"} },
+			err:    helpers.ErrorNotExistingCountryCode,
+		},
+		{
+			name:   "invalid country override",
+			modify: func(card *models.RateCard) { card.CountryOverrides = map[string]float64{"pl": 1} },
+			err:    helpers.ErrorInvalidCountryCode,
+		},
+		{
+			name:   "no default region",
+			modify: func(card *models.RateCard) { card.DefaultRegion = "" },
+			err:    ErrorNoDefaultRegion,
+		},
+		{
+			name:   "unknown origin zone",
+			modify: func(card *models.RateCard) { card.ZoneMatrix["asia"] = map[string]float64{"world": 2} },
+			err:    ErrorUnknownZone,
+		},
+		{
+			name:   "unknown destination zone",
+			modify: func(card *models.RateCard) { card.ZoneMatrix["world"]["asia"] = 2 },
+			err:    ErrorUnknownZone,
+		},
+		{
+			name:   "zero lane factor",
+			modify: func(card *models.RateCard) { card.ZoneMatrix["world"]["world"] = 0 },
+			err:    ErrorInvalidFactor,
+		},
+		{
+			name:   "no weight brackets",
+			modify: func(card *models.RateCard) { card.WeightBrackets = nil },
+			err:    ErrorNoWeightBrackets,
+		},
+		{
+			name: "unsorted weight brackets",
+			modify: func(card *models.RateCard) {
+				card.WeightBrackets[1], card.WeightBrackets[2] = card.WeightBrackets[2], card.WeightBrackets[1]
+			},
+			err: ErrorInvalidWeightBracket,
+		},
+		{
+			name:   "first weight bracket is not from zero",
+			modify: func(card *models.RateCard) { card.WeightBrackets[0].From = 1 },
+			err:    ErrorInvalidWeightBracket,
+		},
+		{
+			name:   "max weight inside the last bracket",
+			modify: func(card *models.RateCard) { card.MaxWeight = 50 },
+			err:    ErrorInvalidMaxWeight,
+		},
+		{
+			name:   "negative volumetric divisor",
+			modify: func(card *models.RateCard) { card.VolumetricDivisor = -1 },
+			err:    ErrorInvalidVolumetricDivisor,
+		},
+		{
+			name:   "zero multiplier",
+			modify: func(card *models.RateCard) { card.Multipliers = []models.Multiplier{{Name: "fuel"}} },
+			err:    ErrorInvalidFactor,
+		},
+		{
+			name:   "negative returns factor",
+			modify: func(card *models.RateCard) { card.ReturnsFactor = -0.5 },
+			err:    ErrorInvalidFactor,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			card := DefaultRateCard()
+			tC.modify(&card)
+
+			err := ValidateRateCard(card)
+			require.True(t, errors.Is(err, tC.err), "expected %v, got %v", tC.err, err)
+		})
+	}
+}
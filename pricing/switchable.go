@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"sync"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+)
+
+// engine whose rate card may be replaced while the server runs
+type SwitchableEngine struct {
+	mu     sync.RWMutex
+	engine Engine
+}
+
+func InitSwitchableEngine(engine Engine) *SwitchableEngine {
+	return &SwitchableEngine{engine: engine}
+}
+
+// price with the new engine from now on
+func (e *SwitchableEngine) Switch(engine Engine) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.engine = engine
+}
+
+func (e *SwitchableEngine) current() Engine {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.engine
+}
+
+func (e *SwitchableEngine) Price(fromCountryCode, toCountryCode string, parcel models.Parcel) (money.Money, error) {
+	return e.current().Price(fromCountryCode, toCountryCode, parcel)
+}
+
+func (e *SwitchableEngine) Quote(fromCountryCode, toCountryCode string, parcel models.Parcel) (models.PriceBreakdown, error) {
+	return e.current().Quote(fromCountryCode, toCountryCode, parcel)
+}
+
+func (e *SwitchableEngine) QuoteReturn(fromCountryCode, toCountryCode string, parcel models.Parcel) (models.PriceBreakdown, error) {
+	return e.current().QuoteReturn(fromCountryCode, toCountryCode, parcel)
+}
+
+func (e *SwitchableEngine) BillableWeight(parcel models.Parcel) (float64, float64) {
+	return e.current().BillableWeight(parcel)
+}
+
+func (e *SwitchableEngine) RegionFactor(countryCode string) float64 {
+	return e.current().RegionFactor(countryCode)
+}
+
+func (e *SwitchableEngine) Lane(fromCountryCode, toCountryCode string) models.Lane {
+	return e.current().Lane(fromCountryCode, toCountryCode)
+}
+
+func (e *SwitchableEngine) WeightAmount(weight float64) (money.Money, error) {
+	return e.current().WeightAmount(weight)
+}
+
+func (e *SwitchableEngine) RateCard() models.RateCard {
+	return e.current().RateCard()
+}
+
+// engine which is used now, so all pieces of a shipment are priced by the same rate card even if it is switched meanwhile
+func Current(engine Engine) Engine {
+	if switchable, ok := engine.(*SwitchableEngine); ok {
+		return switchable.current()
+	}
+	return engine
+}
@@ -0,0 +1,27 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwitchableEngine_Switch(t *testing.T) {
+	engine, err := InitEngine(DefaultRateCard())
+	require.NoError(t, err)
+
+	card := DefaultRateCard()
+	card.Version = "2022-02"
+	next, err := InitEngine(card)
+	require.NoError(t, err)
+
+	switchable := InitSwitchableEngine(engine)
+	current := Current(switchable)
+	require.Equal(t, "default", switchable.RateCard().Version)
+
+	switchable.Switch(next)
+	require.Equal(t, "2022-02", switchable.RateCard().Version)
+	// the taken engine keeps its rate card
+	require.Equal(t, "default", current.RateCard().Version)
+	require.Equal(t, engine, Current(engine))
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"gorm.io/gorm"
+)
+
+var ErrorAPIKeyNotFound error = errors.New("api key not found")
+
+// API key model (only the hash of the key is stored)
+type APIKeyModel struct {
+	gorm.Model
+	Name      string
+	Client    string `gorm:"index"`
+	Tenant    string
+	Prefix    string `gorm:"uniqueIndex"`
+	Hash      string
+	RevokedAt *time.Time
+}
+
+func APIKeyModelToDomain(key APIKeyModel) models.APIKey {
+	return models.APIKey{
+		Id:        key.ID,
+		Name:      key.Name,
+		Client:    key.Client,
+		Tenant:    key.Tenant,
+		Prefix:    key.Prefix,
+		Hash:      key.Hash,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+func APIKeyModelFromDomain(key models.APIKey) APIKeyModel {
+	return APIKeyModel{
+		Name:      key.Name,
+		Client:    key.Client,
+		Tenant:    key.Tenant,
+		Prefix:    key.Prefix,
+		Hash:      key.Hash,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+//go:generate mockgen -source=apiKey.go -destination=mocks/apiKey.go
+type APIKeyRepository interface {
+	CreateAPIKey(key models.APIKey) (models.APIKey, error)
+	GetAPIKeyByPrefix(prefix string) (models.APIKey, error)
+	ListAPIKeys() ([]models.APIKey, error)
+	RevokeAPIKey(keyID uint, revokedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func InitAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) CreateAPIKey(key models.APIKey) (models.APIKey, error) {
+	model := APIKeyModelFromDomain(key)
+
+	res := r.db.Create(&model)
+	if res.Error != nil {
+		return models.APIKey{}, res.Error
+	}
+
+	return APIKeyModelToDomain(model), nil
+}
+
+func (r *apiKeyRepository) GetAPIKeyByPrefix(prefix string) (models.APIKey, error) {
+	var model APIKeyModel
+
+	res := r.db.Where("prefix = ?", prefix).First(&model)
+	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		return models.APIKey{}, ErrorAPIKeyNotFound
+	}
+	if res.Error != nil {
+		return models.APIKey{}, res.Error
+	}
+
+	return APIKeyModelToDomain(model), nil
+}
+
+// get all keys (with the revoked ones) in the order they were created
+func (r *apiKeyRepository) ListAPIKeys() ([]models.APIKey, error) {
+	var keyModels []APIKeyModel
+	res := r.db.Order("id").Find(&keyModels)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	keys := make([]models.APIKey, 0, len(keyModels))
+	for _, key := range keyModels {
+		keys = append(keys, APIKeyModelToDomain(key))
+	}
+	return keys, nil
+}
+
+// revoke the key (a revoked key stays in the list, but can't be used)
+func (r *apiKeyRepository) RevokeAPIKey(keyID uint, revokedAt time.Time) error {
+	res := r.db.Model(&APIKeyModel{}).
+		Where("id = ? AND revoked_at IS NULL", keyID).
+		Update("revoked_at", revokedAt)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrorAPIKeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"gorm.io/gorm"
+)
+
+// fx rate model
+type FxRateModel struct {
+	gorm.Model
+	From          string `gorm:"index:idx_fx_rate_pair"`
+	To            string `gorm:"index:idx_fx_rate_pair"`
+	Rate          float64
+	EffectiveFrom time.Time
+}
+
+func FxRateModelToDomain(rate FxRateModel) models.FxRate {
+	return models.FxRate{
+		From:          rate.From,
+		To:            rate.To,
+		Rate:          rate.Rate,
+		EffectiveFrom: rate.EffectiveFrom,
+	}
+}
+
+func FxRateModelFromDomain(rate models.FxRate) FxRateModel {
+	return FxRateModel{
+		From:          rate.From,
+		To:            rate.To,
+		Rate:          rate.Rate,
+		EffectiveFrom: rate.EffectiveFrom,
+	}
+}
+
+//go:generate mockgen -source=fxRate.go -destination=mocks/fxRate.go
+type FxRateRepository interface {
+	GetAllFxRates() ([]models.FxRate, error)
+	CreateFxRate(rate models.FxRate) error
+}
+
+type fxRateRepository struct {
+	db *gorm.DB
+}
+
+func InitFxRateRepository(db *gorm.DB) FxRateRepository {
+	return &fxRateRepository{db: db}
+}
+
+// get all fx rates (with all effective dates)
+func (r *fxRateRepository) GetAllFxRates() ([]models.FxRate, error) {
+	var rateModels []FxRateModel
+	res := r.db.Order("effective_from").Find(&rateModels)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	rates := make([]models.FxRate, 0, len(rateModels))
+	for _, rate := range rateModels {
+		rates = append(rates, FxRateModelToDomain(rate))
+	}
+	return rates, nil
+}
+
+// create a new fx rate
+func (r *fxRateRepository) CreateFxRate(rate models.FxRate) error {
+	model := FxRateModelFromDomain(rate)
+	res := r.db.Create(&model)
+	return res.Error
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrorIdempotencyKeyNotFound error = errors.New("idempotency key not found")
+
+// idempotency key model (the key of the owner is the primary key, so a key is saved only once)
+type IdempotencyKeyModel struct {
+	Owner        string `gorm:"primaryKey"`
+	Key          string `gorm:"primaryKey"`
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time `gorm:"index"`
+}
+
+func IdempotencyKeyModelToDomain(key IdempotencyKeyModel) models.IdempotencyKey {
+	return models.IdempotencyKey{
+		Owner:        key.Owner,
+		Key:          key.Key,
+		RequestHash:  key.RequestHash,
+		StatusCode:   key.StatusCode,
+		ResponseBody: key.ResponseBody,
+		CreatedAt:    key.CreatedAt,
+		ExpiresAt:    key.ExpiresAt,
+	}
+}
+
+func IdempotencyKeyModelFromDomain(key models.IdempotencyKey) IdempotencyKeyModel {
+	return IdempotencyKeyModel{
+		Owner:        key.Owner,
+		Key:          key.Key,
+		RequestHash:  key.RequestHash,
+		StatusCode:   key.StatusCode,
+		ResponseBody: key.ResponseBody,
+		CreatedAt:    key.CreatedAt,
+		ExpiresAt:    key.ExpiresAt,
+	}
+}
+
+//go:generate mockgen -source=idempotency.go -destination=mocks/idempotency.go
+type IdempotencyRepository interface {
+	CreateIdempotencyKey(key models.IdempotencyKey) (bool, error)
+	GetIdempotencyKey(owner, key string) (models.IdempotencyKey, error)
+	SaveIdempotentResponse(owner, key string, statusCode int, body []byte) error
+	DeleteIdempotencyKey(owner, key string) error
+	DeleteExpiredIdempotencyKeys(now time.Time) error
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func InitIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// save a new key (false if the key is already saved and not expired yet)
+func (r *idempotencyRepository) CreateIdempotencyKey(key models.IdempotencyKey) (bool, error) {
+	model := IdempotencyKeyModelFromDomain(key)
+
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// the expired key may be used again
+		res := tx.Where("owner = ? AND key = ? AND expires_at <= ?", model.Owner, model.Key, model.CreatedAt).Delete(&IdempotencyKeyModel{})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		// the key of a concurrent request is not overwritten
+		res = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+		if res.Error != nil {
+			return res.Error
+		}
+		created = res.RowsAffected == 1
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return created, nil
+}
+
+func (r *idempotencyRepository) GetIdempotencyKey(owner, key string) (models.IdempotencyKey, error) {
+	var model IdempotencyKeyModel
+	res := r.db.Where("owner = ? AND key = ?", owner, key).First(&model)
+	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		return models.IdempotencyKey{}, ErrorIdempotencyKeyNotFound
+	}
+	if res.Error != nil {
+		return models.IdempotencyKey{}, res.Error
+	}
+
+	return IdempotencyKeyModelToDomain(model), nil
+}
+
+// save the response of the request with the key
+func (r *idempotencyRepository) SaveIdempotentResponse(owner, key string, statusCode int, body []byte) error {
+	res := r.db.Model(&IdempotencyKeyModel{}).
+		Where("owner = ? AND key = ?", owner, key).
+		Updates(map[string]interface{}{"status_code": statusCode, "response_body": body})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrorIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+// delete the key (the request with the key may be made again)
+func (r *idempotencyRepository) DeleteIdempotencyKey(owner, key string) error {
+	return r.db.Where("owner = ? AND key = ?", owner, key).Delete(&IdempotencyKeyModel{}).Error
+}
+
+func (r *idempotencyRepository) DeleteExpiredIdempotencyKeys(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&IdempotencyKeyModel{}).Error
+}
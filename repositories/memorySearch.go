@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+)
+
+// ranking weights of the search fields (as default weights of ts_rank)
+var memorySearchWeights = map[string]float64{"A": 1, "B": 0.4, "C": 0.2}
+
+// the most common of the stop words which are not searched by the database
+var memorySearchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "by": true, "for": true, "from": true,
+	"in": true, "of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// in-memory search over the given shipments (used when there is no database, e.g. in tests)
+type memoryShipmentSearch struct {
+	shipments []models.Shipment
+	// client which created the shipments (empty for all shipments)
+	owner string
+	// tenant of the shipments (empty for all tenants)
+	tenantID string
+}
+
+func InitMemoryShipmentSearch(shipments []models.Shipment) ShipmentSearchRepository {
+	return &memoryShipmentSearch{shipments: shipments}
+}
+
+// search over the shipments created by the owner
+func (r *memoryShipmentSearch) WithOwner(owner string) ShipmentSearchRepository {
+	scoped := *r
+	scoped.owner = owner
+	return &scoped
+}
+
+// search over the shipments of the tenant
+func (r *memoryShipmentSearch) WithTenant(tenantID string) ShipmentSearchRepository {
+	scoped := *r
+	scoped.tenantID = tenantID
+	return &scoped
+}
+
+// search shipments by any of the terms, the best matches first
+func (r *memoryShipmentSearch) SearchShipments(query models.SearchQuery) ([]models.SearchResult, error) {
+	terms := map[string]bool{}
+	for _, term := range query.Terms {
+		term = strings.ToLower(term)
+		if !memorySearchStopWords[term] {
+			terms[term] = true
+		}
+	}
+
+	results := []models.SearchResult{}
+	for _, shipment := range r.shipments {
+		if r.owner != "" && shipment.Owner != r.owner {
+			continue
+		}
+		if r.tenantID != "" && shipment.TenantID != r.tenantID {
+			continue
+		}
+
+		values := map[string]string{
+			"fromName":    shipment.FromName,
+			"toName":      shipment.ToName,
+			"fromEmail":   shipment.FromEmail,
+			"toEmail":     shipment.ToEmail,
+			"fromAddress": shipment.FromAddress,
+			"toAddress":   shipment.ToAddress,
+		}
+
+		result := models.SearchResult{Shipment: shipment, Highlights: map[string]string{}}
+		for _, field := range searchFields {
+			highlight, matches := highlightWords(values[field.name], terms)
+			if matches == 0 {
+				continue
+			}
+			result.Rank += float64(matches) * memorySearchWeights[field.weight]
+			result.Highlights[field.name] = highlight
+		}
+
+		if result.Rank > 0 {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Shipment.Id < results[j].Shipment.Id
+	})
+
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
+
+// wrap the words of the text which are in terms into highlight tags (the rest of the text is HTML-escaped)
+func highlightWords(text string, terms map[string]bool) (string, int) {
+	var (
+		highlighted strings.Builder
+		matches     int
+		last        int
+	)
+
+	for _, loc := range helpers.SearchWordIndexes(text) {
+		word := text[loc[0]:loc[1]]
+		if !terms[strings.ToLower(word)] {
+			continue
+		}
+
+		highlighted.WriteString(html.EscapeString(text[last:loc[0]]))
+		highlighted.WriteString(models.HighlightStart + html.EscapeString(word) + models.HighlightStop)
+		last = loc[1]
+		matches++
+	}
+	highlighted.WriteString(html.EscapeString(text[last:]))
+
+	return highlighted.String(), matches
+}
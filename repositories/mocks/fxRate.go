@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: fxRate.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	reflect "reflect"
+
+	models "github.com/Taras-Rm/shipment/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFxRateRepository is a mock of FxRateRepository interface.
+type MockFxRateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFxRateRepositoryMockRecorder
+}
+
+// MockFxRateRepositoryMockRecorder is the mock recorder for MockFxRateRepository.
+type MockFxRateRepositoryMockRecorder struct {
+	mock *MockFxRateRepository
+}
+
+// NewMockFxRateRepository creates a new mock instance.
+func NewMockFxRateRepository(ctrl *gomock.Controller) *MockFxRateRepository {
+	mock := &MockFxRateRepository{ctrl: ctrl}
+	mock.recorder = &MockFxRateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFxRateRepository) EXPECT() *MockFxRateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateFxRate mocks base method.
+func (m *MockFxRateRepository) CreateFxRate(rate models.FxRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFxRate", rate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFxRate indicates an expected call of CreateFxRate.
+func (mr *MockFxRateRepositoryMockRecorder) CreateFxRate(rate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFxRate", reflect.TypeOf((*MockFxRateRepository)(nil).CreateFxRate), rate)
+}
+
+// GetAllFxRates mocks base method.
+func (m *MockFxRateRepository) GetAllFxRates() ([]models.FxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllFxRates")
+	ret0, _ := ret[0].([]models.FxRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllFxRates indicates an expected call of GetAllFxRates.
+func (mr *MockFxRateRepositoryMockRecorder) GetAllFxRates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllFxRates", reflect.TypeOf((*MockFxRateRepository)(nil).GetAllFxRates))
+}
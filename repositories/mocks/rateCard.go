@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rateCard.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Taras-Rm/shipment/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRateCardRepository is a mock of RateCardRepository interface.
+type MockRateCardRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateCardRepositoryMockRecorder
+}
+
+// MockRateCardRepositoryMockRecorder is the mock recorder for MockRateCardRepository.
+type MockRateCardRepositoryMockRecorder struct {
+	mock *MockRateCardRepository
+}
+
+// NewMockRateCardRepository creates a new mock instance.
+func NewMockRateCardRepository(ctrl *gomock.Controller) *MockRateCardRepository {
+	mock := &MockRateCardRepository{ctrl: ctrl}
+	mock.recorder = &MockRateCardRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateCardRepository) EXPECT() *MockRateCardRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRateCard mocks base method.
+func (m *MockRateCardRepository) CreateRateCard(rateCard models.RateCard) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRateCard", rateCard)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRateCard indicates an expected call of CreateRateCard.
+func (mr *MockRateCardRepositoryMockRecorder) CreateRateCard(rateCard interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRateCard", reflect.TypeOf((*MockRateCardRepository)(nil).CreateRateCard), rateCard)
+}
+
+// GetActiveRateCard mocks base method.
+func (m *MockRateCardRepository) GetActiveRateCard(at time.Time) (models.RateCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveRateCard", at)
+	ret0, _ := ret[0].(models.RateCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveRateCard indicates an expected call of GetActiveRateCard.
+func (mr *MockRateCardRepositoryMockRecorder) GetActiveRateCard(at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveRateCard", reflect.TypeOf((*MockRateCardRepository)(nil).GetActiveRateCard), at)
+}
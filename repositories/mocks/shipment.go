@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	models "github.com/Taras-Rm/shipment/models"
+	repositories "github.com/Taras-Rm/shipment/repositories"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,33 +35,62 @@ func (m *MockShipmentRepository) EXPECT() *MockShipmentRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CreateShipment mocks base method.
-func (m *MockShipmentRepository) CreateShipment(shipment models.Shipment) error {
+// CancelShipment mocks base method.
+func (m *MockShipmentRepository) CancelShipment(shipmentID uint, from models.ShipmentStatus, cancellation models.Cancellation) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateShipment", shipment)
+	ret := m.ctrl.Call(m, "CancelShipment", shipmentID, from, cancellation)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// CancelShipment indicates an expected call of CancelShipment.
+func (mr *MockShipmentRepositoryMockRecorder) CancelShipment(shipmentID, from, cancellation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelShipment", reflect.TypeOf((*MockShipmentRepository)(nil).CancelShipment), shipmentID, from, cancellation)
+}
+
+// CreateShipment mocks base method.
+func (m *MockShipmentRepository) CreateShipment(shipment models.Shipment) (models.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShipment", shipment)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // CreateShipment indicates an expected call of CreateShipment.
 func (mr *MockShipmentRepositoryMockRecorder) CreateShipment(shipment interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShipment", reflect.TypeOf((*MockShipmentRepository)(nil).CreateShipment), shipment)
 }
 
-// GetAllShipments mocks base method.
-func (m *MockShipmentRepository) GetAllShipments() ([]models.Shipment, error) {
+// CreateShipments mocks base method.
+func (m *MockShipmentRepository) CreateShipments(shipments []models.Shipment) ([]models.Shipment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllShipments")
+	ret := m.ctrl.Call(m, "CreateShipments", shipments)
 	ret0, _ := ret[0].([]models.Shipment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllShipments indicates an expected call of GetAllShipments.
-func (mr *MockShipmentRepositoryMockRecorder) GetAllShipments() *gomock.Call {
+// CreateShipments indicates an expected call of CreateShipments.
+func (mr *MockShipmentRepositoryMockRecorder) CreateShipments(shipments interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllShipments", reflect.TypeOf((*MockShipmentRepository)(nil).GetAllShipments))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShipments", reflect.TypeOf((*MockShipmentRepository)(nil).CreateShipments), shipments)
+}
+
+// EachShipment mocks base method.
+func (m *MockShipmentRepository) EachShipment(query models.ShipmentQuery, fn func(models.Shipment) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachShipment", query, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachShipment indicates an expected call of EachShipment.
+func (mr *MockShipmentRepositoryMockRecorder) EachShipment(query, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachShipment", reflect.TypeOf((*MockShipmentRepository)(nil).EachShipment), query, fn)
 }
 
 // GetShipmentByID mocks base method.
@@ -77,3 +107,90 @@ func (mr *MockShipmentRepositoryMockRecorder) GetShipmentByID(shipmentID interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentByID", reflect.TypeOf((*MockShipmentRepository)(nil).GetShipmentByID), shipmentID)
 }
+
+// GetShipmentByTrackingNumber mocks base method.
+func (m *MockShipmentRepository) GetShipmentByTrackingNumber(number string) (models.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShipmentByTrackingNumber", number)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShipmentByTrackingNumber indicates an expected call of GetShipmentByTrackingNumber.
+func (mr *MockShipmentRepositoryMockRecorder) GetShipmentByTrackingNumber(number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentByTrackingNumber", reflect.TypeOf((*MockShipmentRepository)(nil).GetShipmentByTrackingNumber), number)
+}
+
+// ListShipments mocks base method.
+func (m *MockShipmentRepository) ListShipments(query models.ShipmentQuery) (models.ShipmentPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShipments", query)
+	ret0, _ := ret[0].(models.ShipmentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShipments indicates an expected call of ListShipments.
+func (mr *MockShipmentRepositoryMockRecorder) ListShipments(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShipments", reflect.TypeOf((*MockShipmentRepository)(nil).ListShipments), query)
+}
+
+// UpdateShipment mocks base method.
+func (m *MockShipmentRepository) UpdateShipment(shipment models.Shipment, status models.ShipmentStatus) (models.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShipment", shipment, status)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateShipment indicates an expected call of UpdateShipment.
+func (mr *MockShipmentRepositoryMockRecorder) UpdateShipment(shipment, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShipment", reflect.TypeOf((*MockShipmentRepository)(nil).UpdateShipment), shipment, status)
+}
+
+// UpdateShipmentStatus mocks base method.
+func (m *MockShipmentRepository) UpdateShipmentStatus(shipmentID uint, from, to models.ShipmentStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShipmentStatus", shipmentID, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateShipmentStatus indicates an expected call of UpdateShipmentStatus.
+func (mr *MockShipmentRepositoryMockRecorder) UpdateShipmentStatus(shipmentID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShipmentStatus", reflect.TypeOf((*MockShipmentRepository)(nil).UpdateShipmentStatus), shipmentID, from, to)
+}
+
+// WithOwner mocks base method.
+func (m *MockShipmentRepository) WithOwner(owner string) repositories.ShipmentRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithOwner", owner)
+	ret0, _ := ret[0].(repositories.ShipmentRepository)
+	return ret0
+}
+
+// WithOwner indicates an expected call of WithOwner.
+func (mr *MockShipmentRepositoryMockRecorder) WithOwner(owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithOwner", reflect.TypeOf((*MockShipmentRepository)(nil).WithOwner), owner)
+}
+
+// WithTenant mocks base method.
+func (m *MockShipmentRepository) WithTenant(tenantID string) repositories.ShipmentRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTenant", tenantID)
+	ret0, _ := ret[0].(repositories.ShipmentRepository)
+	return ret0
+}
+
+// WithTenant indicates an expected call of WithTenant.
+func (mr *MockShipmentRepositoryMockRecorder) WithTenant(tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTenant", reflect.TypeOf((*MockShipmentRepository)(nil).WithTenant), tenantID)
+}
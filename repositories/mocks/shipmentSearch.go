@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: shipmentSearch.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	reflect "reflect"
+
+	models "github.com/Taras-Rm/shipment/models"
+	repositories "github.com/Taras-Rm/shipment/repositories"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockShipmentSearchRepository is a mock of ShipmentSearchRepository interface.
+type MockShipmentSearchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShipmentSearchRepositoryMockRecorder
+}
+
+// MockShipmentSearchRepositoryMockRecorder is the mock recorder for MockShipmentSearchRepository.
+type MockShipmentSearchRepositoryMockRecorder struct {
+	mock *MockShipmentSearchRepository
+}
+
+// NewMockShipmentSearchRepository creates a new mock instance.
+func NewMockShipmentSearchRepository(ctrl *gomock.Controller) *MockShipmentSearchRepository {
+	mock := &MockShipmentSearchRepository{ctrl: ctrl}
+	mock.recorder = &MockShipmentSearchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShipmentSearchRepository) EXPECT() *MockShipmentSearchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// SearchShipments mocks base method.
+func (m *MockShipmentSearchRepository) SearchShipments(query models.SearchQuery) ([]models.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchShipments", query)
+	ret0, _ := ret[0].([]models.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchShipments indicates an expected call of SearchShipments.
+func (mr *MockShipmentSearchRepositoryMockRecorder) SearchShipments(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchShipments", reflect.TypeOf((*MockShipmentSearchRepository)(nil).SearchShipments), query)
+}
+
+// WithOwner mocks base method.
+func (m *MockShipmentSearchRepository) WithOwner(owner string) repositories.ShipmentSearchRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithOwner", owner)
+	ret0, _ := ret[0].(repositories.ShipmentSearchRepository)
+	return ret0
+}
+
+// WithOwner indicates an expected call of WithOwner.
+func (mr *MockShipmentSearchRepositoryMockRecorder) WithOwner(owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithOwner", reflect.TypeOf((*MockShipmentSearchRepository)(nil).WithOwner), owner)
+}
+
+// WithTenant mocks base method.
+func (m *MockShipmentSearchRepository) WithTenant(tenantID string) repositories.ShipmentSearchRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTenant", tenantID)
+	ret0, _ := ret[0].(repositories.ShipmentSearchRepository)
+	return ret0
+}
+
+// WithTenant indicates an expected call of WithTenant.
+func (mr *MockShipmentSearchRepositoryMockRecorder) WithTenant(tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTenant", reflect.TypeOf((*MockShipmentSearchRepository)(nil).WithTenant), tenantID)
+}
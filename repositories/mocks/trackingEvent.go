@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: trackingEvent.go
+
+// Package mock_repositories is a generated GoMock package.
+package mock_repositories
+
+import (
+	reflect "reflect"
+
+	models "github.com/Taras-Rm/shipment/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTrackingEventRepository is a mock of TrackingEventRepository interface.
+type MockTrackingEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrackingEventRepositoryMockRecorder
+}
+
+// MockTrackingEventRepositoryMockRecorder is the mock recorder for MockTrackingEventRepository.
+type MockTrackingEventRepositoryMockRecorder struct {
+	mock *MockTrackingEventRepository
+}
+
+// NewMockTrackingEventRepository creates a new mock instance.
+func NewMockTrackingEventRepository(ctrl *gomock.Controller) *MockTrackingEventRepository {
+	mock := &MockTrackingEventRepository{ctrl: ctrl}
+	mock.recorder = &MockTrackingEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrackingEventRepository) EXPECT() *MockTrackingEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateTrackingEvent mocks base method.
+func (m *MockTrackingEventRepository) CreateTrackingEvent(shipmentID uint, event models.TrackingEvent) (models.TrackingEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTrackingEvent", shipmentID, event)
+	ret0, _ := ret[0].(models.TrackingEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTrackingEvent indicates an expected call of CreateTrackingEvent.
+func (mr *MockTrackingEventRepositoryMockRecorder) CreateTrackingEvent(shipmentID, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrackingEvent", reflect.TypeOf((*MockTrackingEventRepository)(nil).CreateTrackingEvent), shipmentID, event)
+}
+
+// GetTrackingEvents mocks base method.
+func (m *MockTrackingEventRepository) GetTrackingEvents(shipmentID uint) ([]models.TrackingEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrackingEvents", shipmentID)
+	ret0, _ := ret[0].([]models.TrackingEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrackingEvents indicates an expected call of GetTrackingEvents.
+func (mr *MockTrackingEventRepositoryMockRecorder) GetTrackingEvents(shipmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrackingEvents", reflect.TypeOf((*MockTrackingEventRepository)(nil).GetTrackingEvents), shipmentID)
+}
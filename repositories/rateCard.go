@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrorRateCardNotFound      error = errors.New("rate card not found")
+	ErrorRateCardVersionExists error = errors.New("rate card version already exists")
+)
+
+// rate card model (the whole card is stored as JSON document)
+type RateCardModel struct {
+	gorm.Model
+	Version       string `gorm:"uniqueIndex"`
+	EffectiveFrom time.Time
+	Content       string
+}
+
+func RateCardModelToDomain(rateCard RateCardModel) (models.RateCard, error) {
+	var card models.RateCard
+	err := json.Unmarshal([]byte(rateCard.Content), &card)
+	if err != nil {
+		return card, err
+	}
+
+	card.Version = rateCard.Version
+	card.EffectiveFrom = rateCard.EffectiveFrom
+
+	return card, nil
+}
+
+func RateCardModelFromDomain(rateCard models.RateCard) (RateCardModel, error) {
+	content, err := json.Marshal(rateCard)
+	if err != nil {
+		return RateCardModel{}, err
+	}
+
+	return RateCardModel{
+		Version:       rateCard.Version,
+		EffectiveFrom: rateCard.EffectiveFrom,
+		Content:       string(content),
+	}, nil
+}
+
+//go:generate mockgen -source=rateCard.go -destination=mocks/rateCard.go
+type RateCardRepository interface {
+	GetActiveRateCard(at time.Time) (models.RateCard, error)
+	CreateRateCard(rateCard models.RateCard) error
+}
+
+type rateCardRepository struct {
+	db *gorm.DB
+}
+
+func InitRateCardRepository(db *gorm.DB) RateCardRepository {
+	return &rateCardRepository{db: db}
+}
+
+// get the latest rate card which is already effective
+func (r *rateCardRepository) GetActiveRateCard(at time.Time) (models.RateCard, error) {
+	var rateCard RateCardModel
+	res := r.db.Where("effective_from <= ?", at).Order("effective_from desc").First(&rateCard)
+	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		return models.RateCard{}, ErrorRateCardNotFound
+	}
+	if res.Error != nil {
+		return models.RateCard{}, res.Error
+	}
+	return RateCardModelToDomain(rateCard)
+}
+
+// create a new version of rate card
+func (r *rateCardRepository) CreateRateCard(rateCard models.RateCard) error {
+	model, err := RateCardModelFromDomain(rateCard)
+	if err != nil {
+		return err
+	}
+
+	// versions are never overwritten, a changed rate card gets a new version
+	var count int64
+	res := r.db.Model(&RateCardModel{}).Where("version = ?", model.Version).Count(&count)
+	if res.Error != nil {
+		return res.Error
+	}
+	if count != 0 {
+		return ErrorRateCardVersionExists
+	}
+
+	res = r.db.Create(&model)
+	return res.Error
+}
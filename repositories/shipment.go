@@ -1,28 +1,83 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // shipment model
 type ShipmentModel struct {
 	gorm.Model
-	FromName        string
-	FromEmail       string
-	FromAddress     string
-	FromCountryCode string
-	ToName          string
-	ToEmail         string
-	ToAddress       string
-	ToCountryCode   string
+	TrackingNumber   string `gorm:"uniqueIndex"`
+	FromName         string
+	FromEmail        string
+	FromAddress      string
+	FromCountryCode  string
+	ToName           string
+	ToEmail          string
+	ToAddress        string
+	ToCountryCode    string
+	Weight           float64
+	Length           float64
+	Width            float64
+	Height           float64
+	Price            money.Money `gorm:"embedded;embeddedPrefix:price_"`
+	Status           string      `gorm:"index;default:created"`
+	RateCardVersion  string
+	VolumetricWeight float64
+	BillableWeight   float64
+	LaneType         string
+	LaneOrigin       string
+	LaneDestination  string
+	LaneFactor       float64
+	FxFrom           string
+	FxRate           float64
+	FxEffectiveFrom  time.Time
+	CancellationFee  money.Money `gorm:"embedded;embeddedPrefix:cancellation_fee_"`
+	Refund           money.Money `gorm:"embedded;embeddedPrefix:refund_"`
+	ReturnOfID       *uint       `gorm:"index"`
+	Owner            string      `gorm:"index"`
+	TenantID         string      `gorm:"index"`
+	ReturnOf         *ShipmentModel
+	Returns          []ShipmentModel `gorm:"foreignKey:ReturnOfID"`
+	PriceComponents  []PriceComponentModel
+	Pieces           []ShipmentPieceModel
+	TrackingEvents   []TrackingEventModel
+}
+
+// price component model (part of the shipment price breakdown)
+type PriceComponentModel struct {
+	gorm.Model
+	ShipmentModelID uint `gorm:"index"`
+	Position        int
+	Type            string
+	Name            string
+	Factor          float64
+	Amount          money.Money `gorm:"embedded"`
+}
+
+// shipment piece model (single parcel of the shipment)
+type ShipmentPieceModel struct {
+	gorm.Model
+	ShipmentModelID uint `gorm:"index"`
+	Position        int
 	Weight          float64
-	Price           float64
+	Length          float64
+	Width           float64
+	Height          float64
+	BillableWeight  float64
+	Price           money.Money `gorm:"embedded;embeddedPrefix:price_"`
 }
 
 func ShipmentModelToDomain(shipment ShipmentModel) models.Shipment {
-	return models.Shipment{
+	domain := models.Shipment{
 		Id:              shipment.ID,
+		TrackingNumber:  shipment.TrackingNumber,
 		FromName:        shipment.FromName,
 		FromEmail:       shipment.FromEmail,
 		FromAddress:     shipment.FromAddress,
@@ -30,14 +85,97 @@ func ShipmentModelToDomain(shipment ShipmentModel) models.Shipment {
 		ToName:          shipment.ToName,
 		ToEmail:         shipment.ToEmail,
 		ToAddress:       shipment.ToAddress,
-		ToCountryCode:   shipment.ToAddress,
+		ToCountryCode:   shipment.ToCountryCode,
 		Weight:          shipment.Weight,
+		Length:          shipment.Length,
+		Width:           shipment.Width,
+		Height:          shipment.Height,
 		Price:           shipment.Price,
+		Status:          models.ShipmentStatus(shipment.Status),
+		Owner:           shipment.Owner,
+		TenantID:        shipment.TenantID,
+	}
+
+	// price was converted from the rate card currency
+	if shipment.FxFrom != "" {
+		domain.FxRate = &models.FxRate{
+			From:          shipment.FxFrom,
+			To:            shipment.Price.Currency,
+			Rate:          shipment.FxRate,
+			EffectiveFrom: shipment.FxEffectiveFrom,
+		}
+	}
+
+	// cancelled shipment is soft-deleted
+	if shipment.Status == string(models.StatusCancelled) && shipment.DeletedAt.Valid {
+		domain.Cancellation = &models.Cancellation{
+			CancelledAt: shipment.DeletedAt.Time,
+			Fee:         shipment.CancellationFee,
+			Refund:      shipment.Refund,
+		}
+	}
+
+	// return shipment is linked to the original one
+	if shipment.ReturnOfID != nil {
+		domain.ReturnOf = &models.ShipmentLink{Id: *shipment.ReturnOfID}
+		if shipment.ReturnOf != nil {
+			domain.ReturnOf = shipmentModelToLink(*shipment.ReturnOf)
+		}
+	}
+	for _, returned := range shipment.Returns {
+		domain.Returns = append(domain.Returns, *shipmentModelToLink(returned))
+	}
+
+	// breakdown is available only when price components are loaded
+	if len(shipment.PriceComponents) > 0 {
+		domain.PriceBreakdown = &models.PriceBreakdown{
+			RateCardVersion: shipment.RateCardVersion,
+			Lane: models.Lane{
+				Type:        models.LaneType(shipment.LaneType),
+				Origin:      shipment.LaneOrigin,
+				Destination: shipment.LaneDestination,
+				Factor:      shipment.LaneFactor,
+			},
+			VolumetricWeight: shipment.VolumetricWeight,
+			BillableWeight:   shipment.BillableWeight,
+			Total:            shipment.Price,
+			FxRate:           domain.FxRate,
+		}
+		for _, component := range shipment.PriceComponents {
+			domain.PriceBreakdown.Components = append(domain.PriceBreakdown.Components, models.PriceComponent{
+				Type:   models.PriceComponentType(component.Type),
+				Name:   component.Name,
+				Factor: component.Factor,
+				Amount: component.Amount,
+			})
+		}
+	}
+
+	for _, piece := range shipment.Pieces {
+		domain.Pieces = append(domain.Pieces, models.Piece{
+			Weight:         piece.Weight,
+			Length:         piece.Length,
+			Width:          piece.Width,
+			Height:         piece.Height,
+			BillableWeight: piece.BillableWeight,
+			Price:          piece.Price,
+		})
+	}
+
+	return domain
+}
+
+func shipmentModelToLink(shipment ShipmentModel) *models.ShipmentLink {
+	return &models.ShipmentLink{
+		Id:             shipment.ID,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         models.ShipmentStatus(shipment.Status),
 	}
 }
 
 func ShipmentModelFromDomain(shipment models.Shipment) ShipmentModel {
-	return ShipmentModel{
+	model := ShipmentModel{
+		TrackingNumber:  shipment.TrackingNumber,
 		FromName:        shipment.FromName,
 		FromEmail:       shipment.FromEmail,
 		FromAddress:     shipment.FromAddress,
@@ -45,49 +183,356 @@ func ShipmentModelFromDomain(shipment models.Shipment) ShipmentModel {
 		ToName:          shipment.ToName,
 		ToEmail:         shipment.ToEmail,
 		ToAddress:       shipment.ToAddress,
-		ToCountryCode:   shipment.ToAddress,
+		ToCountryCode:   shipment.ToCountryCode,
 		Weight:          shipment.Weight,
+		Length:          shipment.Length,
+		Width:           shipment.Width,
+		Height:          shipment.Height,
 		Price:           shipment.Price,
+		Status:          string(shipment.Status),
+		Owner:           shipment.Owner,
+		TenantID:        shipment.TenantID,
+	}
+
+	if shipment.FxRate != nil {
+		model.FxFrom = shipment.FxRate.From
+		model.FxRate = shipment.FxRate.Rate
+		model.FxEffectiveFrom = shipment.FxRate.EffectiveFrom
+	}
+
+	if shipment.ReturnOf != nil {
+		returnOfID := shipment.ReturnOf.Id
+		model.ReturnOfID = &returnOfID
 	}
+
+	if shipment.Cancellation != nil {
+		model.CancellationFee = shipment.Cancellation.Fee
+		model.Refund = shipment.Cancellation.Refund
+		model.DeletedAt = gorm.DeletedAt{Time: shipment.Cancellation.CancelledAt, Valid: true}
+	}
+
+	if shipment.PriceBreakdown != nil {
+		model.RateCardVersion = shipment.PriceBreakdown.RateCardVersion
+		model.LaneType = string(shipment.PriceBreakdown.Lane.Type)
+		model.LaneOrigin = shipment.PriceBreakdown.Lane.Origin
+		model.LaneDestination = shipment.PriceBreakdown.Lane.Destination
+		model.LaneFactor = shipment.PriceBreakdown.Lane.Factor
+		model.VolumetricWeight = shipment.PriceBreakdown.VolumetricWeight
+		model.BillableWeight = shipment.PriceBreakdown.BillableWeight
+		for i, component := range shipment.PriceBreakdown.Components {
+			model.PriceComponents = append(model.PriceComponents, PriceComponentModel{
+				Position: i,
+				Type:     string(component.Type),
+				Name:     component.Name,
+				Factor:   component.Factor,
+				Amount:   component.Amount,
+			})
+		}
+	}
+
+	for i, piece := range shipment.Pieces {
+		model.Pieces = append(model.Pieces, ShipmentPieceModel{
+			Position:       i,
+			Weight:         piece.Weight,
+			Length:         piece.Length,
+			Width:          piece.Width,
+			Height:         piece.Height,
+			BillableWeight: piece.BillableWeight,
+			Price:          piece.Price,
+		})
+	}
+
+	return model
 }
 
+var (
+	ErrorShipmentNotFound error = errors.New("shipment not found")
+	ErrorStatusConflict   error = errors.New("shipment status was changed concurrently")
+)
+
 //go:generate mockgen -source=shipment.go -destination=mocks/shipment.go
 type ShipmentRepository interface {
-	GetAllShipments() ([]models.Shipment, error)
-	CreateShipment(shipment models.Shipment) error
+	ListShipments(query models.ShipmentQuery) (models.ShipmentPage, error)
+	EachShipment(query models.ShipmentQuery, fn func(shipment models.Shipment) error) error
+	CreateShipment(shipment models.Shipment) (models.Shipment, error)
+	CreateShipments(shipments []models.Shipment) ([]models.Shipment, error)
 	GetShipmentByID(shipmentID uint) (models.Shipment, error)
+	GetShipmentByTrackingNumber(number string) (models.Shipment, error)
+	UpdateShipment(shipment models.Shipment, status models.ShipmentStatus) (models.Shipment, error)
+	UpdateShipmentStatus(shipmentID uint, from, to models.ShipmentStatus) error
+	CancelShipment(shipmentID uint, from models.ShipmentStatus, cancellation models.Cancellation) error
+	WithOwner(owner string) ShipmentRepository
+	WithTenant(tenantID string) ShipmentRepository
 }
 
 type shipmentRepository struct {
 	db *gorm.DB
+	// client which created the shipments (empty for all shipments)
+	owner string
+	// tenant of the shipments (empty for all tenants)
+	tenantID string
 }
 
 func InitShipmentRepository(db *gorm.DB) ShipmentRepository {
 	return &shipmentRepository{db: db}
 }
 
-// get all shipments that have been sent to the system
-func (r *shipmentRepository) GetAllShipments() ([]models.Shipment, error) {
-	var shipments []models.Shipment
-	res := r.db.Find(&shipments)
+// repository of the shipments created by the owner (new shipments are created for the owner)
+func (r *shipmentRepository) WithOwner(owner string) ShipmentRepository {
+	scoped := *r
+	scoped.owner = owner
+	return &scoped
+}
 
-	return shipments, res.Error
+// repository of the shipments of the tenant (new shipments are created for the tenant)
+func (r *shipmentRepository) WithTenant(tenantID string) ShipmentRepository {
+	scoped := *r
+	scoped.tenantID = tenantID
+	return &scoped
 }
 
-// create a new shipment
-func (r *shipmentRepository) CreateShipment(shipment models.Shipment) error {
+// shipments of the tenant and of the owner only (every query is scoped by it)
+func (r *shipmentRepository) scope(db *gorm.DB) *gorm.DB {
+	if r.tenantID != "" {
+		db = db.Where("tenant_id = ?", r.tenantID)
+	}
+	if r.owner != "" {
+		db = db.Where("owner = ?", r.owner)
+	}
+	return db
+}
+
+// model of the new shipment of the tenant and of the owner
+func (r *shipmentRepository) newModel(shipment models.Shipment) ShipmentModel {
 	model := ShipmentModelFromDomain(shipment)
+	if r.tenantID != "" {
+		model.TenantID = r.tenantID
+	}
+	if r.owner != "" {
+		model.Owner = r.owner
+	}
+	return model
+}
+
+// create a new shipment
+func (r *shipmentRepository) CreateShipment(shipment models.Shipment) (models.Shipment, error) {
+	model := r.newModel(shipment)
 
 	res := r.db.Create(&model)
+	if res.Error != nil {
+		return models.Shipment{}, res.Error
+	}
 
-	return res.Error
+	return ShipmentModelToDomain(model), nil
 }
 
-// get a single shipment by it's ID
+// number of shipments which are inserted by a single statement
+const createBatchSize = 100
+
+// create several shipments in a single transaction (none is created on error)
+func (r *shipmentRepository) CreateShipments(shipments []models.Shipment) ([]models.Shipment, error) {
+	batch := make([]ShipmentModel, 0, len(shipments))
+	for _, shipment := range shipments {
+		batch = append(batch, r.newModel(shipment))
+	}
+
+	res := r.db.CreateInBatches(&batch, createBatchSize)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	created := make([]models.Shipment, 0, len(batch))
+	for _, model := range batch {
+		created = append(created, ShipmentModelToDomain(model))
+	}
+
+	return created, nil
+}
+
+// get a single shipment by it's ID (a cancelled one too)
 func (r *shipmentRepository) GetShipmentByID(shipmentID uint) (models.Shipment, error) {
-	var shipment models.Shipment
+	var model ShipmentModel
+
+	res := r.db.
+		Preload("PriceComponents", orderByPosition).
+		Preload("Pieces", orderByPosition).
+		Preload("ReturnOf", withCancelled).
+		Preload("Returns", withCancelled, orderByID).
+		Scopes(withCancelled, r.scope).
+		First(&model, shipmentID)
+	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		return models.Shipment{}, ErrorShipmentNotFound
+	}
+	if res.Error != nil {
+		return models.Shipment{}, res.Error
+	}
+
+	return ShipmentModelToDomain(model), nil
+}
+
+// get a single shipment by it's tracking number (a cancelled one too)
+func (r *shipmentRepository) GetShipmentByTrackingNumber(number string) (models.Shipment, error) {
+	var model ShipmentModel
+
+	res := r.db.
+		Preload("PriceComponents", orderByPosition).
+		Preload("Pieces", orderByPosition).
+		Preload("ReturnOf", withCancelled).
+		Preload("Returns", withCancelled, orderByID).
+		Scopes(withCancelled, r.scope).
+		Where("tracking_number = ?", number).
+		First(&model)
+	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		return models.Shipment{}, ErrorShipmentNotFound
+	}
+	if res.Error != nil {
+		return models.Shipment{}, res.Error
+	}
+
+	return ShipmentModelToDomain(model), nil
+}
+
+// replace data of the shipment if it still has the expected status
+func (r *shipmentRepository) UpdateShipment(shipment models.Shipment, status models.ShipmentStatus) (models.Shipment, error) {
+	model := ShipmentModelFromDomain(shipment)
+	model.ID = shipment.Id
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := updateShipmentRow(tx.Scopes(r.scope), model, status)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrorStatusConflict
+		}
+
+		// price breakdown and pieces are replaced
+		err := tx.Unscoped().Where("shipment_model_id = ?", model.ID).Delete(&PriceComponentModel{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Where("shipment_model_id = ?", model.ID).Delete(&ShipmentPieceModel{}).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range model.PriceComponents {
+			model.PriceComponents[i].ShipmentModelID = model.ID
+		}
+		if len(model.PriceComponents) > 0 {
+			if err := tx.Create(&model.PriceComponents).Error; err != nil {
+				return err
+			}
+		}
+		for i := range model.Pieces {
+			model.Pieces[i].ShipmentModelID = model.ID
+		}
+		if len(model.Pieces) > 0 {
+			if err := tx.Create(&model.Pieces).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	return r.GetShipmentByID(shipment.Id)
+}
 
-	res := r.db.First(&shipment, shipmentID)
+// update all columns of the shipment row except the identity, the status, the cancellation, the return link, the owner and the tenant
+func updateShipmentRow(db *gorm.DB, model ShipmentModel, status models.ShipmentStatus) *gorm.DB {
+	return db.Model(&model).
+		Where("status = ?", string(status)).
+		Select("*").
+		Omit("id", "created_at", "deleted_at", "tracking_number", "status",
+			"cancellation_fee_amount", "cancellation_fee_currency", "refund_amount", "refund_currency", "return_of_id", "owner", "tenant_id",
+			clause.Associations).
+		Updates(&model)
+}
+
+// change status of the shipment if it still has the expected one
+func (r *shipmentRepository) UpdateShipmentStatus(shipmentID uint, from, to models.ShipmentStatus) error {
+	res := r.db.Model(&ShipmentModel{}).
+		Scopes(r.scope).
+		Where("id = ? AND status = ?", shipmentID, string(from)).
+		Update("status", string(to))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrorStatusConflict
+	}
+
+	return nil
+}
+
+// cancel the shipment if it still has the expected status (it is soft-deleted with the refund)
+func (r *shipmentRepository) CancelShipment(shipmentID uint, from models.ShipmentStatus, cancellation models.Cancellation) error {
+	res := cancelShipmentRow(r.db.Scopes(r.scope), shipmentID, from, cancellation)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrorStatusConflict
+	}
+
+	return nil
+}
+
+func cancelShipmentRow(db *gorm.DB, shipmentID uint, from models.ShipmentStatus, cancellation models.Cancellation) *gorm.DB {
+	return db.Model(&ShipmentModel{}).
+		Where("id = ? AND status = ?", shipmentID, string(from)).
+		Updates(map[string]interface{}{
+			"status":                    string(models.StatusCancelled),
+			"cancellation_fee_amount":   cancellation.Fee.Amount,
+			"cancellation_fee_currency": cancellation.Fee.Currency,
+			"refund_amount":             cancellation.Refund.Amount,
+			"refund_currency":           cancellation.Refund.Currency,
+			"deleted_at":                cancellation.CancelledAt,
+		})
+}
+
+// cancelled shipments are soft-deleted, but they are still read by ID, tracking number and export with their refund
+func withCancelled(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("deleted_at IS NULL OR status = ?", string(models.StatusCancelled))
+}
+
+// children of the shipment are loaded in the original order
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+// shipments are loaded in the order they were added
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+// copy prices of the shipments added before the money type (float major units without currency)
+// to the amount and currency columns, and drop the old column
+func MigrateShipmentPrice(db *gorm.DB, currency string) error {
+	if !db.Migrator().HasColumn(&ShipmentModel{}, "price") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := migrateShipmentPriceRows(tx, currency).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&ShipmentModel{}, "price")
+	})
+}
+
+func migrateShipmentPriceRows(db *gorm.DB, currency string) *gorm.DB {
+	// minor units in a major unit of the currency
+	scale := int64(1)
+	for i := 0; i < money.Digits(currency); i++ {
+		scale *= 10
+	}
 
-	return shipment, res.Error
+	// numeric rounding is half away from zero like in the money type
+	return db.Exec(`UPDATE shipment_models SET price_amount = ROUND(CAST(price AS numeric) * ?), price_currency = ? WHERE price IS NOT NULL AND (price_currency IS NULL OR price_currency = '')`,
+		scale, currency)
 }
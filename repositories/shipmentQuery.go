@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"gorm.io/gorm"
+)
+
+var ErrorInvalidCursor error = errors.New("invalid cursor")
+
+// columns which shipments may be sorted by (id is the tie-breaker)
+var shipmentSortColumns = map[models.ShipmentSortField]string{
+	models.SortByID:        "id",
+	models.SortByCreatedAt: "created_at",
+	models.SortByWeight:    "weight",
+	models.SortByPrice:     "price_amount",
+}
+
+// position of the last shipment of the page (in the sort order it was made for)
+type shipmentCursor struct {
+	SortBy models.ShipmentSortField `json:"s"`
+	Desc   bool                     `json:"d,omitempty"`
+	Value  json.RawMessage          `json:"v,omitempty"`
+	ID     uint                     `json:"id"`
+}
+
+func encodeShipmentCursor(query models.ShipmentQuery, last ShipmentModel) (string, error) {
+	var value interface{}
+	switch query.SortBy {
+	case models.SortByCreatedAt:
+		value = last.CreatedAt
+	case models.SortByWeight:
+		value = last.Weight
+	case models.SortByPrice:
+		value = last.Price.Amount
+	}
+
+	cursor := shipmentCursor{SortBy: query.SortBy, Desc: query.Desc, ID: last.ID}
+	if value != nil {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		cursor.Value = raw
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decode cursor and the value of the sort column
+func decodeShipmentCursor(query models.ShipmentQuery) (shipmentCursor, interface{}, error) {
+	var cursor shipmentCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+	if err != nil {
+		return shipmentCursor{}, nil, ErrorInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return shipmentCursor{}, nil, ErrorInvalidCursor
+	}
+
+	// cursor is valid only for the sort order of the previous page
+	if cursor.SortBy != query.SortBy || cursor.Desc != query.Desc {
+		return shipmentCursor{}, nil, ErrorInvalidCursor
+	}
+
+	var value interface{}
+	switch cursor.SortBy {
+	case models.SortByID:
+		return cursor, nil, nil
+	case models.SortByCreatedAt:
+		var createdAt time.Time
+		err = json.Unmarshal(cursor.Value, &createdAt)
+		value = createdAt
+	case models.SortByWeight:
+		var weight float64
+		err = json.Unmarshal(cursor.Value, &weight)
+		value = weight
+	case models.SortByPrice:
+		var amount int64
+		err = json.Unmarshal(cursor.Value, &amount)
+		value = amount
+	default:
+		return shipmentCursor{}, nil, ErrorInvalidCursor
+	}
+	if err != nil {
+		return shipmentCursor{}, nil, ErrorInvalidCursor
+	}
+
+	return cursor, value, nil
+}
+
+// filter shipments (zero values of the filter are not applied)
+func shipmentFilterScope(filter models.ShipmentFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.FromCountryCode != "" {
+			db = db.Where("from_country_code = ?", filter.FromCountryCode)
+		}
+		if filter.ToCountryCode != "" {
+			db = db.Where("to_country_code = ?", filter.ToCountryCode)
+		}
+		if filter.MinWeight != 0 {
+			db = db.Where("weight >= ?", filter.MinWeight)
+		}
+		if filter.MaxWeight != 0 {
+			db = db.Where("weight <= ?", filter.MaxWeight)
+		}
+		if filter.MinPrice != nil {
+			db = db.Where("price_currency = ? AND price_amount >= ?", filter.MinPrice.Currency, filter.MinPrice.Amount)
+		}
+		if filter.MaxPrice != nil {
+			db = db.Where("price_currency = ? AND price_amount <= ?", filter.MaxPrice.Currency, filter.MaxPrice.Amount)
+		}
+		if !filter.CreatedFrom.IsZero() {
+			db = db.Where("created_at >= ?", filter.CreatedFrom)
+		}
+		if !filter.CreatedTo.IsZero() {
+			db = db.Where("created_at < ?", filter.CreatedTo)
+		}
+		if filter.Email != "" {
+			email := strings.ToLower(filter.Email)
+			db = db.Where("LOWER(from_email) = ? OR LOWER(to_email) = ?", email, email)
+		}
+		return db
+	}
+}
+
+// sort shipments and skip the ones before the cursor
+func shipmentPageScope(query models.ShipmentQuery) (func(db *gorm.DB) *gorm.DB, error) {
+	column, ok := shipmentSortColumns[query.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort field %q", query.SortBy)
+	}
+	if query.Limit <= 0 {
+		return nil, fmt.Errorf("invalid page limit %d", query.Limit)
+	}
+
+	direction, operator := "ASC", ">"
+	if query.Desc {
+		direction, operator = "DESC", "<"
+	}
+
+	var (
+		cursor shipmentCursor
+		value  interface{}
+	)
+	if query.Cursor != "" {
+		var err error
+		cursor, value, err = decodeShipmentCursor(query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		if query.Cursor != "" {
+			if query.SortBy == models.SortByID {
+				db = db.Where(fmt.Sprintf("id %s ?", operator), cursor.ID)
+			} else {
+				db = db.Where(fmt.Sprintf("%s %s ? OR (%s = ? AND id %s ?)", column, operator, column, operator), value, value, cursor.ID)
+			}
+		}
+
+		if query.SortBy != models.SortByID {
+			db = db.Order(fmt.Sprintf("%s %s", column, direction))
+		}
+		// one more shipment shows that there is a next page
+		return db.Order(fmt.Sprintf("id %s", direction)).Limit(query.Limit + 1)
+	}, nil
+}
+
+// get a page of the filtered shipments with the total number of them
+func (r *shipmentRepository) ListShipments(query models.ShipmentQuery) (models.ShipmentPage, error) {
+	var total int64
+	res := r.db.Model(&ShipmentModel{}).Scopes(r.scope, shipmentFilterScope(query.Filter)).Count(&total)
+	if res.Error != nil {
+		return models.ShipmentPage{}, res.Error
+	}
+
+	shipmentModels, nextCursor, err := r.findShipmentPage(query, false)
+	if err != nil {
+		return models.ShipmentPage{}, err
+	}
+
+	page := models.ShipmentPage{Shipments: make([]models.Shipment, 0, len(shipmentModels)), NextCursor: nextCursor, Total: total}
+	for _, model := range shipmentModels {
+		page.Shipments = append(page.Shipments, ShipmentModelToDomain(model))
+	}
+
+	return page, nil
+}
+
+// call fn for every shipment which matches the query, cancelled ones too (shipments are read page by page with the cursor)
+func (r *shipmentRepository) EachShipment(query models.ShipmentQuery, fn func(shipment models.Shipment) error) error {
+	for {
+		shipmentModels, nextCursor, err := r.findShipmentPage(query, true)
+		if err != nil {
+			return err
+		}
+
+		for _, model := range shipmentModels {
+			if err := fn(ShipmentModelToDomain(model)); err != nil {
+				return err
+			}
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+		query.Cursor = nextCursor
+	}
+}
+
+// shipments of the page and the cursor of the next page (empty for the last page)
+func (r *shipmentRepository) findShipmentPage(query models.ShipmentQuery, cancelled bool) ([]ShipmentModel, string, error) {
+	pageScope, err := shipmentPageScope(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	db := r.db
+	if cancelled {
+		db = db.Scopes(withCancelled)
+	}
+
+	var shipmentModels []ShipmentModel
+	res := db.
+		Preload("Pieces", orderByPosition).
+		Scopes(r.scope, shipmentFilterScope(query.Filter), pageScope).
+		Find(&shipmentModels)
+	if res.Error != nil {
+		return nil, "", res.Error
+	}
+
+	// one more shipment is read to know if there is the next page
+	nextCursor := ""
+	if len(shipmentModels) > query.Limit {
+		shipmentModels = shipmentModels[:query.Limit]
+		nextCursor, err = encodeShipmentCursor(query, shipmentModels[query.Limit-1])
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	return shipmentModels, nextCursor, nil
+}
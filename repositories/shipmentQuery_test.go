@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// database which only builds SQL (no connection is made)
+func dryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(""), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	return db
+}
+
+func TestShipmentQuery_SQL(t *testing.T) {
+	minPrice := money.New(100000, "EUR")
+
+	testCases := []struct {
+		name        string
+		query       models.ShipmentQuery
+		expectedSQL string
+	}{
+		{
+			name:        "first page without filters",
+			query:       models.ShipmentQuery{SortBy: models.SortByID, Limit: 50},
+			expectedSQL: `SELECT * FROM "shipment_models" WHERE "shipment_models"."deleted_at" IS NULL ORDER BY id ASC LIMIT 51`,
+		},
+		{
+			name: "filters",
+			query: models.ShipmentQuery{
+				Filter: models.ShipmentFilter{
+					FromCountryCode: "UA",
+					ToCountryCode:   "CA",
+					MinWeight:       10,
+					MaxWeight:       20.5,
+					MinPrice:        &minPrice,
+					CreatedFrom:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+					CreatedTo:       time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+					Email:           "TestTo@g.c",
+				},
+				SortBy: models.SortByCreatedAt,
+				Desc:   true,
+				Limit:  10,
+			},
+			expectedSQL: `SELECT * FROM "shipment_models" WHERE from_country_code = 'UA' AND to_country_code = 'CA' AND weight >= 10.000000 AND weight <= 20.500000 AND (price_currency = 'EUR' AND price_amount >= 100000) AND created_at >= '2022-01-01 00:00:00' AND created_at < '2022-02-01 00:00:00' AND (LOWER(from_email) = 'testto@g.c' OR LOWER(to_email) = 'testto@g.c') AND "shipment_models"."deleted_at" IS NULL ORDER BY created_at DESC,id DESC LIMIT 11`,
+		},
+		{
+			name: "next page by id",
+			query: models.ShipmentQuery{
+				SortBy: models.SortByID,
+				Cursor: "eyJzIjoiaWQiLCJpZCI6NX0",
+				Limit:  2,
+			},
+			expectedSQL: `SELECT * FROM "shipment_models" WHERE id > 5 AND "shipment_models"."deleted_at" IS NULL ORDER BY id ASC LIMIT 3`,
+		},
+		{
+			name: "next page by weight (descending)",
+			query: models.ShipmentQuery{
+				SortBy: models.SortByWeight,
+				Desc:   true,
+				Cursor: "eyJzIjoid2VpZ2h0IiwiZCI6dHJ1ZSwidiI6MTIuNSwiaWQiOjV9",
+				Limit:  2,
+			},
+			expectedSQL: `SELECT * FROM "shipment_models" WHERE (weight < 12.500000 OR (weight = 12.500000 AND id < 5)) AND "shipment_models"."deleted_at" IS NULL ORDER BY weight DESC,id DESC LIMIT 3`,
+		},
+	}
+
+	db := dryRunDB(t)
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			pageScope, err := shipmentPageScope(tC.query)
+			require.NoError(t, err)
+
+			actual := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var shipments []ShipmentModel
+				return tx.Scopes(shipmentFilterScope(tC.query.Filter), pageScope).Find(&shipments)
+			})
+			require.Equal(t, tC.expectedSQL, actual)
+		})
+	}
+}
+
+func TestShipmentCursor(t *testing.T) {
+	last := ShipmentModel{
+		Model:  gorm.Model{ID: 5, CreatedAt: time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC)},
+		Weight: 12.5,
+		Price:  money.New(100050, "EUR"),
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		testCases := []struct {
+			sortBy        models.ShipmentSortField
+			expectedValue interface{}
+		}{
+			{models.SortByID, nil},
+			{models.SortByCreatedAt, last.CreatedAt},
+			{models.SortByWeight, 12.5},
+			{models.SortByPrice, int64(100050)},
+		}
+
+		for _, tC := range testCases {
+			query := models.ShipmentQuery{SortBy: tC.sortBy, Desc: true, Limit: 1}
+
+			cursor, err := encodeShipmentCursor(query, last)
+			require.NoError(t, err)
+
+			query.Cursor = cursor
+			decoded, value, err := decodeShipmentCursor(query)
+			require.NoError(t, err)
+			require.Equal(t, uint(5), decoded.ID)
+			require.Equal(t, tC.expectedValue, value)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		cursor, err := encodeShipmentCursor(models.ShipmentQuery{SortBy: models.SortByWeight, Limit: 1}, last)
+		require.NoError(t, err)
+
+		testCases := []struct {
+			name  string
+			query models.ShipmentQuery
+		}{
+			{"not base64", models.ShipmentQuery{SortBy: models.SortByWeight, Cursor: "%%%"}},
+			{"not json", models.ShipmentQuery{SortBy: models.SortByWeight, Cursor: "bm90IGpzb24"}},
+			{"another sort field", models.ShipmentQuery{SortBy: models.SortByPrice, Cursor: cursor}},
+			{"another direction", models.ShipmentQuery{SortBy: models.SortByWeight, Desc: true, Cursor: cursor}},
+		}
+
+		for _, tC := range testCases {
+			t.Run(tC.name, func(t *testing.T) {
+				_, _, err := decodeShipmentCursor(tC.query)
+				require.Equal(t, ErrorInvalidCursor, err)
+			})
+		}
+	})
+}
+
+func TestEachShipment(t *testing.T) {
+	repo := InitShipmentRepository(dryRunDB(t))
+
+	// nothing is read by the dry run
+	err := repo.EachShipment(models.ShipmentQuery{SortBy: models.SortByID, Limit: 500}, func(shipment models.Shipment) error {
+		return errors.New("unexpected shipment")
+	})
+	require.NoError(t, err)
+
+	err = repo.EachShipment(models.ShipmentQuery{SortBy: models.SortByWeight, Cursor: "%%%", Limit: 500}, func(shipment models.Shipment) error {
+		return nil
+	})
+	require.Equal(t, ErrorInvalidCursor, err)
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/Taras-Rm/shipment/models"
+	"gorm.io/gorm"
+)
+
+// searchable fields of the shipment with their weight in the ranking
+var searchFields = []struct {
+	column string
+	name   string
+	weight string
+}{
+	{"from_name", "fromName", "A"},
+	{"to_name", "toName", "A"},
+	{"from_email", "fromEmail", "B"},
+	{"to_email", "toEmail", "B"},
+	{"from_address", "fromAddress", "C"},
+	{"to_address", "toAddress", "C"},
+}
+
+// text search configuration (removes stop words like "the", "to", "in")
+const searchConfig = "english"
+
+// document of the shipment (the same expression is used by the index)
+var searchDocument = func() string {
+	parts := make([]string, 0, len(searchFields))
+	for _, field := range searchFields {
+		parts = append(parts, fmt.Sprintf("setweight(to_tsvector('%s', coalesce(%s, '')), '%s')", searchConfig, field.column, field.weight))
+	}
+	return "(" + strings.Join(parts, " || ") + ")"
+}()
+
+// create GIN index for the full-text search of shipments
+func MigrateShipmentSearch(db *gorm.DB) error {
+	return db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_shipment_search ON shipment_models USING GIN (%s)", searchDocument)).Error
+}
+
+//go:generate mockgen -source=shipmentSearch.go -destination=mocks/shipmentSearch.go
+type ShipmentSearchRepository interface {
+	SearchShipments(query models.SearchQuery) ([]models.SearchResult, error)
+	WithOwner(owner string) ShipmentSearchRepository
+	WithTenant(tenantID string) ShipmentSearchRepository
+}
+
+type shipmentSearchRepository struct {
+	db *gorm.DB
+	// client which created the shipments (empty for all shipments)
+	owner string
+	// tenant of the shipments (empty for all tenants)
+	tenantID string
+}
+
+func InitShipmentSearchRepository(db *gorm.DB) ShipmentSearchRepository {
+	return &shipmentSearchRepository{db: db}
+}
+
+// search over the shipments created by the owner
+func (r *shipmentSearchRepository) WithOwner(owner string) ShipmentSearchRepository {
+	scoped := *r
+	scoped.owner = owner
+	return &scoped
+}
+
+// search over the shipments of the tenant
+func (r *shipmentSearchRepository) WithTenant(tenantID string) ShipmentSearchRepository {
+	scoped := *r
+	scoped.tenantID = tenantID
+	return &scoped
+}
+
+// shipment row with rank and highlighted fields
+type shipmentSearchRow struct {
+	ShipmentModel
+	Rank                 float64
+	FromNameHighlight    string
+	ToNameHighlight      string
+	FromEmailHighlight   string
+	ToEmailHighlight     string
+	FromAddressHighlight string
+	ToAddressHighlight   string
+}
+
+func (row shipmentSearchRow) highlights() map[string]string {
+	values := map[string]string{
+		"fromName":    row.FromNameHighlight,
+		"toName":      row.ToNameHighlight,
+		"fromEmail":   row.FromEmailHighlight,
+		"toEmail":     row.ToEmailHighlight,
+		"fromAddress": row.FromAddressHighlight,
+		"toAddress":   row.ToAddressHighlight,
+	}
+
+	// only the fields with matches are returned
+	highlights := map[string]string{}
+	for name, value := range values {
+		if strings.Contains(value, headlineStart) {
+			highlights[name] = headlineReplacer.Replace(html.EscapeString(value))
+		}
+	}
+	return highlights
+}
+
+// matches are marked by control characters in the database (they are removed from the text before),
+// so the text is HTML-escaped before the marks become highlight tags
+const (
+	headlineStart = "\x02"
+	headlineStop  = "\x03"
+)
+
+var headlineReplacer = strings.NewReplacer(headlineStart, models.HighlightStart, headlineStop, models.HighlightStop)
+
+// search shipments by any of the terms, the best matches first
+func (r *shipmentSearchRepository) SearchShipments(query models.SearchQuery) ([]models.SearchResult, error) {
+	if len(query.Terms) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	tsQuery, args := searchTsQuery(query.Terms)
+
+	selects := []string{"shipment_models.*", fmt.Sprintf("ts_rank(%s, %s) AS rank", searchDocument, tsQuery)}
+	selectArgs := append([]interface{}{}, args...)
+	for _, field := range searchFields {
+		selects = append(selects, fmt.Sprintf(
+			"ts_headline('%s', translate(coalesce(%s, ''), chr(2) || chr(3), ''), %s, ?) AS %s_highlight",
+			searchConfig, field.column, tsQuery, field.column,
+		))
+		selectArgs = append(selectArgs, args...)
+		selectArgs = append(selectArgs, headlineOptions)
+	}
+
+	db := r.db.Model(&ShipmentModel{})
+	if r.tenantID != "" {
+		db = db.Where("tenant_id = ?", r.tenantID)
+	}
+	if r.owner != "" {
+		db = db.Where("owner = ?", r.owner)
+	}
+
+	var rows []shipmentSearchRow
+	res := db.
+		Select(strings.Join(selects, ", "), selectArgs...).
+		Where(fmt.Sprintf("%s @@ %s", searchDocument, tsQuery), args...).
+		Order("rank DESC, id").
+		Limit(query.Limit).
+		Find(&rows)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	results := make([]models.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, models.SearchResult{
+			Shipment:   ShipmentModelToDomain(row.ShipmentModel),
+			Rank:       row.Rank,
+			Highlights: row.highlights(),
+		})
+	}
+	return results, nil
+}
+
+var headlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", headlineStart, headlineStop)
+
+// query which matches any of the terms (stop words become empty queries)
+func searchTsQuery(terms []string) (string, []interface{}) {
+	parts := make([]string, 0, len(terms))
+	args := make([]interface{}, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, fmt.Sprintf("plainto_tsquery('%s', ?)", searchConfig))
+		args = append(args, term)
+	}
+	return "(" + strings.Join(parts, " || ") + ")", args
+}
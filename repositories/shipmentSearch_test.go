@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestShipmentSearch_SQL(t *testing.T) {
+	db := dryRunDB(t)
+
+	tsQuery, args := searchTsQuery([]string{"iryna", "toronto"})
+	require.Equal(t, "(plainto_tsquery('english', ?) || plainto_tsquery('english', ?))", tsQuery)
+	require.Equal(t, []interface{}{"iryna", "toronto"}, args)
+
+	actual := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var shipments []ShipmentModel
+		return tx.Where(searchDocument+" @@ "+tsQuery, args...).Find(&shipments)
+	})
+	require.Equal(t, `SELECT * FROM "shipment_models" WHERE ((setweight(to_tsvector('english', coalesce(from_name, '')), 'A') || setweight(to_tsvector('english', coalesce(to_name, '')), 'A') || setweight(to_tsvector('english', coalesce(from_email, '')), 'B') || setweight(to_tsvector('english', coalesce(to_email, '')), 'B') || setweight(to_tsvector('english', coalesce(from_address, '')), 'C') || setweight(to_tsvector('english', coalesce(to_address, '')), 'C')) @@ (plainto_tsquery('english', 'iryna') || plainto_tsquery('english', 'toronto'))) AND "shipment_models"."deleted_at" IS NULL`, actual)
+}
+
+func TestShipmentSearchRow_Highlights(t *testing.T) {
+	row := shipmentSearchRow{
+		FromNameHighlight:  "Mark",
+		ToNameHighlight:    "\x02Iryna\x03",
+		ToAddressHighlight: "\x02Toronto\x03, 34",
+		// user-entered text is not HTML
+		FromAddressHighlight: "<script>alert(1)</script> \x02Lviv\x03 & <mark>45</mark>",
+	}
+
+	require.Equal(t, map[string]string{
+		"toName":      "<mark>Iryna</mark>",
+		"toAddress":   "<mark>Toronto</mark>, 34",
+		"fromAddress": "&lt;script&gt;alert(1)&lt;/script&gt; <mark>Lviv</mark> &amp; &lt;mark&gt;45&lt;/mark&gt;",
+	}, row.highlights())
+}
+
+func TestMemoryShipmentSearch(t *testing.T) {
+	shipments := []models.Shipment{
+		{Id: 1, FromName: "Mark", FromEmail: "testFrom@g.c", FromAddress: "Lviv, 45", ToName: "Iryna", ToEmail: "testTo@g.c", ToAddress: "Toronto, 34"},
+		{Id: 2, FromName: "Iryna", FromEmail: "iryna@g.c", FromAddress: "Toronto, 12", ToName: "Mark", ToEmail: "mark@g.c", ToAddress: "Lviv, 45"},
+		{Id: 3, FromName: "Tom", FromEmail: "tom@g.c", FromAddress: "Lutsk, 34", ToName: "Viktor", ToEmail: "viktor@g.c", ToAddress: "Toronto, 2"},
+		{Id: 4, FromName: "Tom", FromEmail: "tom@g.c", FromAddress: "Lutsk, 34", ToName: "Alex", ToEmail: "alex@g.c", ToAddress: "London, 32"},
+		{Id: 5, FromName: "Tom", FromEmail: "tom@g.c", FromAddress: "Lutsk, 34", ToName: "Olena", ToEmail: "olena@g.c", ToAddress: "<img src=x onerror=alert(1)> Odesa & Co"},
+	}
+	search := InitMemoryShipmentSearch(shipments)
+
+	testCases := []struct {
+		name               string
+		query              models.SearchQuery
+		expectedIds        []uint
+		expectedHighlights map[string]string
+	}{
+		{
+			name:        "free text with stop words",
+			query:       models.SearchQuery{Terms: []string{"the", "parcel", "to", "iryna", "in", "toronto"}, Limit: 10},
+			expectedIds: []uint{1, 2, 3},
+			expectedHighlights: map[string]string{
+				"toName":    "<mark>Iryna</mark>",
+				"toAddress": "<mark>Toronto</mark>, 34",
+			},
+		},
+		{
+			name:        "email",
+			query:       models.SearchQuery{Terms: []string{"testto@g.c"}, Limit: 10},
+			expectedIds: []uint{1},
+			expectedHighlights: map[string]string{
+				"toEmail": "<mark>testTo@g.c</mark>",
+			},
+		},
+		{
+			name:        "limit",
+			query:       models.SearchQuery{Terms: []string{"toronto"}, Limit: 2},
+			expectedIds: []uint{1, 2},
+			expectedHighlights: map[string]string{
+				"toAddress": "<mark>Toronto</mark>, 34",
+			},
+		},
+		{
+			name:        "HTML in the text",
+			query:       models.SearchQuery{Terms: []string{"odesa"}, Limit: 10},
+			expectedIds: []uint{5},
+			expectedHighlights: map[string]string{
+				"toAddress": "&lt;img src=x onerror=alert(1)&gt; <mark>Odesa</mark> &amp; Co",
+			},
+		},
+		{
+			name:        "nothing found",
+			query:       models.SearchQuery{Terms: []string{"kyiv"}, Limit: 10},
+			expectedIds: []uint{},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			results, err := search.SearchShipments(tC.query)
+			require.NoError(t, err)
+
+			ids := []uint{}
+			for _, result := range results {
+				ids = append(ids, result.Shipment.Id)
+			}
+			require.Equal(t, tC.expectedIds, ids)
+
+			if len(results) > 0 {
+				require.Equal(t, tC.expectedHighlights, results[0].Highlights)
+			}
+		})
+	}
+}
+
+func TestMemoryShipmentSearch_WithOwner(t *testing.T) {
+	search := InitMemoryShipmentSearch([]models.Shipment{
+		{Id: 1, ToName: "Iryna", Owner: "acme", TenantID: "brand-a"},
+		{Id: 2, ToName: "Iryna", Owner: "globex", TenantID: "brand-a"},
+		{Id: 3, ToName: "Iryna", Owner: "globex", TenantID: "brand-b"},
+	})
+
+	results, err := search.WithTenant("brand-a").WithOwner("globex").SearchShipments(models.SearchQuery{Terms: []string{"iryna"}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, uint(2), results[0].Shipment.Id)
+
+	// shipments of all owners of the tenant
+	results, err = search.WithTenant("brand-b").SearchShipments(models.SearchQuery{Terms: []string{"iryna"}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, uint(3), results[0].Shipment.Id)
+
+	// all shipments without the owner
+	results, err = search.SearchShipments(models.SearchQuery{Terms: []string{"iryna"}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestShipmentModel_DomainConversion(t *testing.T) {
+	shipment := models.Shipment{
+		TrackingNumber:  "SH169090604UA",
+		FromName:        "Mark",
+		FromEmail:       "testFrom@g.c",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "CA",
+		Weight:          234.4,
+		Price:           money.New(500000, "EUR"),
+		Status:          models.StatusInTransit,
+		PriceBreakdown: &models.PriceBreakdown{
+			RateCardVersion: "default",
+			Lane:            models.Lane{Type: models.LaneIntercontinental, Origin: "europe", Destination: "world", Factor: 2.5},
+			BillableWeight:  234.4,
+			Components: []models.PriceComponent{
+				{Type: models.PriceComponentBase, Name: "weight class", Amount: money.New(200000, "EUR")},
+				{Type: models.PriceComponentLane, Name: "intercontinental", Factor: 2.5, Amount: money.New(300000, "EUR")},
+			},
+			Total: money.New(500000, "EUR"),
+		},
+		Pieces: []models.Piece{
+			{Weight: 234.4, BillableWeight: 234.4, Price: money.New(500000, "EUR")},
+		},
+	}
+
+	t.Run("with price breakdown", func(t *testing.T) {
+		model := ShipmentModelFromDomain(shipment)
+		require.Len(t, model.PriceComponents, 2)
+		require.Equal(t, 1, model.PriceComponents[1].Position)
+		require.Len(t, model.Pieces, 1)
+
+		actual := ShipmentModelToDomain(model)
+		require.Equal(t, shipment, actual)
+	})
+
+	t.Run("with fx rate", func(t *testing.T) {
+		rate := models.FxRate{From: "EUR", To: "SEK", Rate: 10.4567, EffectiveFrom: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+		converted := shipment
+		converted.Price = money.New(5228350, "SEK")
+		converted.PriceBreakdown = &models.PriceBreakdown{
+			RateCardVersion: "default",
+			Lane:            shipment.PriceBreakdown.Lane,
+			BillableWeight:  234.4,
+			Components: []models.PriceComponent{
+				{Type: models.PriceComponentBase, Name: "weight class", Amount: money.New(2091340, "SEK")},
+				{Type: models.PriceComponentLane, Name: "intercontinental", Factor: 2.5, Amount: money.New(3137010, "SEK")},
+			},
+			Total:  money.New(5228350, "SEK"),
+			FxRate: &rate,
+		}
+		converted.FxRate = &rate
+		converted.Pieces = []models.Piece{
+			{Weight: 234.4, BillableWeight: 234.4, Price: money.New(5228350, "SEK")},
+		}
+
+		actual := ShipmentModelToDomain(ShipmentModelFromDomain(converted))
+		require.Equal(t, converted, actual)
+	})
+
+	t.Run("without price components", func(t *testing.T) {
+		model := ShipmentModelFromDomain(shipment)
+		model.PriceComponents = nil
+
+		expected := shipment
+		expected.PriceBreakdown = nil
+
+		actual := ShipmentModelToDomain(model)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("linked returns", func(t *testing.T) {
+		returned := shipment
+		returned.ReturnOf = &models.ShipmentLink{Id: 1}
+
+		model := ShipmentModelFromDomain(returned)
+		require.Equal(t, uint(1), *model.ReturnOfID)
+		require.Equal(t, returned, ShipmentModelToDomain(model))
+
+		// links are complete when the related shipments are loaded
+		model.ReturnOf = &ShipmentModel{Model: gorm.Model{ID: 1}, TrackingNumber: "SH123456785UA", Status: "delivered"}
+		model.Returns = []ShipmentModel{{Model: gorm.Model{ID: 5}, TrackingNumber: "SH876543217UA", Status: "created"}}
+
+		actual := ShipmentModelToDomain(model)
+		require.Equal(t, &models.ShipmentLink{Id: 1, TrackingNumber: "SH123456785UA", Status: models.StatusDelivered}, actual.ReturnOf)
+		require.Equal(t, []models.ShipmentLink{{Id: 5, TrackingNumber: "SH876543217UA", Status: models.StatusCreated}}, actual.Returns)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		cancelled := shipment
+		cancelled.Status = models.StatusCancelled
+		cancelled.Cancellation = &models.Cancellation{
+			CancelledAt: time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+			Fee:         money.New(100000, "EUR"),
+			Refund:      money.New(400000, "EUR"),
+		}
+
+		model := ShipmentModelFromDomain(cancelled)
+		require.True(t, model.DeletedAt.Valid)
+
+		actual := ShipmentModelToDomain(model)
+		require.Equal(t, cancelled, actual)
+	})
+}
+
+func TestUpdateShipmentRow_SQL(t *testing.T) {
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	// updates run in a transaction unless it is skipped
+	db := dryRunDB(t).Session(&gorm.Session{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		NowFunc:                func() time.Time { return now },
+	})
+
+	model := ShipmentModelFromDomain(models.Shipment{
+		Id:              3,
+		TrackingNumber:  "SH169090604UA",
+		FromName:        "Mark",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToCountryCode:   "CA",
+		Weight:          5,
+		Price:           money.New(9999, "EUR"),
+		Status:          models.StatusLabelPrinted,
+		Pieces:          []models.Piece{{Weight: 5, Price: money.New(9999, "EUR")}},
+	})
+	model.ID = 3
+
+	stmt := updateShipmentRow(db, model, models.StatusCreated).Statement
+	actual := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+
+	require.Equal(t, `UPDATE "shipment_models" SET "updated_at"='2022-03-01 12:00:00',"from_name"='Mark',"from_email"='',"from_address"='',"from_country_code"='UA',"to_name"='Iryna',"to_email"='',"to_address"='',"to_country_code"='CA',"weight"=5.000000,"length"=0.000000,"width"=0.000000,"height"=0.000000,"price_amount"=9999,"price_currency"='EUR',"rate_card_version"='',"volumetric_weight"=0.000000,"billable_weight"=0.000000,"lane_type"='',"lane_origin"='',"lane_destination"='',"lane_factor"=0.000000,"fx_from"='',"fx_rate"=0.000000,"fx_effective_from"='0000-00-00 00:00:00' WHERE status = 'created' AND "id" = 3 AND "shipment_models"."deleted_at" IS NULL`, actual)
+}
+
+func TestCancelShipmentRow_SQL(t *testing.T) {
+	// updates run in a transaction unless it is skipped
+	db := dryRunDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+
+	cancellation := models.Cancellation{
+		CancelledAt: time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+		Fee:         money.New(100000, "EUR"),
+		Refund:      money.New(400000, "EUR"),
+	}
+
+	stmt := cancelShipmentRow(db, 3, models.StatusPickedUp, cancellation).Statement
+	actual := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+
+	require.Contains(t, actual, `"cancellation_fee_amount"=100000,"cancellation_fee_currency"='EUR',"deleted_at"='2022-03-01 12:00:00',"refund_amount"=400000,"refund_currency"='EUR',"status"='cancelled'`)
+	require.Contains(t, actual, `WHERE (id = 3 AND status = 'picked_up') AND "shipment_models"."deleted_at" IS NULL`)
+}
+
+func TestShipmentScope_SQL(t *testing.T) {
+	db := dryRunDB(t)
+
+	testCases := []struct {
+		name        string
+		repo        *shipmentRepository
+		expectedSQL string
+	}{
+		{
+			name:        "all shipments",
+			repo:        &shipmentRepository{db: db},
+			expectedSQL: `SELECT * FROM "shipment_models" WHERE to_country_code = 'CA' AND "shipment_models"."deleted_at" IS NULL`,
+		},
+		{
+			name:        "shipments of the owner",
+			repo:        InitShipmentRepository(db).WithOwner("acme").(*shipmentRepository),
+			expectedSQL: `SELECT * FROM "shipment_models" WHERE owner = 'acme' AND to_country_code = 'CA' AND "shipment_models"."deleted_at" IS NULL`,
+		},
+		{
+			name:        "shipments of the owner of the tenant",
+			repo:        InitShipmentRepository(db).WithTenant("brand-a").WithOwner("acme").(*shipmentRepository),
+			expectedSQL: `SELECT * FROM "shipment_models" WHERE tenant_id = 'brand-a' AND owner = 'acme' AND to_country_code = 'CA' AND "shipment_models"."deleted_at" IS NULL`,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var shipments []ShipmentModel
+				return tx.Scopes(tC.repo.scope, shipmentFilterScope(models.ShipmentFilter{ToCountryCode: "CA"})).Find(&shipments)
+			})
+			require.Equal(t, tC.expectedSQL, actual)
+		})
+	}
+}
+
+func TestWithCancelled_SQL(t *testing.T) {
+	db := dryRunDB(t)
+	repo := InitShipmentRepository(db).WithOwner("acme").(*shipmentRepository)
+
+	actual := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var shipment ShipmentModel
+		return tx.Scopes(withCancelled, repo.scope).First(&shipment, 3)
+	})
+	require.Equal(t, `SELECT * FROM "shipment_models" WHERE "shipment_models"."id" = 3 AND (deleted_at IS NULL OR status = 'cancelled') AND owner = 'acme' ORDER BY "shipment_models"."id" LIMIT 1`, actual)
+}
+
+func TestShipmentRepository_newModel(t *testing.T) {
+	shipment := models.Shipment{TrackingNumber: "SH169090604UA", Owner: "imported"}
+
+	// new shipments belong to the owner of the repository
+	require.Equal(t, "acme", InitShipmentRepository(nil).WithOwner("acme").(*shipmentRepository).newModel(shipment).Owner)
+	require.Equal(t, "imported", InitShipmentRepository(nil).(*shipmentRepository).newModel(shipment).Owner)
+
+	// and to the tenant of the repository
+	model := InitShipmentRepository(nil).WithOwner("acme").WithTenant("brand-a").(*shipmentRepository).newModel(shipment)
+	require.Equal(t, "brand-a", model.TenantID)
+	require.Equal(t, "acme", model.Owner)
+}
+
+func TestMigrateShipmentPriceRows_SQL(t *testing.T) {
+	db := dryRunDB(t).Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+
+	stmt := migrateShipmentPriceRows(db, "EUR").Statement
+	actual := db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+
+	require.Equal(t, `UPDATE shipment_models SET price_amount = ROUND(CAST(price AS numeric) * 100), price_currency = 'EUR' WHERE price IS NOT NULL AND (price_currency IS NULL OR price_currency = '')`, actual)
+}
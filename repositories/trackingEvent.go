@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"gorm.io/gorm"
+)
+
+// tracking event model (scan event of the shipment)
+type TrackingEventModel struct {
+	gorm.Model
+	ShipmentModelID     uint `gorm:"index"`
+	OccurredAt          time.Time
+	Code                string
+	LocationFacility    string
+	LocationCity        string
+	LocationCountryCode string
+	Note                string
+}
+
+func TrackingEventModelToDomain(event TrackingEventModel) models.TrackingEvent {
+	return models.TrackingEvent{
+		Id:         event.ID,
+		OccurredAt: event.OccurredAt,
+		Code:       event.Code,
+		Location: models.EventLocation{
+			Facility:    event.LocationFacility,
+			City:        event.LocationCity,
+			CountryCode: event.LocationCountryCode,
+		},
+		Note: event.Note,
+	}
+}
+
+func TrackingEventModelFromDomain(shipmentID uint, event models.TrackingEvent) TrackingEventModel {
+	return TrackingEventModel{
+		ShipmentModelID:     shipmentID,
+		OccurredAt:          event.OccurredAt,
+		Code:                event.Code,
+		LocationFacility:    event.Location.Facility,
+		LocationCity:        event.Location.City,
+		LocationCountryCode: event.Location.CountryCode,
+		Note:                event.Note,
+	}
+}
+
+//go:generate mockgen -source=trackingEvent.go -destination=mocks/trackingEvent.go
+type TrackingEventRepository interface {
+	GetTrackingEvents(shipmentID uint) ([]models.TrackingEvent, error)
+	CreateTrackingEvent(shipmentID uint, event models.TrackingEvent) (models.TrackingEvent, error)
+}
+
+type trackingEventRepository struct {
+	db *gorm.DB
+}
+
+func InitTrackingEventRepository(db *gorm.DB) TrackingEventRepository {
+	return &trackingEventRepository{db: db}
+}
+
+// get all events of the shipment in chronological order
+func (r *trackingEventRepository) GetTrackingEvents(shipmentID uint) ([]models.TrackingEvent, error) {
+	var eventModels []TrackingEventModel
+	res := r.db.
+		Where("shipment_model_id = ?", shipmentID).
+		Order("occurred_at, id").
+		Find(&eventModels)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	events := make([]models.TrackingEvent, 0, len(eventModels))
+	for _, event := range eventModels {
+		events = append(events, TrackingEventModelToDomain(event))
+	}
+	return events, nil
+}
+
+// append a new event to the shipment
+func (r *trackingEventRepository) CreateTrackingEvent(shipmentID uint, event models.TrackingEvent) (models.TrackingEvent, error) {
+	model := TrackingEventModelFromDomain(shipmentID, event)
+	res := r.db.Create(&model)
+	if res.Error != nil {
+		return models.TrackingEvent{}, res.Error
+	}
+
+	return TrackingEventModelToDomain(model), nil
+}
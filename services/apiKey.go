@@ -0,0 +1,126 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+)
+
+const (
+	// keys look like sk_<prefix>_<secret>
+	apiKeyScheme = "sk"
+	// random bytes of the public prefix and of the secret part of the key
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+	// max length of the client and of the key name
+	maxAPIKeyClientLength = 50
+	maxAPIKeyNameLength   = 100
+)
+
+var (
+	ErrorInvalidAPIKey       error = errors.New("invalid api key")
+	ErrorInvalidAPIKeyClient error = errors.New("invalid api key client")
+	ErrorInvalidAPIKeyName   error = errors.New("invalid api key name")
+	ErrorInvalidAPIKeyTenant error = errors.New("invalid api key tenant")
+)
+
+//go:generate mockgen -source=apiKey.go -destination=mocks/apiKey.go
+type APIKeyService interface {
+	CreateAPIKey(name, client, tenant string) (models.APIKey, string, error)
+	ListAPIKeys() ([]models.APIKey, error)
+	RevokeAPIKey(keyID uint) error
+	Authenticate(key string) (models.APIKey, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepository repositories.APIKeyRepository
+	tenantService    TenantService
+	// source of the random keys (crypto/rand)
+	random io.Reader
+}
+
+func InitAPIKeyService(apiKeyRepo repositories.APIKeyRepository, tenantService TenantService, random io.Reader) APIKeyService {
+	return &apiKeyService{apiKeyRepository: apiKeyRepo, tenantService: tenantService, random: random}
+}
+
+// create a new key of the client in the tenant (the key is returned only here, just its hash is stored)
+func (s *apiKeyService) CreateAPIKey(name, client, tenant string) (models.APIKey, string, error) {
+	if client == "" || len(client) > maxAPIKeyClientLength || strings.TrimSpace(client) != client {
+		return models.APIKey{}, "", ErrorInvalidAPIKeyClient
+	}
+	if len(name) > maxAPIKeyNameLength {
+		return models.APIKey{}, "", ErrorInvalidAPIKeyName
+	}
+	// with tenants every key belongs to an existing one
+	if tenant != "" || s.tenantService.MultiTenant() {
+		if _, err := s.tenantService.GetTenant(tenant); err != nil {
+			return models.APIKey{}, "", ErrorInvalidAPIKeyTenant
+		}
+	}
+
+	random := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	if _, err := io.ReadFull(s.random, random); err != nil {
+		return models.APIKey{}, "", err
+	}
+	prefix := hex.EncodeToString(random[:apiKeyPrefixBytes])
+	key := apiKeyScheme + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(random[apiKeyPrefixBytes:])
+
+	created, err := s.apiKeyRepository.CreateAPIKey(models.APIKey{
+		Name:   name,
+		Client: client,
+		Tenant: tenant,
+		Prefix: prefix,
+		Hash:   hashAPIKey(key),
+	})
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	return created, key, nil
+}
+
+func (s *apiKeyService) ListAPIKeys() ([]models.APIKey, error) {
+	return s.apiKeyRepository.ListAPIKeys()
+}
+
+func (s *apiKeyService) RevokeAPIKey(keyID uint) error {
+	return s.apiKeyRepository.RevokeAPIKey(keyID, time.Now().UTC())
+}
+
+// get the key which is not revoked
+func (s *apiKeyService) Authenticate(key string) (models.APIKey, error) {
+	// the secret part may contain "_" of the base64 URL alphabet
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyScheme {
+		return models.APIKey{}, ErrorInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepository.GetAPIKeyByPrefix(parts[1])
+	if errors.Is(err, repositories.ErrorAPIKeyNotFound) {
+		return models.APIKey{}, ErrorInvalidAPIKey
+	}
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	// hashes are compared in constant time
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(key)), []byte(apiKey.Hash)) != 1 || apiKey.RevokedAt != nil {
+		return models.APIKey{}, ErrorInvalidAPIKey
+	}
+
+	return apiKey, nil
+}
+
+// keys are long random strings, so a fast hash is enough to store them
+func hashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// key generated from the repeated bytes 1, 2, 3, 4 and its hash
+const (
+	testAPIKey     = "sk_010203040102_AwQBAgMEAQIDBAECAwQBAgMEAQIDBAECAwQBAgMEAQI"
+	testAPIKeyHash = "7ebc0cdf962c8d96c6fcc7733c82685813e61797759292261e5b1eaeb61cf825"
+)
+
+func TestService_CreateAPIKey(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockAPIKeyRepository)
+
+	testCases := []struct {
+		name           string
+		keyName        string
+		client         string
+		tenant         string
+		mockBehaviur   mockBehaviur
+		expectedAPIKey models.APIKey
+		expectedKey    string
+		expectedError  error
+	}{
+		{
+			name:    "OK",
+			keyName: "warehouse",
+			client:  "acme",
+			tenant:  "brand-a",
+			mockBehaviur: func(r *mock_repositories.MockAPIKeyRepository) {
+				r.EXPECT().CreateAPIKey(models.APIKey{Name: "warehouse", Client: "acme", Tenant: "brand-a", Prefix: "010203040102", Hash: testAPIKeyHash}).
+					Return(models.APIKey{Id: 1, Name: "warehouse", Client: "acme", Tenant: "brand-a", Prefix: "010203040102", Hash: testAPIKeyHash}, nil)
+			},
+			expectedAPIKey: models.APIKey{Id: 1, Name: "warehouse", Client: "acme", Tenant: "brand-a", Prefix: "010203040102", Hash: testAPIKeyHash},
+			expectedKey:    testAPIKey,
+		},
+		{
+			name:          "no tenant",
+			client:        "acme",
+			mockBehaviur:  func(r *mock_repositories.MockAPIKeyRepository) {},
+			expectedError: ErrorInvalidAPIKeyTenant,
+		},
+		{
+			name:          "unknown tenant",
+			client:        "acme",
+			tenant:        "brand-c",
+			mockBehaviur:  func(r *mock_repositories.MockAPIKeyRepository) {},
+			expectedError: ErrorInvalidAPIKeyTenant,
+		},
+		{
+			name:          "empty client",
+			mockBehaviur:  func(r *mock_repositories.MockAPIKeyRepository) {},
+			expectedError: ErrorInvalidAPIKeyClient,
+		},
+		{
+			name:          "untrimmed client",
+			client:        " acme",
+			mockBehaviur:  func(r *mock_repositories.MockAPIKeyRepository) {},
+			expectedError: ErrorInvalidAPIKeyClient,
+		},
+		{
+			name:          "too long name",
+			keyName:       string(bytes.Repeat([]byte("a"), 101)),
+			client:        "acme",
+			mockBehaviur:  func(r *mock_repositories.MockAPIKeyRepository) {},
+			expectedError: ErrorInvalidAPIKeyName,
+		},
+		{
+			name:   "some db error",
+			client: "acme",
+			tenant: "brand-a",
+			mockBehaviur: func(r *mock_repositories.MockAPIKeyRepository) {
+				r.EXPECT().CreateAPIKey(gomock.Any()).Return(models.APIKey{}, errors.New("some db error"))
+			},
+			expectedError: errors.New("some db error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockAPIKeyRepository(c)
+			tC.mockBehaviur(repo)
+
+			tenants, err := InitTenantService([]models.Tenant{{Id: "brand-a"}}, nil)
+			require.NoError(t, err)
+
+			service := InitAPIKeyService(repo, tenants, bytes.NewReader(bytes.Repeat([]byte{1, 2, 3, 4}, 10)))
+
+			apiKey, key, err := service.CreateAPIKey(tC.keyName, tC.client, tC.tenant)
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedAPIKey, apiKey)
+			require.Equal(t, tC.expectedKey, key)
+		})
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockAPIKeyRepository)
+
+	saved := models.APIKey{Id: 1, Client: "acme", Prefix: "010203040102", Hash: testAPIKeyHash}
+	revokedAt := time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC)
+	revoked := saved
+	revoked.RevokedAt = &revokedAt
+
+	testCases := []struct {
+		name           string
+		key            string
+		mockBehaviur   mockBehaviur
+		expectedAPIKey models.APIKey
+		expectedError  error
+	}{
+		{
+			name: "OK",
+			key:  testAPIKey,
+			mockBehaviur: func(r *mock_repositories.MockAPIKeyRepository) {
+				r.EXPECT().GetAPIKeyByPrefix("010203040102").Return(saved, nil)
+			},
+			expectedAPIKey: saved,
+		},
+		{
+			name: "wrong secret",
+			key:  "sk_010203040102_wrong",
+			mockBehaviur: func(r *mock_repositories.MockAPIKeyRepository) {
+				r.EXPECT().GetAPIKeyByPrefix("010203040102").Return(saved, nil)
+			},
+			expectedError: ErrorInvalidAPIKey,
+		},
+		{
+			name: "revoked key",
+			key:  testAPIKey,
+			mockBehaviur: func(r *mock_repositories.MockAPIKeyRepository) {
+				r.EXPECT().GetAPIKeyByPrefix("010203040102").Return(revoked, nil)
+			},
+			expectedError: ErrorInvalidAPIKey,
+		},
+		{
+			name: "unknown prefix",
+			key:  testAPIKey,
+			mockBehaviur: func(r *mock_repositories.MockAPIKeyRepository) {
+				r.EXPECT().GetAPIKeyByPrefix("010203040102").Return(models.APIKey{}, repositories.ErrorAPIKeyNotFound)
+			},
+			expectedError: ErrorInvalidAPIKey,
+		},
+		{
+			name:          "invalid format",
+			key:           "Bearer 42",
+			mockBehaviur:  func(r *mock_repositories.MockAPIKeyRepository) {},
+			expectedError: ErrorInvalidAPIKey,
+		},
+		{
+			name: "some db error",
+			key:  testAPIKey,
+			mockBehaviur: func(r *mock_repositories.MockAPIKeyRepository) {
+				r.EXPECT().GetAPIKeyByPrefix("010203040102").Return(models.APIKey{}, errors.New("some db error"))
+			},
+			expectedError: errors.New("some db error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockAPIKeyRepository(c)
+			tC.mockBehaviur(repo)
+
+			service := InitAPIKeyService(repo, nil, nil)
+
+			apiKey, err := service.Authenticate(tC.key)
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedAPIKey, apiKey)
+		})
+	}
+}
+
+func TestService_CreatedAPIKeyAuthenticates(t *testing.T) {
+	// Init dependencies
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// bytes 0xff are encoded as "_" in the secret part of the key
+	random := append(bytes.Repeat([]byte{1}, apiKeyPrefixBytes), bytes.Repeat([]byte{0xff, 0x01, 0x02}, apiKeySecretBytes)...)
+
+	var saved models.APIKey
+	repo := mock_repositories.NewMockAPIKeyRepository(c)
+	repo.EXPECT().CreateAPIKey(gomock.Any()).DoAndReturn(func(apiKey models.APIKey) (models.APIKey, error) {
+		saved = apiKey
+		saved.Id = 1
+		return saved, nil
+	})
+	repo.EXPECT().GetAPIKeyByPrefix("010101010101").DoAndReturn(func(prefix string) (models.APIKey, error) {
+		return saved, nil
+	})
+
+	tenants, err := InitTenantService(nil, nil)
+	require.NoError(t, err)
+
+	service := InitAPIKeyService(repo, tenants, bytes.NewReader(random))
+
+	// Call methods
+	_, key, err := service.CreateAPIKey("warehouse", "acme", "")
+	require.NoError(t, err)
+	require.Contains(t, strings.TrimPrefix(key, "sk_010101010101_"), "_")
+
+	apiKey, err := service.Authenticate(key)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), apiKey.Id)
+}
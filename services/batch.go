@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/Taras-Rm/shipment/models"
+)
+
+// max number of shipments in a batch
+const maxBatchSize = 500
+
+// error of the valid shipments which are not created in all-or-nothing mode
+var ErrorBatchNotCreated error = errors.New("not created: batch has invalid shipments")
+
+type AddShipmentsInput []AddShipmentInput
+
+func (i AddShipmentsInput) Validate() error {
+	if len(i) == 0 {
+		return errors.New("empty batch")
+	}
+	if len(i) > maxBatchSize {
+		return errors.New("too many shipments in batch")
+	}
+
+	return nil
+}
+
+// priced shipment of the batch
+type batchItem struct {
+	index     int
+	input     AddShipmentInput
+	breakdown models.PriceBreakdown
+	pieces    []models.Piece
+}
+
+// add valid shipments of the batch together (or none of them in atomic mode)
+func (s *shipmentService) AddShipments(inp AddShipmentsInput, atomic bool) ([]models.ShipmentBatchResult, error) {
+	if err := inp.Validate(); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.ShipmentBatchResult, len(inp))
+	items := make([]batchItem, 0, len(inp))
+
+	// every shipment is validated and priced separately
+	for i, shipmentInput := range inp {
+		results[i].Index = i
+
+		if err := shipmentInput.Validate(); err != nil {
+			setBatchError(&results[i], err)
+			continue
+		}
+
+		breakdown, pieces, err := s.price(shipmentInput)
+		if err != nil {
+			setBatchError(&results[i], err)
+			continue
+		}
+
+		items = append(items, batchItem{index: i, input: shipmentInput, breakdown: breakdown, pieces: pieces})
+	}
+
+	if atomic && len(items) < len(inp) {
+		for _, item := range items {
+			results[item.index].Error = ErrorBatchNotCreated.Error()
+		}
+		return results, nil
+	}
+	if len(items) == 0 {
+		return results, nil
+	}
+
+	// all valid shipments are created in a single transaction
+	created, err := s.createItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, shipment := range created {
+		price := shipment.Price
+		result := &results[items[j].index]
+		result.Id = shipment.Id
+		result.TrackingNumber = shipment.TrackingNumber
+		result.Price = &price
+	}
+
+	return results, nil
+}
+
+// create priced shipments with unique tracking numbers
+func (s *shipmentService) createItems(items []batchItem) ([]models.Shipment, error) {
+	// tracking numbers have to be unique within the batch too
+	taken := map[string]bool{}
+	shipments := make([]models.Shipment, 0, len(items))
+	for _, item := range items {
+		trackingNumber, err := s.newTrackingNumberExcept(taken)
+		if err != nil {
+			return nil, err
+		}
+		taken[trackingNumber] = true
+
+		shipment := newShipment(item.input, item.breakdown, item.pieces)
+		shipment.TrackingNumber = trackingNumber
+		shipments = append(shipments, shipment)
+	}
+
+	return s.shipmentRepository.CreateShipments(shipments)
+}
+
+// error of the shipment of the batch (with the invalid fields if it is a validation error)
+func setBatchError(result *models.ShipmentBatchResult, err error) {
+	result.Error = err.Error()
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		result.Fields = validationErr.Fields
+	}
+}
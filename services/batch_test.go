@@ -0,0 +1,187 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/Taras-Rm/shipment/tracking"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddShipmentsInput_Validate(t *testing.T) {
+	require.Equal(t, errors.New("empty batch"), AddShipmentsInput{}.Validate())
+	require.Equal(t, errors.New("too many shipments in batch"), make(AddShipmentsInput, maxBatchSize+1).Validate())
+	require.NoError(t, make(AddShipmentsInput, maxBatchSize).Validate())
+}
+
+func TestService_AddShipments(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockShipmentRepository, numbers []string)
+
+	valid := AddShipmentInput{
+		FromName:        "Mark",
+		FromEmail:       "testFrom@g.c",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "CA",
+		Weight:          234.4,
+	}
+	light := valid
+	light.Weight = 5
+	invalid := valid
+	invalid.ToEmail = "test"
+
+	// creating returns shipments with IDs in the same order
+	created := func(shipments []models.Shipment) ([]models.Shipment, error) {
+		for i := range shipments {
+			shipments[i].Id = uint(i + 1)
+		}
+		return shipments, nil
+	}
+	price := func(amount int64) *money.Money {
+		price := money.New(amount, "EUR")
+		return &price
+	}
+
+	testCases := []struct {
+		name            string
+		input           AddShipmentsInput
+		atomic          bool
+		mockBehaviur    mockBehaviur
+		expectedResults func(numbers []string) []models.ShipmentBatchResult
+		expectedError   error
+	}{
+		{
+			name:  "all created",
+			input: AddShipmentsInput{valid, light},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository, numbers []string) {
+				r.EXPECT().GetShipmentByTrackingNumber(gomock.Any()).Return(models.Shipment{}, repositories.ErrorShipmentNotFound).Times(2)
+				r.EXPECT().CreateShipments(gomock.Len(2)).DoAndReturn(created)
+			},
+			expectedResults: func(numbers []string) []models.ShipmentBatchResult {
+				return []models.ShipmentBatchResult{
+					{Index: 0, Id: 1, TrackingNumber: numbers[0], Price: price(500000)},
+					{Index: 1, Id: 2, TrackingNumber: numbers[1], Price: price(25000)},
+				}
+			},
+		},
+		{
+			name:  "valid ones created",
+			input: AddShipmentsInput{invalid, light},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository, numbers []string) {
+				r.EXPECT().GetShipmentByTrackingNumber(numbers[0]).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipments(gomock.Len(1)).DoAndReturn(created)
+			},
+			expectedResults: func(numbers []string) []models.ShipmentBatchResult {
+				return []models.ShipmentBatchResult{
+					{Index: 0, Error: "toEmail: invalid email", Fields: []models.FieldError{{Path: "toEmail", Code: CodeInvalidEmail, Message: "invalid email"}}},
+					{Index: 1, Id: 1, TrackingNumber: numbers[0], Price: price(25000)},
+				}
+			},
+		},
+		{
+			name:         "none created in atomic mode",
+			input:        AddShipmentsInput{valid, invalid},
+			atomic:       true,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository, numbers []string) {},
+			expectedResults: func(numbers []string) []models.ShipmentBatchResult {
+				return []models.ShipmentBatchResult{
+					{Index: 0, Error: "not created: batch has invalid shipments"},
+					{Index: 1, Error: "toEmail: invalid email", Fields: []models.FieldError{{Path: "toEmail", Code: CodeInvalidEmail, Message: "invalid email"}}},
+				}
+			},
+		},
+		{
+			name:         "all invalid",
+			input:        AddShipmentsInput{invalid},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository, numbers []string) {},
+			expectedResults: func(numbers []string) []models.ShipmentBatchResult {
+				return []models.ShipmentBatchResult{{Index: 0, Error: "toEmail: invalid email", Fields: []models.FieldError{{Path: "toEmail", Code: CodeInvalidEmail, Message: "invalid email"}}}}
+			},
+		},
+		{
+			name:          "empty batch",
+			input:         AddShipmentsInput{},
+			mockBehaviur:  func(r *mock_repositories.MockShipmentRepository, numbers []string) {},
+			expectedError: errors.New("empty batch"),
+		},
+		{
+			name:  "transaction failed",
+			input: AddShipmentsInput{valid, light},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository, numbers []string) {
+				r.EXPECT().GetShipmentByTrackingNumber(gomock.Any()).Return(models.Shipment{}, repositories.ErrorShipmentNotFound).Times(2)
+				r.EXPECT().CreateShipments(gomock.Len(2)).Return(nil, errors.New("some db error"))
+			},
+			expectedError: errors.New("some db error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			// the same serial numbers are generated for the service and for the test
+			serials := []byte{1, 2, 3, 4, 1, 2, 3, 5}
+			trackingNumbers, err := tracking.InitGenerator("SH", "UA", bytes.NewReader(serials))
+			require.NoError(t, err)
+			expectedNumbers, err := tracking.InitGenerator("SH", "UA", bytes.NewReader(serials))
+			require.NoError(t, err)
+			numbers := make([]string, 2)
+			for i := range numbers {
+				numbers[i], err = expectedNumbers.Generate()
+				require.NoError(t, err)
+			}
+
+			repo := mock_repositories.NewMockShipmentRepository(c)
+			tC.mockBehaviur(repo, numbers)
+
+			service := initTestServiceWithTracking(t, repo, nil, repositories.InitMemoryShipmentSearch(nil), trackingNumbers)
+
+			results, err := service.AddShipments(tC.input, tC.atomic)
+			require.Equal(t, tC.expectedError, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tC.expectedResults(numbers), results)
+		})
+	}
+}
+
+func TestSetBatchError(t *testing.T) {
+	fields := []models.FieldError{{Path: "toEmail", Code: "invalid", Message: "invalid email"}}
+
+	testCases := []struct {
+		name           string
+		err            error
+		expectedResult models.ShipmentBatchResult
+	}{
+		{
+			name:           "validation error",
+			err:            &ValidationError{Fields: fields},
+			expectedResult: models.ShipmentBatchResult{Index: 1, Error: (&ValidationError{Fields: fields}).Error(), Fields: fields},
+		},
+		{
+			name:           "other error",
+			err:            errors.New("some error"),
+			expectedResult: models.ShipmentBatchResult{Index: 1, Error: "some error"},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			result := models.ShipmentBatchResult{Index: 1}
+			setBatchError(&result, tC.err)
+			require.Equal(t, tC.expectedResult, result)
+		})
+	}
+}
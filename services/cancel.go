@@ -0,0 +1,41 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+)
+
+// cancel the shipment and calculate its refund (cancelled shipment is soft-deleted, so it is not listed or searched anymore)
+func (s *shipmentService) CancelShipment(id uint) (models.Shipment, error) {
+	shipment, err := s.shipmentRepository.GetShipmentByID(id)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	if err := ValidateTransition(shipment.Status, models.StatusCancelled); err != nil {
+		return models.Shipment{}, err
+	}
+
+	// cancellation is free before pick up
+	fee := s.policy.CancellationFee(shipment.Status, shipment.Price)
+	refund, err := shipment.Price.Sub(fee)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+	cancellation := models.Cancellation{
+		CancelledAt: time.Now().UTC(),
+		Fee:         fee,
+		Refund:      refund,
+	}
+
+	// cancellation fails if the status was changed in the meantime
+	err = s.shipmentRepository.CancelShipment(id, shipment.Status, cancellation)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+	shipment.Status = models.StatusCancelled
+	shipment.Cancellation = &cancellation
+
+	return shipment, nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CancelShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockShipmentRepository)
+
+	shipment := func(status models.ShipmentStatus) models.Shipment {
+		return models.Shipment{Id: 2, FromCountryCode: "UA", ToCountryCode: "CA", Price: money.New(500000, "EUR"), Status: status}
+	}
+
+	testCases := []struct {
+		name           string
+		mockBehaviur   mockBehaviur
+		expectedFee    money.Money
+		expectedRefund money.Money
+		expectedError  error
+	}{
+		{
+			name: "free before pick up",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(2)).Return(shipment(models.StatusLabelPrinted), nil)
+				r.EXPECT().CancelShipment(uint(2), models.StatusLabelPrinted, gomock.Any()).Return(nil)
+			},
+			expectedFee:    money.New(0, "EUR"),
+			expectedRefund: money.New(500000, "EUR"),
+		},
+		{
+			name: "fee after pick up",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(2)).Return(shipment(models.StatusInTransit), nil)
+				r.EXPECT().CancelShipment(uint(2), models.StatusInTransit, gomock.Any()).Return(nil)
+			},
+			expectedFee:    money.New(100000, "EUR"),
+			expectedRefund: money.New(400000, "EUR"),
+		},
+		{
+			name: "already delivered",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(2)).Return(shipment(models.StatusDelivered), nil)
+			},
+			expectedError: ErrorIllegalTransition,
+		},
+		{
+			name: "shipment not found",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(2)).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedError: repositories.ErrorShipmentNotFound,
+		},
+		{
+			name: "status was changed concurrently",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(2)).Return(shipment(models.StatusCreated), nil)
+				r.EXPECT().CancelShipment(uint(2), models.StatusCreated, gomock.Any()).Return(repositories.ErrorStatusConflict)
+			},
+			expectedError: repositories.ErrorStatusConflict,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockShipmentRepository(c)
+			tC.mockBehaviur(repo)
+
+			service := initTestService(t, repo)
+
+			cancelled, err := service.CancelShipment(2)
+			require.True(t, errors.Is(err, tC.expectedError), "expected %v, got %v", tC.expectedError, err)
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, models.StatusCancelled, cancelled.Status)
+			require.NotNil(t, cancelled.Cancellation)
+			require.False(t, cancelled.Cancellation.CancelledAt.IsZero())
+			require.Equal(t, tC.expectedFee, cancelled.Cancellation.Fee)
+			require.Equal(t, tC.expectedRefund, cancelled.Cancellation.Refund)
+		})
+	}
+}
+
+func TestService_TransitionShipment_Cancelled(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_repositories.NewMockShipmentRepository(c)
+	repo.EXPECT().GetShipmentByID(uint(2)).Return(models.Shipment{Id: 2, Price: money.New(500000, "EUR"), Status: models.StatusPickedUp}, nil)
+	repo.EXPECT().CancelShipment(uint(2), models.StatusPickedUp, gomock.Any()).Return(nil)
+
+	service := initTestService(t, repo)
+
+	// refund is recorded when shipment is cancelled by the transition
+	cancelled, err := service.TransitionShipment(2, models.StatusCancelled)
+	require.NoError(t, err)
+	require.Equal(t, money.New(400000, "EUR"), cancelled.Cancellation.Refund)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+)
+
+// max length of the key given by the client
+const maxIdempotencyKeyLength = 255
+
+var (
+	ErrorInvalidIdempotencyKey    error = errors.New("invalid idempotency key")
+	ErrorIdempotencyKeyReused     error = errors.New("idempotency key is already used with another request")
+	ErrorIdempotencyKeyInProgress error = errors.New("request with the same idempotency key is in progress")
+	ErrorInvalidIdempotencyKeyTTL error = errors.New("invalid idempotency key ttl")
+)
+
+//go:generate mockgen -source=idempotency.go -destination=mocks/idempotency.go
+type IdempotencyService interface {
+	StartRequest(owner, key string, body []byte) (models.IdempotencyKey, bool, error)
+	FinishRequest(owner, key string, statusCode int, body []byte) error
+	DeleteExpiredKeys() error
+}
+
+type idempotencyService struct {
+	idempotencyRepository repositories.IdempotencyRepository
+	ttl                   time.Duration
+}
+
+func InitIdempotencyService(idempotencyRepo repositories.IdempotencyRepository, ttl time.Duration) (IdempotencyService, error) {
+	if ttl <= 0 {
+		return nil, ErrorInvalidIdempotencyKeyTTL
+	}
+
+	return &idempotencyService{idempotencyRepository: idempotencyRepo, ttl: ttl}, nil
+}
+
+// save the key of a new request or get the saved response of the same request (true if it has to be replayed)
+func (s *idempotencyService) StartRequest(owner, key string, body []byte) (models.IdempotencyKey, bool, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return models.IdempotencyKey{}, false, ErrorInvalidIdempotencyKey
+	}
+
+	now := time.Now().UTC()
+	requestHash := hashRequest(body)
+	created, err := s.idempotencyRepository.CreateIdempotencyKey(models.IdempotencyKey{
+		Owner:       owner,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+	if created {
+		return models.IdempotencyKey{}, false, nil
+	}
+
+	// the key is used already
+	saved, err := s.idempotencyRepository.GetIdempotencyKey(owner, key)
+	if errors.Is(err, repositories.ErrorIdempotencyKeyNotFound) {
+		// the concurrent request failed and released the key
+		return models.IdempotencyKey{}, false, ErrorIdempotencyKeyInProgress
+	}
+	if err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+	if saved.RequestHash != requestHash {
+		return models.IdempotencyKey{}, false, ErrorIdempotencyKeyReused
+	}
+	if saved.StatusCode == 0 {
+		return models.IdempotencyKey{}, false, ErrorIdempotencyKeyInProgress
+	}
+
+	return saved, true, nil
+}
+
+// save the response of the request (the key is released after a server error, so the request may be retried)
+func (s *idempotencyService) FinishRequest(owner, key string, statusCode int, body []byte) error {
+	if statusCode >= http.StatusInternalServerError {
+		return s.idempotencyRepository.DeleteIdempotencyKey(owner, key)
+	}
+
+	return s.idempotencyRepository.SaveIdempotentResponse(owner, key, statusCode, body)
+}
+
+func (s *idempotencyService) DeleteExpiredKeys() error {
+	return s.idempotencyRepository.DeleteExpiredIdempotencyKeys(time.Now().UTC())
+}
+
+// hash of the request body (the same key may be used only with the same body)
+func hashRequest(body []byte) string {
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
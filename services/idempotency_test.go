@@ -0,0 +1,144 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitIdempotencyService(t *testing.T) {
+	_, err := InitIdempotencyService(nil, 0)
+	require.Equal(t, ErrorInvalidIdempotencyKeyTTL, err)
+
+	_, err = InitIdempotencyService(nil, time.Hour)
+	require.NoError(t, err)
+}
+
+func TestService_StartRequest(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockIdempotencyRepository)
+
+	body := []byte(`{"fromName":"Mark"}`)
+	requestHash := hashRequest(body)
+	saved := models.IdempotencyKey{Key: "key-1", RequestHash: requestHash, StatusCode: 201, ResponseBody: []byte(`{"id":2}`)}
+
+	// the new key expires after the ttl
+	newKey := gomock.AssignableToTypeOf(models.IdempotencyKey{})
+
+	testCases := []struct {
+		name           string
+		key            string
+		mockBehaviur   mockBehaviur
+		expectedSaved  models.IdempotencyKey
+		expectedReplay bool
+		expectedError  error
+	}{
+		{
+			name: "new request",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_repositories.MockIdempotencyRepository) {
+				r.EXPECT().CreateIdempotencyKey(newKey).DoAndReturn(func(key models.IdempotencyKey) (bool, error) {
+					if key.Owner != "acme" || key.Key != "key-1" || key.RequestHash != requestHash || key.ExpiresAt.Sub(key.CreatedAt) != 24*time.Hour {
+						return false, errors.New("unexpected key")
+					}
+					return true, nil
+				})
+			},
+		},
+		{
+			name: "replay",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_repositories.MockIdempotencyRepository) {
+				r.EXPECT().CreateIdempotencyKey(newKey).Return(false, nil)
+				r.EXPECT().GetIdempotencyKey("acme", "key-1").Return(saved, nil)
+			},
+			expectedSaved:  saved,
+			expectedReplay: true,
+		},
+		{
+			name: "another body",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_repositories.MockIdempotencyRepository) {
+				r.EXPECT().CreateIdempotencyKey(newKey).Return(false, nil)
+				r.EXPECT().GetIdempotencyKey("acme", "key-1").Return(models.IdempotencyKey{Key: "key-1", RequestHash: hashRequest([]byte(`{}`)), StatusCode: 201}, nil)
+			},
+			expectedError: ErrorIdempotencyKeyReused,
+		},
+		{
+			name: "in progress",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_repositories.MockIdempotencyRepository) {
+				r.EXPECT().CreateIdempotencyKey(newKey).Return(false, nil)
+				r.EXPECT().GetIdempotencyKey("acme", "key-1").Return(models.IdempotencyKey{Key: "key-1", RequestHash: requestHash}, nil)
+			},
+			expectedError: ErrorIdempotencyKeyInProgress,
+		},
+		{
+			name: "released by the concurrent request",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_repositories.MockIdempotencyRepository) {
+				r.EXPECT().CreateIdempotencyKey(newKey).Return(false, nil)
+				r.EXPECT().GetIdempotencyKey("acme", "key-1").Return(models.IdempotencyKey{}, repositories.ErrorIdempotencyKeyNotFound)
+			},
+			expectedError: ErrorIdempotencyKeyInProgress,
+		},
+		{
+			name:          "too long key",
+			key:           strings.Repeat("k", maxIdempotencyKeyLength+1),
+			mockBehaviur:  func(r *mock_repositories.MockIdempotencyRepository) {},
+			expectedError: ErrorInvalidIdempotencyKey,
+		},
+		{
+			name: "some db error",
+			key:  "key-1",
+			mockBehaviur: func(r *mock_repositories.MockIdempotencyRepository) {
+				r.EXPECT().CreateIdempotencyKey(newKey).Return(false, errors.New("some db error"))
+			},
+			expectedError: errors.New("some db error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockIdempotencyRepository(c)
+			tC.mockBehaviur(repo)
+
+			service, err := InitIdempotencyService(repo, 24*time.Hour)
+			require.NoError(t, err)
+
+			saved, replay, err := service.StartRequest("acme", tC.key, body)
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedReplay, replay)
+			require.Equal(t, tC.expectedSaved, saved)
+		})
+	}
+}
+
+func TestService_FinishRequest(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_repositories.NewMockIdempotencyRepository(c)
+	repo.EXPECT().SaveIdempotentResponse("acme", "key-1", 201, []byte(`{"id":2}`)).Return(nil)
+	repo.EXPECT().SaveIdempotentResponse("acme", "key-2", 400, []byte(`{"error":"invalid input body"}`)).Return(nil)
+	repo.EXPECT().DeleteIdempotencyKey("acme", "key-3").Return(nil)
+
+	service, err := InitIdempotencyService(repo, time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, service.FinishRequest("acme", "key-1", 201, []byte(`{"id":2}`)))
+	// client errors are replayed too
+	require.NoError(t, service.FinishRequest("acme", "key-2", 400, []byte(`{"error":"invalid input body"}`)))
+	// the request may be retried after a server error
+	require.NoError(t, service.FinishRequest("acme", "key-3", 500, []byte(`{"error":"some internal error"}`)))
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Taras-Rm/shipment/fx"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/pricing"
+)
+
+// number of valid rows which are created together
+const importChunkSize = 100
+
+// columns of the shipments CSV (the same names as the fields of the add shipment input)
+var importColumns = []string{
+	"fromName", "fromEmail", "fromAddress", "fromCountryCode",
+	"toName", "toEmail", "toAddress", "toCountryCode",
+	"weight", "length", "width", "height", "currency",
+}
+
+// columns which can be omitted in the header
+var optionalImportColumns = map[string]bool{
+	"length": true, "width": true, "height": true, "currency": true,
+}
+
+var ErrorInvalidImportFile error = errors.New("invalid import file")
+
+// add shipments of the CSV rows (valid rows are created even if some rows are invalid)
+func (s *shipmentService) ImportShipments(r io.Reader) (models.ImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return models.ImportResult{}, fmt.Errorf("%w: empty file", ErrorInvalidImportFile)
+	}
+	if err != nil {
+		return models.ImportResult{}, fmt.Errorf("%w: %v", ErrorInvalidImportFile, err)
+	}
+	columns, err := importHeader(header)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	result := models.ImportResult{Errors: []models.ImportError{}}
+	rows := 0
+	chunk := make([]batchItem, 0, importChunkSize)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		// a row with another number of fields is skipped, the rest of the file can't be read after other errors
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.Failed++
+			result.Errors = append(result.Errors, models.ImportError{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+			if errors.Is(err, csv.ErrFieldCount) {
+				continue
+			}
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		rows++
+
+		row, _ := reader.FieldPos(0)
+
+		item, rowErrors := s.importRow(row, record, columns)
+		if len(rowErrors) != 0 {
+			result.Failed++
+			result.Errors = append(result.Errors, rowErrors...)
+			continue
+		}
+
+		// rows are created by chunks while the file is read
+		chunk = append(chunk, item)
+		if len(chunk) == importChunkSize {
+			if err := s.createImported(chunk, &result); err != nil {
+				return result, err
+			}
+			chunk = chunk[:0]
+		}
+	}
+
+	if rows == 0 && result.Failed == 0 {
+		return models.ImportResult{}, fmt.Errorf("%w: no rows", ErrorInvalidImportFile)
+	}
+	if len(chunk) != 0 {
+		if err := s.createImported(chunk, &result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// indexes of the columns by the header row
+func importHeader(header []string) (map[string]int, error) {
+	known := map[string]bool{}
+	for _, column := range importColumns {
+		known[column] = true
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if !known[column] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrorInvalidImportFile, column)
+		}
+		if _, ok := columns[column]; ok {
+			return nil, fmt.Errorf("%w: duplicated column %q", ErrorInvalidImportFile, column)
+		}
+		columns[column] = i
+	}
+
+	for _, column := range importColumns {
+		if _, ok := columns[column]; !ok && !optionalImportColumns[column] {
+			return nil, fmt.Errorf("%w: missing column %q", ErrorInvalidImportFile, column)
+		}
+	}
+
+	return columns, nil
+}
+
+// validate and price a single row
+func (s *shipmentService) importRow(row int, record []string, columns map[string]int) (batchItem, []models.ImportError) {
+	var rowErrors []models.ImportError
+
+	value := func(column string) string {
+		i, ok := columns[column]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	number := func(column string) float64 {
+		v := value(column)
+		if v == "" {
+			return 0
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			rowErrors = append(rowErrors, models.ImportError{Row: row, Column: column, Error: "invalid number"})
+		}
+		return n
+	}
+
+	inp := AddShipmentInput{
+		FromName:        value("fromName"),
+		FromEmail:       value("fromEmail"),
+		FromAddress:     value("fromAddress"),
+		FromCountryCode: value("fromCountryCode"),
+		ToName:          value("toName"),
+		ToEmail:         value("toEmail"),
+		ToAddress:       value("toAddress"),
+		ToCountryCode:   value("toCountryCode"),
+		Weight:          number("weight"),
+		Length:          number("length"),
+		Width:           number("width"),
+		Height:          number("height"),
+		Currency:        value("currency"),
+	}
+	if len(rowErrors) != 0 {
+		return batchItem{}, rowErrors
+	}
+
+	// the row has an error of every invalid column
+	if err := inp.Validate(); err != nil {
+		return batchItem{}, importRowErrors(row, err)
+	}
+
+	breakdown, pieces, err := s.price(inp)
+	if err != nil {
+		column := ""
+		switch {
+		case errors.Is(err, pricing.ErrorWeightNotSupported):
+			column = "weight"
+		case errors.Is(err, fx.ErrorRateNotFound):
+			column = "currency"
+		}
+		return batchItem{}, []models.ImportError{{Row: row, Column: column, Error: err.Error()}}
+	}
+
+	return batchItem{index: row, input: inp, breakdown: breakdown, pieces: pieces}, nil
+}
+
+// errors of the row by the batch error of the shipment (without column if it is not a validation error)
+func importRowErrors(row int, err error) []models.ImportError {
+	var result models.ShipmentBatchResult
+	setBatchError(&result, err)
+	if len(result.Fields) == 0 {
+		return []models.ImportError{{Row: row, Error: result.Error}}
+	}
+
+	rowErrors := make([]models.ImportError, 0, len(result.Fields))
+	for _, field := range result.Fields {
+		rowErrors = append(rowErrors, models.ImportError{Row: row, Column: field.Path, Error: field.Message})
+	}
+	return rowErrors
+}
+
+// create the valid rows of the chunk
+func (s *shipmentService) createImported(chunk []batchItem, result *models.ImportResult) error {
+	created, err := s.createItems(chunk)
+	if err != nil {
+		return err
+	}
+
+	result.Created += len(created)
+	return nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/Taras-Rm/shipment/tracking"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ImportShipments(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockShipmentRepository)
+
+	header := "fromName,fromEmail,fromAddress,fromCountryCode,toName,toEmail,toAddress,toCountryCode,weight,currency\n"
+	valid := "Mark,testFrom@g.c,\"Lviv, 45\",UA,Iryna,testTo@g.c,\"Toronto, 34\",CA,234.4,\n"
+	light := "Tom,testFrom@g.c,\"Lutsk, 34\",UA,Viktor,testTo@g.c,\"London, 32\",GB,5,SEK\n"
+
+	// creating returns shipments with IDs in the same order
+	created := func(shipments []models.Shipment) ([]models.Shipment, error) {
+		for i := range shipments {
+			shipments[i].Id = uint(i + 1)
+		}
+		return shipments, nil
+	}
+
+	testCases := []struct {
+		name           string
+		file           string
+		mockBehaviur   mockBehaviur
+		expectedResult models.ImportResult
+		expectedError  error
+	}{
+		{
+			name: "all created",
+			file: header + valid + light,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(gomock.Any()).Return(models.Shipment{}, repositories.ErrorShipmentNotFound).Times(2)
+				r.EXPECT().CreateShipments(gomock.Len(2)).DoAndReturn(created)
+			},
+			expectedResult: models.ImportResult{Created: 2, Errors: []models.ImportError{}},
+		},
+		{
+			name: "errors of the columns",
+			file: header +
+				"Mark,test,\"Lviv, 45\",U,Iryna,testTo@g.c,\"Toronto, 34\",CA,234.4,\n" +
+				light +
+				"Mark1,testFrom@g.c,\"Lviv, 45\",UA,Iryna,testTo@g.c,\"Toronto, 34\",CA,heavy,\n" +
+				"Mark1,testFrom@g.c,\"Lviv, 45\",UA,Iryna,testTo@g.c,\"Toronto, 34\",QQ,1,\n" +
+				"Mark,testFrom@g.c,\"Lviv, 45\",UA,Iryna,testTo@g.c,\"Toronto, 34\",CA,5,JPY\n",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(gomock.Any()).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipments(gomock.Len(1)).DoAndReturn(created)
+			},
+			expectedResult: models.ImportResult{
+				Created: 1,
+				Failed:  4,
+				Errors: []models.ImportError{
+					{Row: 2, Column: "fromEmail", Error: "invalid email"},
+					{Row: 2, Column: "fromCountryCode", Error: "invalid country code"},
+					{Row: 4, Column: "weight", Error: "invalid number"},
+					{Row: 5, Column: "fromName", Error: "invalid name"},
+					{Row: 5, Column: "toCountryCode", Error: "not existing country code"},
+					{Row: 6, Column: "currency", Error: "EUR/JPY: fx rate not found"},
+				},
+			},
+		},
+		{
+			name: "wrong number of fields",
+			file: header + "Mark,testFrom@g.c\n" + light,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(gomock.Any()).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipments(gomock.Len(1)).DoAndReturn(created)
+			},
+			expectedResult: models.ImportResult{
+				Created: 1,
+				Failed:  1,
+				Errors:  []models.ImportError{{Row: 2, Error: "wrong number of fields"}},
+			},
+		},
+		{
+			name: "broken quotes",
+			file: header + valid + "Mark,\"test\"From@g.c\n" + light,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(gomock.Any()).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipments(gomock.Len(1)).DoAndReturn(created)
+			},
+			expectedResult: models.ImportResult{
+				Created: 1,
+				Failed:  1,
+				Errors:  []models.ImportError{{Row: 3, Error: `extraneous or missing " in quoted-field`}},
+			},
+		},
+		{
+			name:          "empty file",
+			file:          "",
+			mockBehaviur:  func(r *mock_repositories.MockShipmentRepository) {},
+			expectedError: fmt.Errorf("%w: empty file", ErrorInvalidImportFile),
+		},
+		{
+			name:          "no rows",
+			file:          header,
+			mockBehaviur:  func(r *mock_repositories.MockShipmentRepository) {},
+			expectedError: fmt.Errorf("%w: no rows", ErrorInvalidImportFile),
+		},
+		{
+			name:          "unknown column",
+			file:          "fromName,price\n" + valid,
+			mockBehaviur:  func(r *mock_repositories.MockShipmentRepository) {},
+			expectedError: fmt.Errorf("%w: unknown column %q", ErrorInvalidImportFile, "price"),
+		},
+		{
+			name:          "missing column",
+			file:          "fromName,fromEmail\n" + valid,
+			mockBehaviur:  func(r *mock_repositories.MockShipmentRepository) {},
+			expectedError: fmt.Errorf("%w: missing column %q", ErrorInvalidImportFile, "fromAddress"),
+		},
+		{
+			name: "some db error",
+			file: header + valid,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(gomock.Any()).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipments(gomock.Len(1)).Return(nil, errors.New("some db error"))
+			},
+			expectedError: errors.New("some db error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			// different serial numbers for the rows of the same chunk
+			trackingNumbers, err := tracking.InitGenerator("SH", "UA", bytes.NewReader([]byte{1, 2, 3, 4, 1, 2, 3, 5}))
+			require.NoError(t, err)
+
+			repo := mock_repositories.NewMockShipmentRepository(c)
+			tC.mockBehaviur(repo)
+
+			service := initTestServiceWithTracking(t, repo, nil, repositories.InitMemoryShipmentSearch(nil), trackingNumbers)
+
+			result, err := service.ImportShipments(strings.NewReader(tC.file))
+			require.Equal(t, tC.expectedError, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tC.expectedResult, result)
+		})
+	}
+}
+
+func TestImportRowErrors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedErrors []models.ImportError
+	}{
+		{
+			name: "validation error",
+			err: &ValidationError{Fields: []models.FieldError{
+				{Path: "fromEmail", Code: "invalid", Message: "invalid email"},
+				{Path: "weight", Code: CodeRequired, Message: "is required"},
+			}},
+			expectedErrors: []models.ImportError{
+				{Row: 3, Column: "fromEmail", Error: "invalid email"},
+				{Row: 3, Column: "weight", Error: "is required"},
+			},
+		},
+		{
+			name:           "other error",
+			err:            errors.New("some error"),
+			expectedErrors: []models.ImportError{{Row: 3, Error: "some error"}},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			require.Equal(t, tC.expectedErrors, importRowErrors(3, tC.err))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rateCard.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	reflect "reflect"
+
+	models "github.com/Taras-Rm/shipment/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRateCardService is a mock of RateCardService interface.
+type MockRateCardService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateCardServiceMockRecorder
+}
+
+// MockRateCardServiceMockRecorder is the mock recorder for MockRateCardService.
+type MockRateCardServiceMockRecorder struct {
+	mock *MockRateCardService
+}
+
+// NewMockRateCardService creates a new mock instance.
+func NewMockRateCardService(ctrl *gomock.Controller) *MockRateCardService {
+	mock := &MockRateCardService{ctrl: ctrl}
+	mock.recorder = &MockRateCardServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateCardService) EXPECT() *MockRateCardServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateRateCard mocks base method.
+func (m *MockRateCardService) CreateRateCard(card models.RateCard) (models.RateCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRateCard", card)
+	ret0, _ := ret[0].(models.RateCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRateCard indicates an expected call of CreateRateCard.
+func (mr *MockRateCardServiceMockRecorder) CreateRateCard(card interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRateCard", reflect.TypeOf((*MockRateCardService)(nil).CreateRateCard), card)
+}
+
+// GetActiveRateCard mocks base method.
+func (m *MockRateCardService) GetActiveRateCard() models.RateCard {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveRateCard")
+	ret0, _ := ret[0].(models.RateCard)
+	return ret0
+}
+
+// GetActiveRateCard indicates an expected call of GetActiveRateCard.
+func (mr *MockRateCardServiceMockRecorder) GetActiveRateCard() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveRateCard", reflect.TypeOf((*MockRateCardService)(nil).GetActiveRateCard))
+}
+
+// RefreshRateCard mocks base method.
+func (m *MockRateCardService) RefreshRateCard() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshRateCard")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshRateCard indicates an expected call of RefreshRateCard.
+func (mr *MockRateCardServiceMockRecorder) RefreshRateCard() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRateCard", reflect.TypeOf((*MockRateCardService)(nil).RefreshRateCard))
+}
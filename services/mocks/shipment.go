@@ -5,9 +5,11 @@
 package mock_services
 
 import (
+	io "io"
 	reflect "reflect"
 
 	models "github.com/Taras-Rm/shipment/models"
+	pricing "github.com/Taras-Rm/shipment/pricing"
 	services "github.com/Taras-Rm/shipment/services"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,10 +38,10 @@ func (m *MockShipmentService) EXPECT() *MockShipmentServiceMockRecorder {
 }
 
 // AddShipment mocks base method.
-func (m *MockShipmentService) AddShipment(inp services.AddShipmentInput) (float64, error) {
+func (m *MockShipmentService) AddShipment(inp services.AddShipmentInput) (models.Shipment, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddShipment", inp)
-	ret0, _ := ret[0].(float64)
+	ret0, _ := ret[0].(models.Shipment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -50,19 +52,94 @@ func (mr *MockShipmentServiceMockRecorder) AddShipment(inp interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShipment", reflect.TypeOf((*MockShipmentService)(nil).AddShipment), inp)
 }
 
-// GetAllShipments mocks base method.
-func (m *MockShipmentService) GetAllShipments() ([]models.Shipment, error) {
+// AddShipments mocks base method.
+func (m *MockShipmentService) AddShipments(inp services.AddShipmentsInput, atomic bool) ([]models.ShipmentBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddShipments", inp, atomic)
+	ret0, _ := ret[0].([]models.ShipmentBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddShipments indicates an expected call of AddShipments.
+func (mr *MockShipmentServiceMockRecorder) AddShipments(inp, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShipments", reflect.TypeOf((*MockShipmentService)(nil).AddShipments), inp, atomic)
+}
+
+// AddTrackingEvent mocks base method.
+func (m *MockShipmentService) AddTrackingEvent(id uint, inp services.AddTrackingEventInput) (models.TrackingEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllShipments")
-	ret0, _ := ret[0].([]models.Shipment)
+	ret := m.ctrl.Call(m, "AddTrackingEvent", id, inp)
+	ret0, _ := ret[0].(models.TrackingEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTrackingEvent indicates an expected call of AddTrackingEvent.
+func (mr *MockShipmentServiceMockRecorder) AddTrackingEvent(id, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTrackingEvent", reflect.TypeOf((*MockShipmentService)(nil).AddTrackingEvent), id, inp)
+}
+
+// CancelShipment mocks base method.
+func (m *MockShipmentService) CancelShipment(id uint) (models.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelShipment", id)
+	ret0, _ := ret[0].(models.Shipment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllShipments indicates an expected call of GetAllShipments.
-func (mr *MockShipmentServiceMockRecorder) GetAllShipments() *gomock.Call {
+// CancelShipment indicates an expected call of CancelShipment.
+func (mr *MockShipmentServiceMockRecorder) CancelShipment(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllShipments", reflect.TypeOf((*MockShipmentService)(nil).GetAllShipments))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelShipment", reflect.TypeOf((*MockShipmentService)(nil).CancelShipment), id)
+}
+
+// ConvertShipment mocks base method.
+func (m *MockShipmentService) ConvertShipment(shipment models.Shipment, currency string) (models.Shipment, models.FxRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertShipment", shipment, currency)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].(models.FxRate)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ConvertShipment indicates an expected call of ConvertShipment.
+func (mr *MockShipmentServiceMockRecorder) ConvertShipment(shipment, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertShipment", reflect.TypeOf((*MockShipmentService)(nil).ConvertShipment), shipment, currency)
+}
+
+// ExportShipments mocks base method.
+func (m *MockShipmentService) ExportShipments(inp services.ListShipmentsInput, fn func(models.Shipment) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportShipments", inp, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportShipments indicates an expected call of ExportShipments.
+func (mr *MockShipmentServiceMockRecorder) ExportShipments(inp, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportShipments", reflect.TypeOf((*MockShipmentService)(nil).ExportShipments), inp, fn)
+}
+
+// GetLane mocks base method.
+func (m *MockShipmentService) GetLane(fromCountryCode, toCountryCode string) (models.Lane, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLane", fromCountryCode, toCountryCode)
+	ret0, _ := ret[0].(models.Lane)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLane indicates an expected call of GetLane.
+func (mr *MockShipmentServiceMockRecorder) GetLane(fromCountryCode, toCountryCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLane", reflect.TypeOf((*MockShipmentService)(nil).GetLane), fromCountryCode, toCountryCode)
 }
 
 // GetShipmentByID mocks base method.
@@ -79,3 +156,198 @@ func (mr *MockShipmentServiceMockRecorder) GetShipmentByID(id interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentByID", reflect.TypeOf((*MockShipmentService)(nil).GetShipmentByID), id)
 }
+
+// GetShipmentByTrackingNumber mocks base method.
+func (m *MockShipmentService) GetShipmentByTrackingNumber(number string) (models.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShipmentByTrackingNumber", number)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShipmentByTrackingNumber indicates an expected call of GetShipmentByTrackingNumber.
+func (mr *MockShipmentServiceMockRecorder) GetShipmentByTrackingNumber(number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentByTrackingNumber", reflect.TypeOf((*MockShipmentService)(nil).GetShipmentByTrackingNumber), number)
+}
+
+// GetTrackingEvents mocks base method.
+func (m *MockShipmentService) GetTrackingEvents(id uint) ([]models.TrackingEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrackingEvents", id)
+	ret0, _ := ret[0].([]models.TrackingEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrackingEvents indicates an expected call of GetTrackingEvents.
+func (mr *MockShipmentServiceMockRecorder) GetTrackingEvents(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrackingEvents", reflect.TypeOf((*MockShipmentService)(nil).GetTrackingEvents), id)
+}
+
+// ImportShipments mocks base method.
+func (m *MockShipmentService) ImportShipments(r io.Reader) (models.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportShipments", r)
+	ret0, _ := ret[0].(models.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportShipments indicates an expected call of ImportShipments.
+func (mr *MockShipmentServiceMockRecorder) ImportShipments(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportShipments", reflect.TypeOf((*MockShipmentService)(nil).ImportShipments), r)
+}
+
+// ListShipments mocks base method.
+func (m *MockShipmentService) ListShipments(inp services.ListShipmentsInput) (models.ShipmentPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShipments", inp)
+	ret0, _ := ret[0].(models.ShipmentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShipments indicates an expected call of ListShipments.
+func (mr *MockShipmentServiceMockRecorder) ListShipments(inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShipments", reflect.TypeOf((*MockShipmentService)(nil).ListShipments), inp)
+}
+
+// PatchShipment mocks base method.
+func (m *MockShipmentService) PatchShipment(id uint, inp services.PatchShipmentInput) (models.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchShipment", id, inp)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchShipment indicates an expected call of PatchShipment.
+func (mr *MockShipmentServiceMockRecorder) PatchShipment(id, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchShipment", reflect.TypeOf((*MockShipmentService)(nil).PatchShipment), id, inp)
+}
+
+// Quote mocks base method.
+func (m *MockShipmentService) Quote(inp services.AddShipmentInput) (models.PriceBreakdown, []models.Piece, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", inp)
+	ret0, _ := ret[0].(models.PriceBreakdown)
+	ret1, _ := ret[1].([]models.Piece)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockShipmentServiceMockRecorder) Quote(inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockShipmentService)(nil).Quote), inp)
+}
+
+// ReturnShipment mocks base method.
+func (m *MockShipmentService) ReturnShipment(id uint) (models.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnShipment", id)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReturnShipment indicates an expected call of ReturnShipment.
+func (mr *MockShipmentServiceMockRecorder) ReturnShipment(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnShipment", reflect.TypeOf((*MockShipmentService)(nil).ReturnShipment), id)
+}
+
+// SearchShipments mocks base method.
+func (m *MockShipmentService) SearchShipments(inp services.SearchShipmentsInput) ([]models.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchShipments", inp)
+	ret0, _ := ret[0].([]models.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchShipments indicates an expected call of SearchShipments.
+func (mr *MockShipmentServiceMockRecorder) SearchShipments(inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchShipments", reflect.TypeOf((*MockShipmentService)(nil).SearchShipments), inp)
+}
+
+// TrackShipment mocks base method.
+func (m *MockShipmentService) TrackShipment(number string) (models.Shipment, []models.TrackingEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrackShipment", number)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].([]models.TrackingEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TrackShipment indicates an expected call of TrackShipment.
+func (mr *MockShipmentServiceMockRecorder) TrackShipment(number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackShipment", reflect.TypeOf((*MockShipmentService)(nil).TrackShipment), number)
+}
+
+// TransitionShipment mocks base method.
+func (m *MockShipmentService) TransitionShipment(id uint, status models.ShipmentStatus) (models.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionShipment", id, status)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionShipment indicates an expected call of TransitionShipment.
+func (mr *MockShipmentServiceMockRecorder) TransitionShipment(id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionShipment", reflect.TypeOf((*MockShipmentService)(nil).TransitionShipment), id, status)
+}
+
+// UpdateShipment mocks base method.
+func (m *MockShipmentService) UpdateShipment(id uint, inp services.AddShipmentInput) (models.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateShipment", id, inp)
+	ret0, _ := ret[0].(models.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateShipment indicates an expected call of UpdateShipment.
+func (mr *MockShipmentServiceMockRecorder) UpdateShipment(id, inp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShipment", reflect.TypeOf((*MockShipmentService)(nil).UpdateShipment), id, inp)
+}
+
+// WithOwner mocks base method.
+func (m *MockShipmentService) WithOwner(owner string) services.ShipmentService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithOwner", owner)
+	ret0, _ := ret[0].(services.ShipmentService)
+	return ret0
+}
+
+// WithOwner indicates an expected call of WithOwner.
+func (mr *MockShipmentServiceMockRecorder) WithOwner(owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithOwner", reflect.TypeOf((*MockShipmentService)(nil).WithOwner), owner)
+}
+
+// WithTenant mocks base method.
+func (m *MockShipmentService) WithTenant(tenantID string, pricingEngine pricing.Engine) services.ShipmentService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTenant", tenantID, pricingEngine)
+	ret0, _ := ret[0].(services.ShipmentService)
+	return ret0
+}
+
+// WithTenant indicates an expected call of WithTenant.
+func (mr *MockShipmentServiceMockRecorder) WithTenant(tenantID, pricingEngine interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTenant", reflect.TypeOf((*MockShipmentService)(nil).WithTenant), tenantID, pricingEngine)
+}
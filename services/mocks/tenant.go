@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tenant.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	reflect "reflect"
+
+	models "github.com/Taras-Rm/shipment/models"
+	pricing "github.com/Taras-Rm/shipment/pricing"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTenantService is a mock of TenantService interface.
+type MockTenantService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantServiceMockRecorder
+}
+
+// MockTenantServiceMockRecorder is the mock recorder for MockTenantService.
+type MockTenantServiceMockRecorder struct {
+	mock *MockTenantService
+}
+
+// NewMockTenantService creates a new mock instance.
+func NewMockTenantService(ctrl *gomock.Controller) *MockTenantService {
+	mock := &MockTenantService{ctrl: ctrl}
+	mock.recorder = &MockTenantServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantService) EXPECT() *MockTenantServiceMockRecorder {
+	return m.recorder
+}
+
+// GetTenant mocks base method.
+func (m *MockTenantService) GetTenant(id string) (models.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenant", id)
+	ret0, _ := ret[0].(models.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenant indicates an expected call of GetTenant.
+func (mr *MockTenantServiceMockRecorder) GetTenant(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenant", reflect.TypeOf((*MockTenantService)(nil).GetTenant), id)
+}
+
+// GetTenantByHost mocks base method.
+func (m *MockTenantService) GetTenantByHost(host string) (models.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenantByHost", host)
+	ret0, _ := ret[0].(models.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenantByHost indicates an expected call of GetTenantByHost.
+func (mr *MockTenantServiceMockRecorder) GetTenantByHost(host interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenantByHost", reflect.TypeOf((*MockTenantService)(nil).GetTenantByHost), host)
+}
+
+// MultiTenant mocks base method.
+func (m *MockTenantService) MultiTenant() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiTenant")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// MultiTenant indicates an expected call of MultiTenant.
+func (mr *MockTenantServiceMockRecorder) MultiTenant() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiTenant", reflect.TypeOf((*MockTenantService)(nil).MultiTenant))
+}
+
+// PricingEngine mocks base method.
+func (m *MockTenantService) PricingEngine(id string) pricing.Engine {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PricingEngine", id)
+	ret0, _ := ret[0].(pricing.Engine)
+	return ret0
+}
+
+// PricingEngine indicates an expected call of PricingEngine.
+func (mr *MockTenantServiceMockRecorder) PricingEngine(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PricingEngine", reflect.TypeOf((*MockTenantService)(nil).PricingEngine), id)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+)
+
+// rules of changing shipments after they were added
+type ShipmentPolicy struct {
+	// the last status in which shipment may be edited
+	EditableUntil models.ShipmentStatus
+	// the last status in which shipment is cancelled for free
+	FreeCancellationUntil models.ShipmentStatus
+	// share of the price (percent) which is kept when shipment is cancelled later
+	CancellationFeePercent float64
+}
+
+func InitShipmentPolicy(editableUntil, freeCancellationUntil models.ShipmentStatus, cancellationFeePercent float64) (ShipmentPolicy, error) {
+	// shipments in the terminal statuses are never edited or cancelled
+	for _, status := range []models.ShipmentStatus{editableUntil, freeCancellationUntil} {
+		if _, ok := shipmentStatusOrder[status]; !ok || len(shipmentTransitions[status]) == 0 {
+			return ShipmentPolicy{}, fmt.Errorf("%w %q", ErrorUnknownStatus, status)
+		}
+	}
+
+	if cancellationFeePercent < 0 || cancellationFeePercent > 100 {
+		return ShipmentPolicy{}, errors.New("invalid cancellation fee")
+	}
+
+	return ShipmentPolicy{
+		EditableUntil:          editableUntil,
+		FreeCancellationUntil:  freeCancellationUntil,
+		CancellationFeePercent: cancellationFeePercent,
+	}, nil
+}
+
+// check that shipment in the given status may be edited
+func (p ShipmentPolicy) Editable(status models.ShipmentStatus) bool {
+	order, ok := shipmentStatusOrder[status]
+	return ok && order <= shipmentStatusOrder[p.EditableUntil]
+}
+
+// fee which is kept when shipment in the given status is cancelled
+func (p ShipmentPolicy) CancellationFee(status models.ShipmentStatus, price money.Money) money.Money {
+	if shipmentStatusOrder[status] <= shipmentStatusOrder[p.FreeCancellationUntil] {
+		return money.New(0, price.Currency)
+	}
+
+	return price.Mul(p.CancellationFeePercent / 100)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShipmentPolicy_Editable(t *testing.T) {
+	testCases := []struct {
+		name          string
+		editableUntil models.ShipmentStatus
+		status        models.ShipmentStatus
+		expected      bool
+	}{
+		{name: "the same status", editableUntil: models.StatusCreated, status: models.StatusCreated, expected: true},
+		{name: "past the status", editableUntil: models.StatusCreated, status: models.StatusLabelPrinted, expected: false},
+		{name: "before the status", editableUntil: models.StatusPickedUp, status: models.StatusLabelPrinted, expected: true},
+		{name: "terminal status", editableUntil: models.StatusOutForDelivery, status: models.StatusCancelled, expected: false},
+		{name: "unknown status", editableUntil: models.StatusOutForDelivery, status: "lost", expected: false},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			policy, err := InitShipmentPolicy(tC.editableUntil, models.StatusLabelPrinted, 20)
+			require.NoError(t, err)
+			require.Equal(t, tC.expected, policy.Editable(tC.status))
+		})
+	}
+}
+
+func TestInitShipmentPolicy(t *testing.T) {
+	_, err := InitShipmentPolicy(models.StatusDelivered, models.StatusLabelPrinted, 20)
+	require.ErrorIs(t, err, ErrorUnknownStatus)
+
+	_, err = InitShipmentPolicy(models.StatusCreated, "lost", 20)
+	require.ErrorIs(t, err, ErrorUnknownStatus)
+
+	_, err = InitShipmentPolicy(models.StatusCreated, models.StatusLabelPrinted, 120)
+	require.Equal(t, errors.New("invalid cancellation fee"), err)
+}
+
+func TestShipmentPolicy_CancellationFee(t *testing.T) {
+	policy, err := InitShipmentPolicy(models.StatusCreated, models.StatusLabelPrinted, 12.5)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		status   models.ShipmentStatus
+		price    money.Money
+		expected money.Money
+	}{
+		{name: "before label is printed", status: models.StatusCreated, price: money.New(9999, "EUR"), expected: money.New(0, "EUR")},
+		{name: "before pick up", status: models.StatusLabelPrinted, price: money.New(9999, "EUR"), expected: money.New(0, "EUR")},
+		{name: "after pick up", status: models.StatusPickedUp, price: money.New(9999, "EUR"), expected: money.New(1250, "EUR")},
+		{name: "out for delivery", status: models.StatusOutForDelivery, price: money.New(5228350, "SEK"), expected: money.New(653544, "SEK")},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			require.Equal(t, tC.expected, policy.CancellationFee(tC.status, tC.price))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/Taras-Rm/shipment/pricing"
+)
+
+// function which prices a single parcel by the rate card
+type quoteFunc func(fromCountryCode, toCountryCode string, parcel models.Parcel) (models.PriceBreakdown, error)
+
+// calculate itemized price of the shipment (every piece separately) in the requested currency
+func (s *shipmentService) price(inp AddShipmentInput) (models.PriceBreakdown, []models.Piece, error) {
+	return s.priceWith(inp, pricing.Current(s.pricingEngine).Quote)
+}
+
+// calculate itemized price of the return shipment
+func (s *shipmentService) priceReturn(inp AddShipmentInput) (models.PriceBreakdown, []models.Piece, error) {
+	return s.priceWith(inp, pricing.Current(s.pricingEngine).QuoteReturn)
+}
+
+func (s *shipmentService) priceWith(inp AddShipmentInput, quote quoteFunc) (models.PriceBreakdown, []models.Piece, error) {
+	var rate *models.FxRate
+
+	parcels := inp.Parcels()
+	breakdowns := make([]models.PriceBreakdown, 0, len(parcels))
+	pieces := make([]models.Piece, 0, len(parcels))
+
+	for _, parcel := range parcels {
+		breakdown, err := quote(inp.FromCountryCode, inp.ToCountryCode, parcel)
+		if err != nil {
+			return models.PriceBreakdown{}, nil, err
+		}
+
+		// rate card currency is used by default
+		if inp.Currency != "" && inp.Currency != breakdown.Total.Currency {
+			if rate == nil {
+				fxRate, err := s.fxConverter.Rate(breakdown.Total.Currency, inp.Currency, time.Now())
+				if err != nil {
+					return models.PriceBreakdown{}, nil, err
+				}
+				rate = &fxRate
+			}
+
+			breakdown = convertBreakdown(breakdown, *rate)
+			breakdown.FxRate = rate
+		}
+
+		breakdowns = append(breakdowns, breakdown)
+		pieces = append(pieces, models.Piece{
+			Weight:         parcel.Weight,
+			Length:         parcel.Length,
+			Width:          parcel.Width,
+			Height:         parcel.Height,
+			BillableWeight: breakdown.BillableWeight,
+			Price:          breakdown.Total,
+		})
+	}
+
+	return pricing.Aggregate(breakdowns), pieces, nil
+}
+
+// convert every component of the breakdown (total is the sum of converted components)
+func convertBreakdown(breakdown models.PriceBreakdown, rate models.FxRate) models.PriceBreakdown {
+	components := make([]models.PriceComponent, 0, len(breakdown.Components))
+	total := money.New(0, rate.To)
+
+	for _, component := range breakdown.Components {
+		component.Amount = component.Amount.Convert(rate.To, rate.Rate)
+		components = append(components, component)
+		total.Amount += component.Amount.Amount
+	}
+
+	breakdown.Components = components
+	breakdown.Total = total
+
+	return breakdown
+}
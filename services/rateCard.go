@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/pricing"
+	"github.com/Taras-Rm/shipment/repositories"
+)
+
+var (
+	ErrorInvalidRateCard    error = errors.New("invalid rate card")
+	ErrorRateCardsNotStored error = errors.New("rate cards are not loaded from the db")
+)
+
+//go:generate mockgen -source=rateCard.go -destination=mocks/rateCard.go
+type RateCardService interface {
+	GetActiveRateCard() models.RateCard
+	CreateRateCard(card models.RateCard) (models.RateCard, error)
+	RefreshRateCard() error
+}
+
+type rateCardService struct {
+	rateCardRepository repositories.RateCardRepository
+	pricingEngine      *pricing.SwitchableEngine
+	// new versions are saved only when the rate card is loaded from the db
+	stored bool
+}
+
+func InitRateCardService(rateCardRepo repositories.RateCardRepository, pricingEngine *pricing.SwitchableEngine, stored bool) RateCardService {
+	return &rateCardService{rateCardRepository: rateCardRepo, pricingEngine: pricingEngine, stored: stored}
+}
+
+// rate card which prices the shipments now
+func (s *rateCardService) GetActiveRateCard() models.RateCard {
+	return s.pricingEngine.RateCard()
+}
+
+// save a new version of the rate card (it prices the shipments from its effectiveFrom)
+func (s *rateCardService) CreateRateCard(card models.RateCard) (models.RateCard, error) {
+	if !s.stored {
+		return models.RateCard{}, ErrorRateCardsNotStored
+	}
+
+	now := time.Now().UTC()
+	if card.EffectiveFrom.IsZero() {
+		card.EffectiveFrom = now
+	}
+
+	// the rate card is validated by the engine
+	engine, err := pricing.InitEngine(card)
+	if err != nil {
+		return models.RateCard{}, fmt.Errorf("%w: %v", ErrorInvalidRateCard, err)
+	}
+
+	if err := s.rateCardRepository.CreateRateCard(card); err != nil {
+		return models.RateCard{}, err
+	}
+
+	// a future version is switched to by the refresh
+	if !card.EffectiveFrom.After(now) && !card.EffectiveFrom.Before(s.pricingEngine.RateCard().EffectiveFrom) {
+		s.pricingEngine.Switch(engine)
+	}
+
+	return card, nil
+}
+
+// switch to the latest effective rate card of the db (a new version or the one whose effectiveFrom has passed)
+func (s *rateCardService) RefreshRateCard() error {
+	if !s.stored {
+		return nil
+	}
+
+	card, err := s.rateCardRepository.GetActiveRateCard(time.Now())
+	if err != nil {
+		return err
+	}
+	if card.Version == s.pricingEngine.RateCard().Version {
+		return nil
+	}
+
+	engine, err := pricing.InitEngine(card)
+	if err != nil {
+		return fmt.Errorf("rate card %q: %w", card.Version, err)
+	}
+	s.pricingEngine.Switch(engine)
+
+	return nil
+}
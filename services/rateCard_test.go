@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/pricing"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// valid rate card of the version effective from the date
+func testRateCard(version string, effectiveFrom time.Time) models.RateCard {
+	card := pricing.DefaultRateCard()
+	card.Version = version
+	card.EffectiveFrom = effectiveFrom
+	return card
+}
+
+func TestService_CreateRateCard(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockRateCardRepository)
+
+	active := testRateCard("2022-01", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	future := testRateCard("2099-01", time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC))
+	older := testRateCard("2021-12", time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC))
+	invalid := testRateCard("2022-02", time.Time{})
+	invalid.WeightBrackets = nil
+
+	testCases := []struct {
+		name            string
+		stored          bool
+		card            models.RateCard
+		mockBehaviur    mockBehaviur
+		expectedVersion string
+		expectedActive  string
+		expectedError   error
+	}{
+		{
+			name:   "OK effective now",
+			stored: true,
+			card:   testRateCard("2022-02", time.Time{}),
+			mockBehaviur: func(r *mock_repositories.MockRateCardRepository) {
+				r.EXPECT().CreateRateCard(gomock.Any()).DoAndReturn(func(card models.RateCard) error {
+					require.False(t, card.EffectiveFrom.IsZero())
+					return nil
+				})
+			},
+			expectedVersion: "2022-02",
+			expectedActive:  "2022-02",
+		},
+		{
+			name:   "OK effective later",
+			stored: true,
+			card:   future,
+			mockBehaviur: func(r *mock_repositories.MockRateCardRepository) {
+				r.EXPECT().CreateRateCard(future).Return(nil)
+			},
+			expectedVersion: "2099-01",
+			expectedActive:  "2022-01",
+		},
+		{
+			name:   "OK older than active",
+			stored: true,
+			card:   older,
+			mockBehaviur: func(r *mock_repositories.MockRateCardRepository) {
+				r.EXPECT().CreateRateCard(older).Return(nil)
+			},
+			expectedVersion: "2021-12",
+			expectedActive:  "2022-01",
+		},
+		{
+			name:           "invalid rate card",
+			stored:         true,
+			card:           invalid,
+			mockBehaviur:   func(r *mock_repositories.MockRateCardRepository) {},
+			expectedActive: "2022-01",
+			expectedError:  ErrorInvalidRateCard,
+		},
+		{
+			name:   "version exists",
+			stored: true,
+			card:   future,
+			mockBehaviur: func(r *mock_repositories.MockRateCardRepository) {
+				r.EXPECT().CreateRateCard(future).Return(repositories.ErrorRateCardVersionExists)
+			},
+			expectedActive: "2022-01",
+			expectedError:  repositories.ErrorRateCardVersionExists,
+		},
+		{
+			name:           "rate cards are not stored",
+			card:           future,
+			mockBehaviur:   func(r *mock_repositories.MockRateCardRepository) {},
+			expectedActive: "2022-01",
+			expectedError:  ErrorRateCardsNotStored,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockRateCardRepository(c)
+			tC.mockBehaviur(repo)
+
+			engine, err := pricing.InitEngine(active)
+			require.NoError(t, err)
+			switchable := pricing.InitSwitchableEngine(engine)
+
+			service := InitRateCardService(repo, switchable, tC.stored)
+
+			rateCard, err := service.CreateRateCard(tC.card)
+			require.ErrorIs(t, err, tC.expectedError)
+			require.Equal(t, tC.expectedVersion, rateCard.Version)
+			require.Equal(t, tC.expectedActive, service.GetActiveRateCard().Version)
+		})
+	}
+}
+
+func TestService_RefreshRateCard(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockRateCardRepository)
+
+	active := testRateCard("2022-01", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name           string
+		stored         bool
+		mockBehaviur   mockBehaviur
+		expectedActive string
+		expectedError  error
+	}{
+		{
+			name:   "new version",
+			stored: true,
+			mockBehaviur: func(r *mock_repositories.MockRateCardRepository) {
+				r.EXPECT().GetActiveRateCard(gomock.Any()).Return(testRateCard("2022-02", time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)), nil)
+			},
+			expectedActive: "2022-02",
+		},
+		{
+			name:   "same version",
+			stored: true,
+			mockBehaviur: func(r *mock_repositories.MockRateCardRepository) {
+				r.EXPECT().GetActiveRateCard(gomock.Any()).Return(active, nil)
+			},
+			expectedActive: "2022-01",
+		},
+		{
+			name:           "rate cards are not stored",
+			mockBehaviur:   func(r *mock_repositories.MockRateCardRepository) {},
+			expectedActive: "2022-01",
+		},
+		{
+			name:   "some db error",
+			stored: true,
+			mockBehaviur: func(r *mock_repositories.MockRateCardRepository) {
+				r.EXPECT().GetActiveRateCard(gomock.Any()).Return(models.RateCard{}, errors.New("some db error"))
+			},
+			expectedActive: "2022-01",
+			expectedError:  errors.New("some db error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockRateCardRepository(c)
+			tC.mockBehaviur(repo)
+
+			engine, err := pricing.InitEngine(active)
+			require.NoError(t, err)
+
+			service := InitRateCardService(repo, pricing.InitSwitchableEngine(engine), tC.stored)
+
+			err = service.RefreshRateCard()
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedActive, service.GetActiveRateCard().Version)
+		})
+	}
+}
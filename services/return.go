@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Taras-Rm/shipment/models"
+)
+
+var ErrorReturnNotAllowed error = errors.New("shipment can't be returned")
+
+// create reverse shipment of the delivered one (priced by the returns rate)
+func (s *shipmentService) ReturnShipment(id uint) (models.Shipment, error) {
+	original, err := s.shipmentRepository.GetShipmentByID(id)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	if original.ReturnOf != nil {
+		return models.Shipment{}, fmt.Errorf("%w: it is a return shipment itself", ErrorReturnNotAllowed)
+	}
+	if original.Status != models.StatusDelivered {
+		return models.Shipment{}, fmt.Errorf("%w in status %q", ErrorReturnNotAllowed, original.Status)
+	}
+
+	// the same parcels go back to the sender
+	inp := shipmentInput(original)
+	inp.FromName, inp.ToName = inp.ToName, inp.FromName
+	inp.FromEmail, inp.ToEmail = inp.ToEmail, inp.FromEmail
+	inp.FromAddress, inp.ToAddress = inp.ToAddress, inp.FromAddress
+	inp.FromCountryCode, inp.ToCountryCode = inp.ToCountryCode, inp.FromCountryCode
+
+	breakdown, pieces, err := s.priceReturn(inp)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	trackingNumber, err := s.newTrackingNumber()
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	link := models.ShipmentLink{
+		Id:             original.Id,
+		TrackingNumber: original.TrackingNumber,
+		Status:         original.Status,
+	}
+	shipment := newShipment(inp, breakdown, pieces)
+	shipment.TrackingNumber = trackingNumber
+	shipment.ReturnOf = &link
+
+	created, err := s.shipmentRepository.CreateShipment(shipment)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+	created.ReturnOf = &link
+
+	return created, nil
+}
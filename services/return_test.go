@@ -0,0 +1,148 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ReturnShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockShipmentRepository)
+
+	original := models.Shipment{
+		Id:              1,
+		TrackingNumber:  "SH123456785UA",
+		FromName:        "Mark",
+		FromEmail:       "testFrom@g.c",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "CA",
+		Weight:          234.4,
+		Price:           money.New(500000, "EUR"),
+		Status:          models.StatusDelivered,
+		PriceBreakdown:  &uaToCaBreakdown,
+		Pieces:          []models.Piece{{Weight: 234.4, BillableWeight: 234.4, Price: money.New(500000, "EUR")}},
+	}
+	link := models.ShipmentLink{Id: 1, TrackingNumber: "SH123456785UA", Status: models.StatusDelivered}
+
+	// reverse shipment is priced by the returns rate of the default rate card
+	reverse := models.Shipment{
+		TrackingNumber:  testTrackingNumber,
+		FromName:        "Iryna",
+		FromEmail:       "testTo@g.c",
+		FromAddress:     "Toronto, 34",
+		FromCountryCode: "CA",
+		ToName:          "Mark",
+		ToEmail:         "testFrom@g.c",
+		ToAddress:       "Lviv, 45",
+		ToCountryCode:   "UA",
+		Weight:          234.4,
+		Price:           money.New(400000, "EUR"),
+		Status:          models.StatusCreated,
+		PriceBreakdown: &models.PriceBreakdown{
+			RateCardVersion: "default",
+			Lane:            models.Lane{Type: models.LaneIntercontinental, Origin: "world", Destination: "europe", Factor: 2.5},
+			BillableWeight:  234.4,
+			Components: []models.PriceComponent{
+				{Type: models.PriceComponentBase, Name: "weight class", Amount: money.New(200000, "EUR")},
+				{Type: models.PriceComponentLane, Name: "intercontinental", Factor: 2.5, Amount: money.New(300000, "EUR")},
+				{Type: models.PriceComponentReturns, Name: "returns", Factor: 0.8, Amount: money.New(-100000, "EUR")},
+			},
+			Total: money.New(400000, "EUR"),
+		},
+		Pieces:   []models.Piece{{Weight: 234.4, BillableWeight: 234.4, Price: money.New(400000, "EUR")}},
+		ReturnOf: &link,
+	}
+
+	inTransit := original
+	inTransit.Status = models.StatusInTransit
+
+	returned := original
+	returned.ReturnOf = &models.ShipmentLink{Id: 7}
+
+	testCases := []struct {
+		name          string
+		mockBehaviur  mockBehaviur
+		expectedPrice money.Money
+		expectedError error
+	}{
+		{
+			name: "Ok",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				created := reverse
+				created.Id = 2
+				created.ReturnOf = &models.ShipmentLink{Id: 1}
+
+				r.EXPECT().GetShipmentByID(uint(1)).Return(original, nil)
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipment(reverse).Return(created, nil)
+			},
+			expectedPrice: money.New(400000, "EUR"),
+		},
+		{
+			name: "not delivered yet",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(inTransit, nil)
+			},
+			expectedError: ErrorReturnNotAllowed,
+		},
+		{
+			name: "return of the return",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(returned, nil)
+			},
+			expectedError: ErrorReturnNotAllowed,
+		},
+		{
+			name: "shipment not found",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedError: repositories.ErrorShipmentNotFound,
+		},
+		{
+			name: "some internal error",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(original, nil)
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipment(reverse).Return(models.Shipment{}, errors.New("some internal error"))
+			},
+			expectedError: errors.New("some internal error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockShipmentRepository(c)
+			tC.mockBehaviur(repo)
+
+			service := initTestService(t, repo)
+
+			shipment, err := service.ReturnShipment(1)
+			if tC.expectedError != nil {
+				if !errors.Is(err, tC.expectedError) {
+					require.Equal(t, tC.expectedError, err)
+				}
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, uint(2), shipment.Id)
+			require.Equal(t, tC.expectedPrice, shipment.Price)
+			require.Equal(t, &link, shipment.ReturnOf)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+)
+
+type SearchShipmentsInput struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit"`
+}
+
+const (
+	// max length of the search text
+	maxSearchLength = 200
+	// max number of words which are searched
+	maxSearchTerms = 10
+
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+func (i SearchShipmentsInput) Validate() error {
+	_, err := i.Query()
+	return err
+}
+
+// build search query from the search request
+func (i SearchShipmentsInput) Query() (models.SearchQuery, error) {
+	query := models.SearchQuery{Limit: i.Limit}
+
+	// check page size
+	if query.Limit == 0 {
+		query.Limit = defaultSearchLimit
+	}
+	if query.Limit < 0 || query.Limit > maxSearchLimit {
+		return models.SearchQuery{}, errors.New("invalid limit")
+	}
+
+	// check search text (every word is searched once)
+	if len(i.Q) > maxSearchLength {
+		return models.SearchQuery{}, errors.New("invalid search query")
+	}
+	seen := map[string]bool{}
+	for _, word := range helpers.SearchWords(i.Q) {
+		if !seen[word] {
+			seen[word] = true
+			query.Terms = append(query.Terms, word)
+		}
+	}
+	if len(query.Terms) == 0 || len(query.Terms) > maxSearchTerms {
+		return models.SearchQuery{}, errors.New("invalid search query")
+	}
+
+	return query, nil
+}
+
+// find shipments by names, emails and addresses
+func (s *shipmentService) SearchShipments(inp SearchShipmentsInput) ([]models.SearchResult, error) {
+	query, err := inp.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.shipmentSearch.SearchShipments(query)
+}
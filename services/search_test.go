@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchShipmentsInput_Query(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         SearchShipmentsInput
+		expectedQuery models.SearchQuery
+		expectedError error
+	}{
+		{
+			name:          "default limit",
+			input:         SearchShipmentsInput{Q: "Mark Lviv"},
+			expectedQuery: models.SearchQuery{Terms: []string{"mark", "lviv"}, Limit: 20},
+		},
+		{
+			name:          "repeated words and punctuation",
+			input:         SearchShipmentsInput{Q: "  mark, MARK; testFrom@g.c ", Limit: 5},
+			expectedQuery: models.SearchQuery{Terms: []string{"mark", "testfrom@g.c"}, Limit: 5},
+		},
+		{
+			name:          "without words",
+			input:         SearchShipmentsInput{Q: " ,.- "},
+			expectedError: errors.New("invalid search query"),
+		},
+		{
+			name:          "too long text",
+			input:         SearchShipmentsInput{Q: strings.Repeat("a", 201)},
+			expectedError: errors.New("invalid search query"),
+		},
+		{
+			name:          "too many words",
+			input:         SearchShipmentsInput{Q: "a b c d e f g h i j k"},
+			expectedError: errors.New("invalid search query"),
+		},
+		{
+			name:          "too big limit",
+			input:         SearchShipmentsInput{Q: "mark", Limit: 101},
+			expectedError: errors.New("invalid limit"),
+		},
+		{
+			name:          "negative limit",
+			input:         SearchShipmentsInput{Q: "mark", Limit: -1},
+			expectedError: errors.New("invalid limit"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			query, err := tC.input.Query()
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedQuery, query)
+		})
+	}
+}
+
+func TestService_SearchShipments(t *testing.T) {
+	shipments := []models.Shipment{
+		{Id: 1, FromName: "Mark", FromAddress: "Lviv, 45", ToName: "Iryna", ToAddress: "Toronto, 34"},
+		{Id: 2, FromName: "Tom", FromAddress: "Lutsk, 34", ToName: "Mark", ToAddress: "Lviv, 12"},
+		{Id: 3, FromName: "Viktor", FromAddress: "London, 32", ToName: "Anna", ToAddress: "Kyiv, 1"},
+	}
+
+	testCases := []struct {
+		name          string
+		input         SearchShipmentsInput
+		expectedIds   []uint
+		expectedError error
+	}{
+		{
+			name:        "the best matches first",
+			input:       SearchShipmentsInput{Q: "mark lviv"},
+			expectedIds: []uint{1, 2},
+		},
+		{
+			name:        "limited results",
+			input:       SearchShipmentsInput{Q: "mark", Limit: 1},
+			expectedIds: []uint{1},
+		},
+		{
+			name:        "nothing found",
+			input:       SearchShipmentsInput{Q: "paris"},
+			expectedIds: []uint{},
+		},
+		{
+			name:          "invalid query",
+			input:         SearchShipmentsInput{Q: "!!!"},
+			expectedError: errors.New("invalid search query"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+			service := initTestServiceWithSearch(t, shipmentRepo, nil, repositories.InitMemoryShipmentSearch(shipments))
+
+			results, err := service.SearchShipments(tC.input)
+			require.Equal(t, tC.expectedError, err)
+			if err != nil {
+				return
+			}
+
+			ids := []uint{}
+			for _, result := range results {
+				ids = append(ids, result.Shipment.Id)
+			}
+			require.Equal(t, tC.expectedIds, ids)
+		})
+	}
+}
@@ -1,100 +1,227 @@
 package services
 
 import (
-	"errors"
+	"fmt"
+	"io"
+	"time"
 
+	"github.com/Taras-Rm/shipment/fx"
 	"github.com/Taras-Rm/shipment/helpers"
 	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/pricing"
 	"github.com/Taras-Rm/shipment/repositories"
+	"github.com/Taras-Rm/shipment/tracking"
 )
 
 type AddShipmentInput struct {
-	FromName        string  `json:"fromName" binding:"required"`
-	FromEmail       string  `json:"fromEmail" binding:"required"`
-	FromAddress     string  `json:"fromAddress" binding:"required"`
-	FromCountryCode string  `json:"fromCountryCode" binding:"required"`
-	ToName          string  `json:"toName" binding:"required"`
-	ToEmail         string  `json:"toEmail" binding:"required"`
-	ToAddress       string  `json:"toAddress" binding:"required"`
-	ToCountryCode   string  `json:"toCountryCode" binding:"required"`
-	Weight          float64 `json:"weight" binding:"required"`
+	FromName        string       `json:"fromName"`
+	FromEmail       string       `json:"fromEmail"`
+	FromAddress     string       `json:"fromAddress"`
+	FromCountryCode string       `json:"fromCountryCode"`
+	ToName          string       `json:"toName"`
+	ToEmail         string       `json:"toEmail"`
+	ToAddress       string       `json:"toAddress"`
+	ToCountryCode   string       `json:"toCountryCode"`
+	Weight          float64      `json:"weight"`
+	Length          float64      `json:"length"`
+	Width           float64      `json:"width"`
+	Height          float64      `json:"height"`
+	Pieces          []PieceInput `json:"pieces"`
+	Currency        string       `json:"currency"`
 }
 
+// parcel of multi-parcel shipment
+type PieceInput struct {
+	Weight float64 `json:"weight"`
+	Length float64 `json:"length"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+const (
+	// max weight of a parcel (kg)
+	maxWeight = 1000
+	// max length of a parcel side (cm)
+	maxDimension = 300
+	// max number of parcels in a shipment
+	maxPieces = 50
+)
+
+// check all fields (the error lists every invalid field)
 func (i AddShipmentInput) Validate() error {
-	// check email
-	fromErr := helpers.ValidateEmail(i.FromEmail)
-	toErr := helpers.ValidateEmail(i.ToEmail)
-	if fromErr != nil || toErr != nil {
-		return errors.New("invalid email format")
-	}
+	errs := &ValidationError{}
+
+	// check sender and recipient
+	errs.check("fromName", i.FromName, helpers.ValidateName)
+	errs.check("fromEmail", i.FromEmail, helpers.ValidateEmail)
+	errs.check("fromAddress", i.FromAddress, helpers.ValidateAddress)
+	errs.check("fromCountryCode", i.FromCountryCode, helpers.ValidateCountryCode)
+	errs.check("toName", i.ToName, helpers.ValidateName)
+	errs.check("toEmail", i.ToEmail, helpers.ValidateEmail)
+	errs.check("toAddress", i.ToAddress, helpers.ValidateAddress)
+	errs.check("toCountryCode", i.ToCountryCode, helpers.ValidateCountryCode)
 
-	// check names
-	nameFromErr := helpers.ValidateName(i.FromName)
-	nameToErr := helpers.ValidateName(i.ToName)
-	if nameFromErr != nil || nameToErr != nil {
-		return errors.New("invalid name format")
+	// check weight and dimensions (of the shipment or of every piece)
+	if len(i.Pieces) == 0 {
+		validateParcel(errs, "", i.Weight, i.Length, i.Width, i.Height)
+	} else {
+		for _, field := range []struct {
+			path  string
+			value float64
+		}{{"weight", i.Weight}, {"length", i.Length}, {"width", i.Width}, {"height", i.Height}} {
+			if field.value != 0 {
+				errs.add(field.path, CodeNotAllowed, "must be given per piece")
+			}
+		}
+		if len(i.Pieces) > maxPieces {
+			errs.add("pieces", CodeTooManyPieces, "too many pieces")
+		}
+		for n, piece := range i.Pieces {
+			validateParcel(errs, fmt.Sprintf("pieces[%d].", n), piece.Weight, piece.Length, piece.Width, piece.Height)
+		}
 	}
 
-	// check country codes
-	codeErr := helpers.ValidateCountryCode(i.FromCountryCode)
-	if codeErr != nil {
-		return codeErr
+	// check currency (optional, rate card currency is used by default)
+	if i.Currency != "" {
+		errs.check("currency", i.Currency, helpers.ValidateCurrencyCode)
 	}
-	codeErr = helpers.ValidateCountryCode(i.ToCountryCode)
-	if codeErr != nil {
-		return codeErr
+
+	return errs.errorOrNil()
+}
+
+// check weight and dimensions of the parcel (paths of its fields start with the prefix)
+func validateParcel(errs *ValidationError, prefix string, weight, length, width, height float64) {
+	// check weight
+	switch {
+	case weight == 0:
+		errs.add(prefix+"weight", CodeRequired, "is required")
+	case weight < 0 || weight > maxWeight:
+		errs.add(prefix+"weight", CodeInvalidWeight, "invalid weight")
 	}
 
-	// check addresses
-	addressFromErr := helpers.ValidateAddress(i.FromAddress)
-	addressToErr := helpers.ValidateAddress(i.ToAddress)
-	if addressFromErr != nil || addressToErr != nil {
-		return errors.New("invalid address format")
+	// check dimensions (optional, all three are required for volumetric weight)
+	if length != 0 || width != 0 || height != 0 {
+		for _, dimension := range []struct {
+			path  string
+			value float64
+		}{{"length", length}, {"width", width}, {"height", height}} {
+			if dimension.value <= 0 || dimension.value > maxDimension {
+				errs.add(prefix+dimension.path, CodeInvalidDimensions, "invalid dimensions")
+			}
+		}
 	}
+}
 
-	// check weight
-	if i.Weight <= 0 || i.Weight > 1000 {
-		return errors.New("invalid weight")
+// parcels of the shipment (a shipment without pieces is a single parcel)
+func (i AddShipmentInput) Parcels() []models.Parcel {
+	if len(i.Pieces) == 0 {
+		return []models.Parcel{{Weight: i.Weight, Length: i.Length, Width: i.Width, Height: i.Height}}
 	}
 
-	return nil
+	parcels := make([]models.Parcel, 0, len(i.Pieces))
+	for _, piece := range i.Pieces {
+		parcels = append(parcels, models.Parcel{Weight: piece.Weight, Length: piece.Length, Width: piece.Width, Height: piece.Height})
+	}
+	return parcels
 }
 
 //go:generate mockgen -source=shipment.go -destination=mocks/shipment.go
 type ShipmentService interface {
-	GetAllShipments() ([]models.Shipment, error)
-	AddShipment(inp AddShipmentInput) (float64, error)
+	ListShipments(inp ListShipmentsInput) (models.ShipmentPage, error)
+	ExportShipments(inp ListShipmentsInput, fn func(shipment models.Shipment) error) error
+	AddShipment(inp AddShipmentInput) (models.Shipment, error)
+	AddShipments(inp AddShipmentsInput, atomic bool) ([]models.ShipmentBatchResult, error)
+	ImportShipments(r io.Reader) (models.ImportResult, error)
+	UpdateShipment(id uint, inp AddShipmentInput) (models.Shipment, error)
+	PatchShipment(id uint, inp PatchShipmentInput) (models.Shipment, error)
 	GetShipmentByID(id uint) (models.Shipment, error)
+	GetShipmentByTrackingNumber(number string) (models.Shipment, error)
+	GetLane(fromCountryCode, toCountryCode string) (models.Lane, error)
+	Quote(inp AddShipmentInput) (models.PriceBreakdown, []models.Piece, error)
+	ConvertShipment(shipment models.Shipment, currency string) (models.Shipment, models.FxRate, error)
+	TransitionShipment(id uint, status models.ShipmentStatus) (models.Shipment, error)
+	CancelShipment(id uint) (models.Shipment, error)
+	ReturnShipment(id uint) (models.Shipment, error)
+	AddTrackingEvent(id uint, inp AddTrackingEventInput) (models.TrackingEvent, error)
+	GetTrackingEvents(id uint) ([]models.TrackingEvent, error)
+	TrackShipment(number string) (models.Shipment, []models.TrackingEvent, error)
+	SearchShipments(inp SearchShipmentsInput) ([]models.SearchResult, error)
+	WithOwner(owner string) ShipmentService
+	WithTenant(tenantID string, pricingEngine pricing.Engine) ShipmentService
 }
 
 type shipmentService struct {
-	shipmentRepository repositories.ShipmentRepository
+	shipmentRepository      repositories.ShipmentRepository
+	trackingEventRepository repositories.TrackingEventRepository
+	shipmentSearch          repositories.ShipmentSearchRepository
+	pricingEngine           pricing.Engine
+	fxConverter             fx.Converter
+	trackingNumbers         tracking.Generator
+	policy                  ShipmentPolicy
+	// shipments of all owners and tenants (tracking numbers are unique across them)
+	allShipments repositories.ShipmentRepository
+}
+
+func InitShipmentService(shipmentRepo repositories.ShipmentRepository, trackingEventRepo repositories.TrackingEventRepository, shipmentSearch repositories.ShipmentSearchRepository, pricingEngine pricing.Engine, fxConverter fx.Converter, trackingNumbers tracking.Generator, policy ShipmentPolicy) ShipmentService {
+	return &shipmentService{
+		shipmentRepository:      shipmentRepo,
+		trackingEventRepository: trackingEventRepo,
+		shipmentSearch:          shipmentSearch,
+		pricingEngine:           pricingEngine,
+		fxConverter:             fxConverter,
+		trackingNumbers:         trackingNumbers,
+		policy:                  policy,
+		allShipments:            shipmentRepo,
+	}
+}
+
+// service which sees and creates only the shipments of the owner
+func (s *shipmentService) WithOwner(owner string) ShipmentService {
+	scoped := *s
+	scoped.shipmentRepository = s.shipmentRepository.WithOwner(owner)
+	scoped.shipmentSearch = s.shipmentSearch.WithOwner(owner)
+	return &scoped
 }
 
-func InitShipmentService(shipmentRepo repositories.ShipmentRepository) ShipmentService {
-	return &shipmentService{shipmentRepository: shipmentRepo}
+// service which sees and creates only the shipments of the tenant and prices them by its rate card (if it has one)
+func (s *shipmentService) WithTenant(tenantID string, pricingEngine pricing.Engine) ShipmentService {
+	scoped := *s
+	scoped.shipmentRepository = s.shipmentRepository.WithTenant(tenantID)
+	scoped.shipmentSearch = s.shipmentSearch.WithTenant(tenantID)
+	if pricingEngine != nil {
+		scoped.pricingEngine = pricingEngine
+	}
+	return &scoped
 }
 
-func (s *shipmentService) GetAllShipments() ([]models.Shipment, error) {
-	shipments, err := s.shipmentRepository.GetAllShipments()
+func (s *shipmentService) AddShipment(inp AddShipmentInput) (models.Shipment, error) {
+	// calculate price by the rate card
+	breakdown, pieces, err := s.price(inp)
 	if err != nil {
-		return nil, err
+		return models.Shipment{}, err
 	}
 
-	return shipments, nil
-}
+	trackingNumber, err := s.newTrackingNumber()
+	if err != nil {
+		return models.Shipment{}, err
+	}
 
-func (s *shipmentService) AddShipment(inp AddShipmentInput) (float64, error) {
-	// determine Region Rules
-	regionFactor := helpers.RegionRulesFactor(inp.FromCountryCode)
+	shipment := newShipment(inp, breakdown, pieces)
+	shipment.TrackingNumber = trackingNumber
 
-	// determine Weight Class Rules
-	weightFactor := helpers.WeightClassRulesFactor(inp.Weight)
+	// add the new shipment to the database
+	return s.shipmentRepository.CreateShipment(shipment)
+}
 
-	// calculate price
-	price := regionFactor * float64(weightFactor)
+// new shipment of the priced input
+func newShipment(inp AddShipmentInput, breakdown models.PriceBreakdown, pieces []models.Piece) models.Shipment {
+	// weight of the shipment is the total weight of its pieces
+	var weight float64
+	for _, piece := range pieces {
+		weight += piece.Weight
+	}
 
-	shipment := models.Shipment{
+	return models.Shipment{
 		FromName:        inp.FromName,
 		FromEmail:       inp.FromEmail,
 		FromAddress:     inp.FromAddress,
@@ -103,17 +230,16 @@ func (s *shipmentService) AddShipment(inp AddShipmentInput) (float64, error) {
 		ToEmail:         inp.ToEmail,
 		ToAddress:       inp.ToAddress,
 		ToCountryCode:   inp.ToCountryCode,
-		Weight:          inp.Weight,
-		Price:           price,
-	}
-
-	// add the new shipment to the database
-	err := s.shipmentRepository.CreateShipment(shipment)
-	if err != nil {
-		return 0, err
+		Weight:          weight,
+		Length:          inp.Length,
+		Width:           inp.Width,
+		Height:          inp.Height,
+		Price:           breakdown.Total,
+		Status:          models.StatusCreated,
+		PriceBreakdown:  &breakdown,
+		FxRate:          breakdown.FxRate,
+		Pieces:          pieces,
 	}
-
-	return price, nil
 }
 
 func (s *shipmentService) GetShipmentByID(id uint) (models.Shipment, error) {
@@ -124,3 +250,77 @@ func (s *shipmentService) GetShipmentByID(id uint) (models.Shipment, error) {
 
 	return shipment, nil
 }
+
+// get a single shipment by it's tracking number (spaces and case are ignored)
+func (s *shipmentService) GetShipmentByTrackingNumber(number string) (models.Shipment, error) {
+	number = tracking.Normalize(number)
+	if err := tracking.Validate(number); err != nil {
+		return models.Shipment{}, err
+	}
+
+	return s.shipmentRepository.GetShipmentByTrackingNumber(number)
+}
+
+// get the pricing lane (zone pair and factor) between two countries
+func (s *shipmentService) GetLane(fromCountryCode, toCountryCode string) (models.Lane, error) {
+	if err := helpers.ValidateCountryCode(fromCountryCode); err != nil {
+		return models.Lane{}, err
+	}
+	if err := helpers.ValidateCountryCode(toCountryCode); err != nil {
+		return models.Lane{}, err
+	}
+
+	return s.pricingEngine.Lane(fromCountryCode, toCountryCode), nil
+}
+
+// calculate price of the shipment without saving it
+func (s *shipmentService) Quote(inp AddShipmentInput) (models.PriceBreakdown, []models.Piece, error) {
+	if err := inp.Validate(); err != nil {
+		return models.PriceBreakdown{}, nil, err
+	}
+
+	return s.price(inp)
+}
+
+// show shipment amounts in another currency (by the current fx rate)
+func (s *shipmentService) ConvertShipment(shipment models.Shipment, currency string) (models.Shipment, models.FxRate, error) {
+	if err := helpers.ValidateCurrencyCode(currency); err != nil {
+		return models.Shipment{}, models.FxRate{}, err
+	}
+
+	price, rate, err := s.fxConverter.Convert(shipment.Price, currency, time.Now())
+	if err != nil {
+		return models.Shipment{}, models.FxRate{}, err
+	}
+	shipment.Price = price
+
+	// converted components have to sum up to the converted price
+	if shipment.PriceBreakdown != nil {
+		breakdown := convertBreakdown(*shipment.PriceBreakdown, rate)
+		breakdown.FxRate = shipment.FxRate
+		shipment.PriceBreakdown = &breakdown
+		shipment.Price = breakdown.Total
+	}
+
+	pieces := make([]models.Piece, 0, len(shipment.Pieces))
+	for _, piece := range shipment.Pieces {
+		piece.Price = piece.Price.Convert(rate.To, rate.Rate)
+		pieces = append(pieces, piece)
+	}
+	if len(pieces) > 0 {
+		shipment.Pieces = pieces
+	}
+
+	// fee and refund have to sum up to the converted price
+	if shipment.Cancellation != nil {
+		cancellation := *shipment.Cancellation
+		cancellation.Fee = cancellation.Fee.Convert(rate.To, rate.Rate)
+		cancellation.Refund, err = shipment.Price.Sub(cancellation.Fee)
+		if err != nil {
+			return models.Shipment{}, models.FxRate{}, err
+		}
+		shipment.Cancellation = &cancellation
+	}
+
+	return shipment, rate, nil
+}
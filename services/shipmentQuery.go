@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+)
+
+type ListShipmentsInput struct {
+	Cursor          string    `form:"cursor"`
+	Limit           int       `form:"limit"`
+	Sort            string    `form:"sort"`
+	FromCountryCode string    `form:"fromCountryCode"`
+	ToCountryCode   string    `form:"toCountryCode"`
+	MinWeight       float64   `form:"minWeight"`
+	MaxWeight       float64   `form:"maxWeight"`
+	MinPrice        string    `form:"minPrice"`
+	MaxPrice        string    `form:"maxPrice"`
+	PriceCurrency   string    `form:"priceCurrency"`
+	CreatedFrom     time.Time `form:"createdFrom"`
+	CreatedTo       time.Time `form:"createdTo"`
+	Email           string    `form:"email"`
+}
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+	// number of shipments which are read from the database at once by the export
+	exportPageLimit = 500
+)
+
+// fields which shipments may be sorted by ("-" prefix is for descending order)
+var shipmentSortFields = map[string]models.ShipmentSortField{
+	"id":        models.SortByID,
+	"createdAt": models.SortByCreatedAt,
+	"weight":    models.SortByWeight,
+	"price":     models.SortByPrice,
+}
+
+func (i ListShipmentsInput) Validate() error {
+	_, err := i.Query()
+	return err
+}
+
+// build repository query from the list request
+func (i ListShipmentsInput) Query() (models.ShipmentQuery, error) {
+	query := models.ShipmentQuery{Cursor: i.Cursor, Limit: i.Limit, SortBy: models.SortByID}
+
+	// check page size
+	if query.Limit == 0 {
+		query.Limit = defaultPageLimit
+	}
+	if query.Limit < 0 || query.Limit > maxPageLimit {
+		return models.ShipmentQuery{}, errors.New("invalid limit")
+	}
+
+	// check sort
+	if i.Sort != "" {
+		field, ok := shipmentSortFields[strings.TrimPrefix(i.Sort, "-")]
+		if !ok {
+			return models.ShipmentQuery{}, errors.New("invalid sort")
+		}
+		query.SortBy = field
+		query.Desc = strings.HasPrefix(i.Sort, "-")
+	}
+
+	// check country codes
+	for _, code := range []string{i.FromCountryCode, i.ToCountryCode} {
+		if code == "" {
+			continue
+		}
+		if err := helpers.ValidateCountryCode(code); err != nil {
+			return models.ShipmentQuery{}, err
+		}
+	}
+	query.Filter.FromCountryCode = i.FromCountryCode
+	query.Filter.ToCountryCode = i.ToCountryCode
+
+	// check weight range
+	if i.MinWeight < 0 || i.MaxWeight < 0 || (i.MaxWeight != 0 && i.MinWeight > i.MaxWeight) {
+		return models.ShipmentQuery{}, errors.New("invalid weight range")
+	}
+	query.Filter.MinWeight = i.MinWeight
+	query.Filter.MaxWeight = i.MaxWeight
+
+	// check price range (prices are compared in the given currency only)
+	if i.MinPrice != "" || i.MaxPrice != "" {
+		if err := helpers.ValidateCurrencyCode(i.PriceCurrency); err != nil {
+			return models.ShipmentQuery{}, err
+		}
+		var err error
+		if query.Filter.MinPrice, err = parsePriceBound(i.MinPrice, i.PriceCurrency); err != nil {
+			return models.ShipmentQuery{}, err
+		}
+		if query.Filter.MaxPrice, err = parsePriceBound(i.MaxPrice, i.PriceCurrency); err != nil {
+			return models.ShipmentQuery{}, err
+		}
+		if query.Filter.MinPrice != nil && query.Filter.MaxPrice != nil && query.Filter.MinPrice.Amount > query.Filter.MaxPrice.Amount {
+			return models.ShipmentQuery{}, errors.New("invalid price range")
+		}
+	}
+
+	// check created date range
+	if !i.CreatedFrom.IsZero() && !i.CreatedTo.IsZero() && !i.CreatedFrom.Before(i.CreatedTo) {
+		return models.ShipmentQuery{}, errors.New("invalid date range")
+	}
+	query.Filter.CreatedFrom = i.CreatedFrom
+	query.Filter.CreatedTo = i.CreatedTo
+
+	// check email
+	if i.Email != "" {
+		if err := helpers.ValidateEmail(i.Email); err != nil {
+			return models.ShipmentQuery{}, errors.New("invalid email format")
+		}
+		query.Filter.Email = i.Email
+	}
+
+	return query, nil
+}
+
+// parse optional bound of the price range
+func parsePriceBound(value, currency string) (*money.Money, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	price, err := money.Parse(value, currency)
+	if err != nil || price.Amount < 0 {
+		return nil, errors.New("invalid price range")
+	}
+	return &price, nil
+}
+
+// get a page of the shipments which match the filters
+func (s *shipmentService) ListShipments(inp ListShipmentsInput) (models.ShipmentPage, error) {
+	query, err := inp.Query()
+	if err != nil {
+		return models.ShipmentPage{}, err
+	}
+
+	return s.shipmentRepository.ListShipments(query)
+}
+
+// call fn for every shipment which matches the filters in the order of the sort (page size is not used)
+func (s *shipmentService) ExportShipments(inp ListShipmentsInput, fn func(shipment models.Shipment) error) error {
+	inp.Limit = 0
+	query, err := inp.Query()
+	if err != nil {
+		return err
+	}
+	query.Limit = exportPageLimit
+
+	return s.shipmentRepository.EachShipment(query, fn)
+}
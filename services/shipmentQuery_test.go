@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListShipmentsInput_Query(t *testing.T) {
+	minPrice := money.New(5000, "EUR")
+	maxPrice := money.New(100050, "EUR")
+
+	testCases := []struct {
+		name          string
+		input         ListShipmentsInput
+		expectedQuery models.ShipmentQuery
+		expectedError error
+	}{
+		{
+			name:          "defaults",
+			input:         ListShipmentsInput{},
+			expectedQuery: models.ShipmentQuery{SortBy: models.SortByID, Limit: 50},
+		},
+		{
+			name: "all filters",
+			input: ListShipmentsInput{
+				Cursor:          "abc",
+				Limit:           10,
+				Sort:            "-createdAt",
+				FromCountryCode: "UA",
+				ToCountryCode:   "CA",
+				MinWeight:       1,
+				MaxWeight:       20,
+				MinPrice:        "50",
+				MaxPrice:        "1000.50",
+				PriceCurrency:   "EUR",
+				CreatedFrom:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedTo:       time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+				Email:           "testTo@g.c",
+			},
+			expectedQuery: models.ShipmentQuery{
+				Filter: models.ShipmentFilter{
+					FromCountryCode: "UA",
+					ToCountryCode:   "CA",
+					MinWeight:       1,
+					MaxWeight:       20,
+					MinPrice:        &minPrice,
+					MaxPrice:        &maxPrice,
+					CreatedFrom:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+					CreatedTo:       time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+					Email:           "testTo@g.c",
+				},
+				SortBy: models.SortByCreatedAt,
+				Desc:   true,
+				Cursor: "abc",
+				Limit:  10,
+			},
+		},
+		{
+			name:          "ascending sort by weight",
+			input:         ListShipmentsInput{Sort: "weight"},
+			expectedQuery: models.ShipmentQuery{SortBy: models.SortByWeight, Limit: 50},
+		},
+		{
+			name:          "too big limit",
+			input:         ListShipmentsInput{Limit: 201},
+			expectedError: errors.New("invalid limit"),
+		},
+		{
+			name:          "unknown sort field",
+			input:         ListShipmentsInput{Sort: "-fromName"},
+			expectedError: errors.New("invalid sort"),
+		},
+		{
+			name:          "invalid country code",
+			input:         ListShipmentsInput{ToCountryCode: "QQ"},
+			expectedError: helpers.ErrorNotExistingCountryCode,
+		},
+		{
+			name:          "min weight is bigger than max weight",
+			input:         ListShipmentsInput{MinWeight: 20, MaxWeight: 10},
+			expectedError: errors.New("invalid weight range"),
+		},
+		{
+			name:          "price without currency",
+			input:         ListShipmentsInput{MinPrice: "50"},
+			expectedError: helpers.ErrorInvalidCurrencyCode,
+		},
+		{
+			name:          "not a price",
+			input:         ListShipmentsInput{MaxPrice: "1e3", PriceCurrency: "EUR"},
+			expectedError: errors.New("invalid price range"),
+		},
+		{
+			name:          "min price is bigger than max price",
+			input:         ListShipmentsInput{MinPrice: "100", MaxPrice: "50", PriceCurrency: "EUR"},
+			expectedError: errors.New("invalid price range"),
+		},
+		{
+			name: "empty date range",
+			input: ListShipmentsInput{
+				CreatedFrom: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+				CreatedTo:   time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedError: errors.New("invalid date range"),
+		},
+		{
+			name:          "invalid email",
+			input:         ListShipmentsInput{Email: "testTo"},
+			expectedError: errors.New("invalid email format"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			actual, err := tC.input.Query()
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedQuery, actual)
+		})
+	}
+}
+
+func TestService_ListShipments(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	page := models.ShipmentPage{
+		Shipments:  []models.Shipment{{Id: 2}},
+		NextCursor: "eyJzIjoiaWQiLCJpZCI6Mn0",
+		Total:      3,
+	}
+
+	repo := mock_repositories.NewMockShipmentRepository(c)
+	repo.EXPECT().ListShipments(models.ShipmentQuery{SortBy: models.SortByID, Limit: 1}).Return(page, nil)
+
+	service := initTestService(t, repo)
+
+	actual, err := service.ListShipments(ListShipmentsInput{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, page, actual)
+
+	// invalid input is not passed to the repository
+	_, err = service.ListShipments(ListShipmentsInput{Limit: -1})
+	require.Equal(t, errors.New("invalid limit"), err)
+}
+
+func TestService_ExportShipments(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// all pages are read with the same sort and filters whatever limit is given
+	query := models.ShipmentQuery{Filter: models.ShipmentFilter{ToCountryCode: "CA"}, SortBy: models.SortByWeight, Desc: true, Limit: exportPageLimit}
+
+	repo := mock_repositories.NewMockShipmentRepository(c)
+	repo.EXPECT().EachShipment(query, gomock.Any()).DoAndReturn(func(query models.ShipmentQuery, fn func(shipment models.Shipment) error) error {
+		for _, id := range []uint{3, 1} {
+			if err := fn(models.Shipment{Id: id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	service := initTestService(t, repo)
+
+	var exported []uint
+	err := service.ExportShipments(ListShipmentsInput{Limit: 1000, Sort: "-weight", ToCountryCode: "CA"}, func(shipment models.Shipment) error {
+		exported = append(exported, shipment.Id)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uint{3, 1}, exported)
+
+	// invalid input is not passed to the repository
+	err = service.ExportShipments(ListShipmentsInput{Sort: "name"}, func(shipment models.Shipment) error { return nil })
+	require.Equal(t, errors.New("invalid sort"), err)
+}
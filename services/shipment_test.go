@@ -1,15 +1,86 @@
 package services
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/Taras-Rm/shipment/fx"
+	"github.com/Taras-Rm/shipment/helpers"
 	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/Taras-Rm/shipment/pricing"
+	"github.com/Taras-Rm/shipment/repositories"
 	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/Taras-Rm/shipment/tracking"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
+// tracking number which is generated by the test service
+const testTrackingNumber = "SH169090604UA"
+
+// fx rates which are used by the service tests
+var testFxRates = []models.FxRate{
+	{From: "EUR", To: "SEK", Rate: 10.4567, EffectiveFrom: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+func initTestService(t *testing.T, shipmentRepo *mock_repositories.MockShipmentRepository) ShipmentService {
+	return initTestServiceWithEvents(t, shipmentRepo, nil)
+}
+
+func initTestServiceWithEvents(t *testing.T, shipmentRepo *mock_repositories.MockShipmentRepository, trackingEventRepo repositories.TrackingEventRepository) ShipmentService {
+	return initTestServiceWithSearch(t, shipmentRepo, trackingEventRepo, repositories.InitMemoryShipmentSearch(nil))
+}
+
+func initTestServiceWithSearch(t *testing.T, shipmentRepo *mock_repositories.MockShipmentRepository, trackingEventRepo repositories.TrackingEventRepository, shipmentSearch repositories.ShipmentSearchRepository) ShipmentService {
+	// the same serial number (16909060) is generated every time
+	trackingNumbers, err := tracking.InitGenerator("SH", "UA", bytes.NewReader(bytes.Repeat([]byte{1, 2, 3, 4}, 16)))
+	require.NoError(t, err)
+
+	return initTestServiceWithTracking(t, shipmentRepo, trackingEventRepo, shipmentSearch, trackingNumbers)
+}
+
+func initTestServiceWithTracking(t *testing.T, shipmentRepo *mock_repositories.MockShipmentRepository, trackingEventRepo repositories.TrackingEventRepository, shipmentSearch repositories.ShipmentSearchRepository, trackingNumbers tracking.Generator) ShipmentService {
+	pricingEngine, err := pricing.InitEngine(pricing.DefaultRateCard())
+	require.NoError(t, err)
+
+	fxConverter, err := fx.InitConverter(testFxRates)
+	require.NoError(t, err)
+
+	policy, err := InitShipmentPolicy(models.StatusCreated, models.StatusLabelPrinted, 20)
+	require.NoError(t, err)
+
+	return InitShipmentService(shipmentRepo, trackingEventRepo, shipmentSearch, pricingEngine, fxConverter, trackingNumbers, policy)
+}
+
+// breakdown of 234.4 kg shipment from UA to CA by the default rate card
+var uaToCaBreakdown = models.PriceBreakdown{
+	RateCardVersion: "default",
+	Lane:            models.Lane{Type: models.LaneIntercontinental, Origin: "europe", Destination: "world", Factor: 2.5},
+	BillableWeight:  234.4,
+	Components: []models.PriceComponent{
+		{Type: models.PriceComponentBase, Name: "weight class", Amount: money.New(200000, "EUR")},
+		{Type: models.PriceComponentLane, Name: "intercontinental", Factor: 2.5, Amount: money.New(300000, "EUR")},
+	},
+	Total: money.New(500000, "EUR"),
+}
+
+// the same breakdown in SEK
+var uaToCaBreakdownSEK = models.PriceBreakdown{
+	RateCardVersion: "default",
+	Lane:            models.Lane{Type: models.LaneIntercontinental, Origin: "europe", Destination: "world", Factor: 2.5},
+	BillableWeight:  234.4,
+	Components: []models.PriceComponent{
+		{Type: models.PriceComponentBase, Name: "weight class", Amount: money.New(2091340, "SEK")},
+		{Type: models.PriceComponentLane, Name: "intercontinental", Factor: 2.5, Amount: money.New(3137010, "SEK")},
+	},
+	Total:  money.New(5228350, "SEK"),
+	FxRate: &testFxRates[0],
+}
+
 func TestService_AddShipment(t *testing.T) {
 	type mockBehaviur func(r *mock_repositories.MockShipmentRepository, shipment models.Shipment)
 
@@ -18,7 +89,7 @@ func TestService_AddShipment(t *testing.T) {
 		input         AddShipmentInput
 		inputShipment models.Shipment
 		mockBehaviur  mockBehaviur
-		expectedPrice float64
+		expectedPrice money.Money
 		expectedError error
 	}{
 		{
@@ -35,6 +106,47 @@ func TestService_AddShipment(t *testing.T) {
 				Weight:          234.4,
 			},
 			inputShipment: models.Shipment{
+				TrackingNumber:  testTrackingNumber,
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+				Price:           money.New(500000, "EUR"),
+				Status:          models.StatusCreated,
+				PriceBreakdown:  &uaToCaBreakdown,
+				Pieces:          []models.Piece{{Weight: 234.4, BillableWeight: 234.4, Price: money.New(500000, "EUR")}},
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository, shipment models.Shipment) {
+				created := shipment
+				created.Id = 1
+
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipment(gomock.Eq(shipment)).Return(created, nil)
+			},
+			expectedPrice: money.New(500000, "EUR"),
+			expectedError: nil,
+		},
+		{
+			name: "Ok in requested currency",
+			input: AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+				Currency:        "SEK",
+			},
+			inputShipment: models.Shipment{
+				TrackingNumber:  testTrackingNumber,
 				FromName:        "Mark",
 				FromEmail:       "testFrom@g.c",
 				FromAddress:     "Lviv, 45",
@@ -44,14 +156,95 @@ func TestService_AddShipment(t *testing.T) {
 				ToAddress:       "Toronto, 34",
 				ToCountryCode:   "CA",
 				Weight:          234.4,
-				Price:           3000,
+				Price:           money.New(5228350, "SEK"),
+				Status:          models.StatusCreated,
+				PriceBreakdown:  &uaToCaBreakdownSEK,
+				FxRate:          &testFxRates[0],
+				Pieces:          []models.Piece{{Weight: 234.4, BillableWeight: 234.4, Price: money.New(5228350, "SEK")}},
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository, shipment models.Shipment) {
+				created := shipment
+				created.Id = 1
+
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipment(gomock.Eq(shipment)).Return(created, nil)
+			},
+			expectedPrice: money.New(5228350, "SEK"),
+			expectedError: nil,
+		},
+		{
+			name: "Ok with several pieces",
+			input: AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Pieces: []PieceInput{
+					{Weight: 5},
+					{Weight: 2, Length: 100, Width: 50, Height: 40},
+				},
+			},
+			inputShipment: models.Shipment{
+				TrackingNumber:  testTrackingNumber,
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          7,
+				Price:           money.New(150000, "EUR"),
+				Status:          models.StatusCreated,
+				PriceBreakdown: &models.PriceBreakdown{
+					RateCardVersion:  "default",
+					Lane:             models.Lane{Type: models.LaneIntercontinental, Origin: "europe", Destination: "world", Factor: 2.5},
+					VolumetricWeight: 40,
+					BillableWeight:   45,
+					Components: []models.PriceComponent{
+						{Type: models.PriceComponentBase, Name: "weight class", Amount: money.New(60000, "EUR")},
+						{Type: models.PriceComponentLane, Name: "intercontinental", Factor: 2.5, Amount: money.New(90000, "EUR")},
+					},
+					Total: money.New(150000, "EUR"),
+				},
+				Pieces: []models.Piece{
+					{Weight: 5, BillableWeight: 5, Price: money.New(25000, "EUR")},
+					{Weight: 2, Length: 100, Width: 50, Height: 40, BillableWeight: 40, Price: money.New(125000, "EUR")},
+				},
 			},
 			mockBehaviur: func(r *mock_repositories.MockShipmentRepository, shipment models.Shipment) {
-				r.EXPECT().CreateShipment(gomock.Eq(shipment)).Return(nil)
+				created := shipment
+				created.Id = 1
+
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipment(gomock.Eq(shipment)).Return(created, nil)
 			},
-			expectedPrice: 3000,
+			expectedPrice: money.New(150000, "EUR"),
 			expectedError: nil,
 		},
+		{
+			name: "no fx rate for requested currency",
+			input: AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+				Currency:        "NOK",
+			},
+			mockBehaviur:  func(r *mock_repositories.MockShipmentRepository, shipment models.Shipment) {},
+			expectedPrice: money.Money{},
+			expectedError: fmt.Errorf("EUR/NOK: %w", fx.ErrorRateNotFound),
+		},
 		{
 			name: "failed to add in database",
 			input: AddShipmentInput{
@@ -66,6 +259,7 @@ func TestService_AddShipment(t *testing.T) {
 				Weight:          234.4,
 			},
 			inputShipment: models.Shipment{
+				TrackingNumber:  testTrackingNumber,
 				FromName:        "Mark",
 				FromEmail:       "testFrom@g.c",
 				FromAddress:     "Lviv, 45",
@@ -75,12 +269,16 @@ func TestService_AddShipment(t *testing.T) {
 				ToAddress:       "Toronto, 34",
 				ToCountryCode:   "CA",
 				Weight:          234.4,
-				Price:           3000,
+				Price:           money.New(500000, "EUR"),
+				Status:          models.StatusCreated,
+				PriceBreakdown:  &uaToCaBreakdown,
+				Pieces:          []models.Piece{{Weight: 234.4, BillableWeight: 234.4, Price: money.New(500000, "EUR")}},
 			},
 			mockBehaviur: func(r *mock_repositories.MockShipmentRepository, shipment models.Shipment) {
-				r.EXPECT().CreateShipment(gomock.Eq(shipment)).Return(errors.New("some db error"))
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+				r.EXPECT().CreateShipment(gomock.Eq(shipment)).Return(models.Shipment{}, errors.New("some db error"))
 			},
-			expectedPrice: 0,
+			expectedPrice: money.Money{},
 			expectedError: errors.New("some db error"),
 		},
 	}
@@ -94,14 +292,462 @@ func TestService_AddShipment(t *testing.T) {
 			shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
 			tC.mockBehaviur(shipmentRepo, tC.inputShipment)
 
-			service := InitShipmentService(shipmentRepo)
+			service := initTestService(t, shipmentRepo)
+
+			// Call method
+			actual, err := service.AddShipment(tC.input)
+
+			// Require
+			require.Equal(t, tC.expectedPrice, actual.Price)
+			require.Equal(t, tC.expectedError, err)
+			if err == nil {
+				require.Equal(t, uint(1), actual.Id)
+				require.Equal(t, testTrackingNumber, actual.TrackingNumber)
+			}
+		})
+	}
+}
+
+func TestService_WithOwner(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+	ownerRepo := mock_repositories.NewMockShipmentRepository(c)
+	shipmentRepo.EXPECT().WithOwner("acme").Return(ownerRepo)
+
+	// the tracking number is checked among the shipments of all owners
+	shipmentRepo.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+	ownerRepo.EXPECT().CreateShipment(gomock.Any()).DoAndReturn(func(shipment models.Shipment) (models.Shipment, error) {
+		shipment.Id = 1
+		return shipment, nil
+	})
+	ownerRepo.EXPECT().GetShipmentByID(uint(2)).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+
+	service := initTestService(t, shipmentRepo).WithOwner("acme")
+
+	created, err := service.AddShipment(AddShipmentInput{
+		FromName:        "Mark",
+		FromEmail:       "testFrom@g.c",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "CA",
+		Weight:          234.4,
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint(1), created.Id)
+
+	_, err = service.GetShipmentByID(2)
+	require.Equal(t, repositories.ErrorShipmentNotFound, err)
+}
+
+func TestService_WithTenant(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+	tenantRepo := mock_repositories.NewMockShipmentRepository(c)
+	shipmentRepo.EXPECT().WithTenant("brand-a").Return(tenantRepo)
+
+	// shipments of the tenant are priced by its rate card
+	card := pricing.DefaultRateCard()
+	card.Version = "brand-a"
+	tenantPricing, err := pricing.InitEngine(card)
+	require.NoError(t, err)
+
+	shipmentRepo.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+	tenantRepo.EXPECT().CreateShipment(gomock.Any()).DoAndReturn(func(shipment models.Shipment) (models.Shipment, error) {
+		shipment.Id = 1
+		return shipment, nil
+	})
+
+	service := initTestService(t, shipmentRepo).WithTenant("brand-a", tenantPricing)
+
+	created, err := service.AddShipment(AddShipmentInput{
+		FromName:        "Mark",
+		FromEmail:       "testFrom@g.c",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "CA",
+		Weight:          234.4,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "brand-a", created.PriceBreakdown.RateCardVersion)
+}
+
+func TestService_GetLane(t *testing.T) {
+	testCases := []struct {
+		name          string
+		from          string
+		to            string
+		expectedLane  models.Lane
+		expectedError error
+	}{
+		{
+			name:         "nordic to nordic",
+			from:         "SE",
+			to:           "NO",
+			expectedLane: models.Lane{Type: models.LaneIntraRegion, Origin: "nordic", Destination: "nordic", Factor: 1},
+		},
+		{
+			name:         "nordic to outside of europe",
+			from:         "SE",
+			to:           "US",
+			expectedLane: models.Lane{Type: models.LaneIntercontinental, Origin: "nordic", Destination: "world", Factor: 2.5},
+		},
+		{
+			name:          "invalid origin country code",
+			from:          "se",
+			to:            "US",
+			expectedError: helpers.ErrorInvalidCountryCode,
+		},
+		{
+			name:          "not existing destination country code",
+			from:          "SE",
+			to:            "UU",
+			expectedError: helpers.ErrorNotExistingCountryCode,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+
+			service := initTestService(t, shipmentRepo)
 
 			// Call method
-			actualPrice, err := service.AddShipment(tC.input)
+			actualLane, err := service.GetLane(tC.from, tC.to)
 
 			// Require
-			require.Equal(t, tC.expectedPrice, actualPrice)
+			require.Equal(t, tC.expectedLane, actualLane)
 			require.Equal(t, tC.expectedError, err)
 		})
 	}
 }
+
+func TestService_Quote(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          AddShipmentInput
+		expectedTotal  money.Money
+		expectedPieces []models.Piece
+		expectedError  error
+	}{
+		{
+			name: "Ok",
+			input: AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+			},
+			expectedTotal:  money.New(500000, "EUR"),
+			expectedPieces: []models.Piece{{Weight: 234.4, BillableWeight: 234.4, Price: money.New(500000, "EUR")}},
+		},
+		{
+			name: "invalid input",
+			input: AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          234.4,
+			},
+			expectedError: &ValidationError{Fields: []models.FieldError{{Path: "fromEmail", Code: CodeInvalidEmail, Message: "invalid email"}}},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps (repository must not be called)
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+
+			service := initTestService(t, shipmentRepo)
+
+			// Call method
+			breakdown, pieces, err := service.Quote(tC.input)
+
+			// Require
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedTotal, breakdown.Total)
+			require.Equal(t, tC.expectedPieces, pieces)
+		})
+	}
+}
+
+func TestService_ConvertShipment(t *testing.T) {
+	shipment := models.Shipment{
+		Id:              2,
+		FromName:        "Mark",
+		FromEmail:       "testFrom@g.c",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "CA",
+		Weight:          234.4,
+		Price:           money.New(500000, "EUR"),
+		PriceBreakdown:  &uaToCaBreakdown,
+	}
+
+	testCases := []struct {
+		name             string
+		shipment         models.Shipment
+		currency         string
+		expectedShipment models.Shipment
+		expectedRate     models.FxRate
+		expectedError    error
+	}{
+		{
+			name:     "Ok",
+			currency: "SEK",
+			expectedShipment: func() models.Shipment {
+				converted := shipment
+				converted.Price = money.New(5228350, "SEK")
+				breakdown := uaToCaBreakdownSEK
+				breakdown.FxRate = nil
+				converted.PriceBreakdown = &breakdown
+				return converted
+			}(),
+			expectedRate: testFxRates[0],
+		},
+		{
+			name:     "cancelled shipment",
+			currency: "SEK",
+			shipment: func() models.Shipment {
+				cancelled := shipment
+				cancelled.Status = models.StatusCancelled
+				cancelled.Cancellation = &models.Cancellation{
+					CancelledAt: time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+					Fee:         money.New(100000, "EUR"),
+					Refund:      money.New(400000, "EUR"),
+				}
+				return cancelled
+			}(),
+			expectedShipment: func() models.Shipment {
+				converted := shipment
+				converted.Status = models.StatusCancelled
+				converted.Price = money.New(5228350, "SEK")
+				breakdown := uaToCaBreakdownSEK
+				breakdown.FxRate = nil
+				converted.PriceBreakdown = &breakdown
+				converted.Cancellation = &models.Cancellation{
+					CancelledAt: time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+					Fee:         money.New(1045670, "SEK"),
+					Refund:      money.New(4182680, "SEK"),
+				}
+				return converted
+			}(),
+			expectedRate: testFxRates[0],
+		},
+		{
+			name:             "the same currency",
+			currency:         "EUR",
+			expectedShipment: shipment,
+			expectedRate:     models.FxRate{From: "EUR", To: "EUR", Rate: 1},
+		},
+		{
+			name:          "invalid currency",
+			currency:      "eur",
+			expectedError: helpers.ErrorInvalidCurrencyCode,
+		},
+		{
+			name:          "no fx rate",
+			currency:      "USD",
+			expectedError: fmt.Errorf("EUR/USD: %w", fx.ErrorRateNotFound),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := initTestService(t, mock_repositories.NewMockShipmentRepository(c))
+
+			input := shipment
+			if tC.shipment.Id != 0 {
+				input = tC.shipment
+			}
+
+			// Call method
+			actualShipment, actualRate, err := service.ConvertShipment(input, tC.currency)
+
+			// Require
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedShipment, actualShipment)
+			require.Equal(t, tC.expectedRate, actualRate)
+		})
+	}
+}
+
+func TestAddShipmentInput_Validate_Dimensions(t *testing.T) {
+	testCases := []struct {
+		name   string
+		length float64
+		width  float64
+		height float64
+		err    error
+	}{
+		{
+			name: "without dimensions",
+		},
+		{
+			name:   "correct dimensions",
+			length: 100,
+			width:  50,
+			height: 40,
+		},
+		{
+			name:   "missing height",
+			length: 100,
+			width:  50,
+			err:    &ValidationError{Fields: []models.FieldError{{Path: "height", Code: CodeInvalidDimensions, Message: "invalid dimensions"}}},
+		},
+		{
+			name:   "too long parcel",
+			length: 301,
+			width:  50,
+			height: 40,
+			err:    &ValidationError{Fields: []models.FieldError{{Path: "length", Code: CodeInvalidDimensions, Message: "invalid dimensions"}}},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			inp := AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          2,
+				Length:          tC.length,
+				Width:           tC.width,
+				Height:          tC.height,
+			}
+
+			require.Equal(t, tC.err, inp.Validate())
+		})
+	}
+}
+
+func TestAddShipmentInput_Validate_Pieces(t *testing.T) {
+	testCases := []struct {
+		name   string
+		weight float64
+		pieces []PieceInput
+		err    error
+	}{
+		{
+			name:   "correct pieces",
+			pieces: []PieceInput{{Weight: 5}, {Weight: 2, Length: 100, Width: 50, Height: 40}},
+		},
+		{
+			name:   "weight of the shipment and pieces",
+			weight: 7,
+			pieces: []PieceInput{{Weight: 5}, {Weight: 2}},
+			err:    &ValidationError{Fields: []models.FieldError{{Path: "weight", Code: CodeNotAllowed, Message: "must be given per piece"}}},
+		},
+		{
+			name:   "invalid weight of piece",
+			pieces: []PieceInput{{Weight: 5}, {Weight: 1001}},
+			err:    &ValidationError{Fields: []models.FieldError{{Path: "pieces[1].weight", Code: CodeInvalidWeight, Message: "invalid weight"}}},
+		},
+		{
+			name:   "invalid dimensions of piece",
+			pieces: []PieceInput{{Weight: 5, Length: 10}},
+			err: &ValidationError{Fields: []models.FieldError{
+				{Path: "pieces[0].width", Code: CodeInvalidDimensions, Message: "invalid dimensions"},
+				{Path: "pieces[0].height", Code: CodeInvalidDimensions, Message: "invalid dimensions"},
+			}},
+		},
+		{
+			name: "too many pieces",
+			pieces: func() []PieceInput {
+				pieces := make([]PieceInput, 51)
+				for i := range pieces {
+					pieces[i].Weight = 1
+				}
+				return pieces
+			}(),
+			err: &ValidationError{Fields: []models.FieldError{{Path: "pieces", Code: CodeTooManyPieces, Message: "too many pieces"}}},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			inp := AddShipmentInput{
+				FromName:        "Mark",
+				FromEmail:       "testFrom@g.c",
+				FromAddress:     "Lviv, 45",
+				FromCountryCode: "UA",
+				ToName:          "Iryna",
+				ToEmail:         "testTo@g.c",
+				ToAddress:       "Toronto, 34",
+				ToCountryCode:   "CA",
+				Weight:          tC.weight,
+				Pieces:          tC.pieces,
+			}
+
+			require.Equal(t, tC.err, inp.Validate())
+		})
+	}
+}
+
+func TestAddShipmentInput_Validate(t *testing.T) {
+	// every invalid field is returned (in the order of the request body)
+	inp := AddShipmentInput{
+		FromName:        "Mark",
+		FromEmail:       "testFrom",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "QQ",
+		Weight:          2000,
+		Length:          10,
+		Height:          400,
+		Currency:        "EURO",
+	}
+
+	err := inp.Validate()
+
+	require.Equal(t, &ValidationError{Fields: []models.FieldError{
+		{Path: "fromEmail", Code: CodeInvalidEmail, Message: "invalid email"},
+		{Path: "toName", Code: CodeRequired, Message: "is required"},
+		{Path: "toCountryCode", Code: CodeUnknownCountryCode, Message: "not existing country code"},
+		{Path: "weight", Code: CodeInvalidWeight, Message: "invalid weight"},
+		{Path: "width", Code: CodeInvalidDimensions, Message: "invalid dimensions"},
+		{Path: "height", Code: CodeInvalidDimensions, Message: "invalid dimensions"},
+		{Path: "currency", Code: CodeInvalidCurrencyCode, Message: "invalid currency code"},
+	}}, err)
+	require.Equal(t, "fromEmail: invalid email; toName: is required; toCountryCode: not existing country code; weight: invalid weight; width: invalid dimensions; height: invalid dimensions; currency: invalid currency code", err.Error())
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Taras-Rm/shipment/models"
+)
+
+var (
+	ErrorUnknownStatus     error = errors.New("unknown shipment status")
+	ErrorIllegalTransition error = errors.New("illegal status transition")
+)
+
+type TransitionShipmentInput struct {
+	Status models.ShipmentStatus `json:"status" binding:"required"`
+}
+
+// shipment lifecycle: allowed transitions from every status
+var shipmentTransitions = map[models.ShipmentStatus][]models.ShipmentStatus{
+	models.StatusCreated:        {models.StatusLabelPrinted, models.StatusCancelled},
+	models.StatusLabelPrinted:   {models.StatusPickedUp, models.StatusCancelled},
+	models.StatusPickedUp:       {models.StatusInTransit, models.StatusReturned, models.StatusCancelled},
+	models.StatusInTransit:      {models.StatusOutForDelivery, models.StatusReturned, models.StatusCancelled},
+	models.StatusOutForDelivery: {models.StatusDelivered, models.StatusInTransit, models.StatusReturned, models.StatusCancelled},
+	models.StatusDelivered:      {},
+	models.StatusReturned:       {},
+	models.StatusCancelled:      {},
+}
+
+// position of every status in the shipment lifecycle (terminal statuses are the last)
+var shipmentStatusOrder = map[models.ShipmentStatus]int{
+	models.StatusCreated:        0,
+	models.StatusLabelPrinted:   1,
+	models.StatusPickedUp:       2,
+	models.StatusInTransit:      3,
+	models.StatusOutForDelivery: 4,
+	models.StatusDelivered:      5,
+	models.StatusReturned:       6,
+	models.StatusCancelled:      6,
+}
+
+// check that shipment may be moved from one status to another
+func ValidateTransition(from, to models.ShipmentStatus) error {
+	allowed, ok := shipmentTransitions[from]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrorUnknownStatus, from)
+	}
+	if _, ok := shipmentTransitions[to]; !ok {
+		return fmt.Errorf("%w %q", ErrorUnknownStatus, to)
+	}
+
+	for _, status := range allowed {
+		if status == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w from %q to %q", ErrorIllegalTransition, from, to)
+}
+
+// move the shipment to the next status of its lifecycle
+func (s *shipmentService) TransitionShipment(id uint, status models.ShipmentStatus) (models.Shipment, error) {
+	// refund of the cancelled shipment has to be recorded
+	if status == models.StatusCancelled {
+		return s.CancelShipment(id)
+	}
+
+	shipment, err := s.shipmentRepository.GetShipmentByID(id)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	if err := ValidateTransition(shipment.Status, status); err != nil {
+		return models.Shipment{}, err
+	}
+
+	// update fails if the status was changed in the meantime
+	err = s.shipmentRepository.UpdateShipmentStatus(id, shipment.Status, status)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+	shipment.Status = status
+
+	return shipment, nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTransition(t *testing.T) {
+	testCases := []struct {
+		name string
+		from models.ShipmentStatus
+		to   models.ShipmentStatus
+		err  error
+	}{
+		{
+			name: "label is printed",
+			from: models.StatusCreated,
+			to:   models.StatusLabelPrinted,
+		},
+		{
+			name: "cancelled before pick up",
+			from: models.StatusLabelPrinted,
+			to:   models.StatusCancelled,
+		},
+		{
+			name: "delivered",
+			from: models.StatusOutForDelivery,
+			to:   models.StatusDelivered,
+		},
+		{
+			name: "failed delivery attempt",
+			from: models.StatusOutForDelivery,
+			to:   models.StatusInTransit,
+		},
+		{
+			name: "returned on the way",
+			from: models.StatusInTransit,
+			to:   models.StatusReturned,
+		},
+		{
+			name: "skipping pick up",
+			from: models.StatusCreated,
+			to:   models.StatusInTransit,
+			err:  ErrorIllegalTransition,
+		},
+		{
+			name: "cancelled after pick up",
+			from: models.StatusPickedUp,
+			to:   models.StatusCancelled,
+		},
+		{
+			name: "cancelled after delivery",
+			from: models.StatusDelivered,
+			to:   models.StatusCancelled,
+			err:  ErrorIllegalTransition,
+		},
+		{
+			name: "from terminal status",
+			from: models.StatusDelivered,
+			to:   models.StatusReturned,
+			err:  ErrorIllegalTransition,
+		},
+		{
+			name: "to the same status",
+			from: models.StatusInTransit,
+			to:   models.StatusInTransit,
+			err:  ErrorIllegalTransition,
+		},
+		{
+			name: "unknown status",
+			from: models.StatusCreated,
+			to:   "lost",
+			err:  ErrorUnknownStatus,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			err := ValidateTransition(tC.from, tC.to)
+			require.True(t, errors.Is(err, tC.err), "expected %v, got %v", tC.err, err)
+		})
+	}
+}
+
+func TestService_TransitionShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockShipmentRepository)
+
+	shipment := models.Shipment{Id: 2, FromCountryCode: "UA", ToCountryCode: "CA", Status: models.StatusLabelPrinted}
+
+	testCases := []struct {
+		name             string
+		status           models.ShipmentStatus
+		mockBehaviur     mockBehaviur
+		expectedShipment models.Shipment
+		expectedError    error
+	}{
+		{
+			name:   "Ok",
+			status: models.StatusPickedUp,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(2)).Return(shipment, nil)
+				r.EXPECT().UpdateShipmentStatus(uint(2), models.StatusLabelPrinted, models.StatusPickedUp).Return(nil)
+			},
+			expectedShipment: models.Shipment{Id: 2, FromCountryCode: "UA", ToCountryCode: "CA", Status: models.StatusPickedUp},
+		},
+		{
+			name:   "illegal transition",
+			status: models.StatusDelivered,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(2)).Return(shipment, nil)
+			},
+			expectedError: ErrorIllegalTransition,
+		},
+		{
+			name:   "shipment not found",
+			status: models.StatusPickedUp,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(2)).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedError: repositories.ErrorShipmentNotFound,
+		},
+		{
+			name:   "status was changed concurrently",
+			status: models.StatusPickedUp,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(2)).Return(shipment, nil)
+				r.EXPECT().UpdateShipmentStatus(uint(2), models.StatusLabelPrinted, models.StatusPickedUp).Return(repositories.ErrorStatusConflict)
+			},
+			expectedError: repositories.ErrorStatusConflict,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockShipmentRepository(c)
+			tC.mockBehaviur(repo)
+
+			service := initTestService(t, repo)
+
+			// Call method
+			actual, err := service.TransitionShipment(2, tC.status)
+
+			// Require
+			require.True(t, errors.Is(err, tC.expectedError), "expected %v, got %v", tC.expectedError, err)
+			require.Equal(t, tC.expectedShipment, actual)
+		})
+	}
+}
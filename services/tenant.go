@@ -0,0 +1,149 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/pricing"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	ErrorTenantNotFound          error = errors.New("tenant not found")
+	ErrorInvalidTenant           error = errors.New("invalid tenant")
+	ErrorUnsupportedTenantFormat error = errors.New("unsupported tenants format")
+)
+
+// tenant IDs are stored with the shipments and sent in the headers
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// colors of the branding are given as #rrggbb
+var brandingColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+//go:generate mockgen -source=tenant.go -destination=mocks/tenant.go
+type TenantService interface {
+	MultiTenant() bool
+	GetTenant(id string) (models.Tenant, error)
+	GetTenantByHost(host string) (models.Tenant, error)
+	PricingEngine(id string) pricing.Engine
+}
+
+type tenantService struct {
+	tenants map[string]models.Tenant
+	// tenant IDs by lower case host names
+	hosts map[string]string
+	// pricing of the tenants with their own rate cards
+	pricingEngines map[string]pricing.Engine
+}
+
+// without tenants all shipments belong to the single brand
+func InitTenantService(tenants []models.Tenant, pricingEngines map[string]pricing.Engine) (TenantService, error) {
+	s := &tenantService{
+		tenants:        map[string]models.Tenant{},
+		hosts:          map[string]string{},
+		pricingEngines: pricingEngines,
+	}
+
+	for _, tenant := range tenants {
+		if !tenantIDPattern.MatchString(tenant.Id) {
+			return nil, fmt.Errorf("%w: id %q", ErrorInvalidTenant, tenant.Id)
+		}
+		if _, ok := s.tenants[tenant.Id]; ok {
+			return nil, fmt.Errorf("%w: duplicated id %q", ErrorInvalidTenant, tenant.Id)
+		}
+		if field := invalidBrandingField(tenant.Branding); field != "" {
+			return nil, fmt.Errorf("%w: branding %s of %q", ErrorInvalidTenant, field, tenant.Id)
+		}
+		s.tenants[tenant.Id] = tenant
+
+		for _, host := range tenant.Hosts {
+			host = strings.ToLower(host)
+			if id, ok := s.hosts[host]; ok {
+				return nil, fmt.Errorf("%w: host %q of %q is used by %q", ErrorInvalidTenant, host, tenant.Id, id)
+			}
+			s.hosts[host] = tenant.Id
+		}
+	}
+
+	return s, nil
+}
+
+func (s *tenantService) MultiTenant() bool {
+	return len(s.tenants) != 0
+}
+
+func (s *tenantService) GetTenant(id string) (models.Tenant, error) {
+	tenant, ok := s.tenants[id]
+	if !ok {
+		return models.Tenant{}, ErrorTenantNotFound
+	}
+	return tenant, nil
+}
+
+// get the tenant by the host name of the request (the port is ignored)
+func (s *tenantService) GetTenantByHost(host string) (models.Tenant, error) {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	id, ok := s.hosts[strings.ToLower(host)]
+	if !ok {
+		return models.Tenant{}, ErrorTenantNotFound
+	}
+	return s.tenants[id], nil
+}
+
+// pricing of the tenant (nil if the tenant uses the default rate card)
+func (s *tenantService) PricingEngine(id string) pricing.Engine {
+	return s.pricingEngines[id]
+}
+
+// name of the first invalid field of the branding (empty if it is valid)
+func invalidBrandingField(branding models.Branding) string {
+	if branding.LogoURL != "" {
+		logoURL, err := url.Parse(branding.LogoURL)
+		if err != nil || logoURL.Scheme != "https" || logoURL.Host == "" {
+			return "logoUrl"
+		}
+	}
+	if branding.Color != "" && !brandingColorPattern.MatchString(branding.Color) {
+		return "color"
+	}
+	if branding.SupportEmail != "" && helpers.ValidateEmail(branding.SupportEmail) != nil {
+		return "supportEmail"
+	}
+	return ""
+}
+
+// load tenants from YAML or JSON file
+func LoadTenantsFile(path string) ([]models.Tenant, error) {
+	var tenants []models.Tenant
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tenants)
+	case ".json":
+		err = json.Unmarshal(data, &tenants)
+	default:
+		return nil, ErrorUnsupportedTenantFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tenants, nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/pricing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitTenantService(t *testing.T) {
+	testCases := []struct {
+		name          string
+		tenants       []models.Tenant
+		expectedError error
+	}{
+		{
+			name:    "OK",
+			tenants: []models.Tenant{{Id: "brand-a", Hosts: []string{"a.example.com"}}, {Id: "brand-b"}},
+		},
+		{
+			name:    "OK with branding",
+			tenants: []models.Tenant{{Id: "brand-a", Branding: models.Branding{DisplayName: "Brand A", LogoURL: "https://cdn.example.com/brand-a.png", Color: "#1a73e8", SupportEmail: "help@brand-a.com"}}},
+		},
+		{
+			name:          "http logo",
+			tenants:       []models.Tenant{{Id: "brand-a", Branding: models.Branding{LogoURL: "http://cdn.example.com/brand-a.png"}}},
+			expectedError: fmt.Errorf("%w: branding %s of %q", ErrorInvalidTenant, "logoUrl", "brand-a"),
+		},
+		{
+			name:          "invalid color",
+			tenants:       []models.Tenant{{Id: "brand-a", Branding: models.Branding{Color: "blue"}}},
+			expectedError: fmt.Errorf("%w: branding %s of %q", ErrorInvalidTenant, "color", "brand-a"),
+		},
+		{
+			name:          "invalid support email",
+			tenants:       []models.Tenant{{Id: "brand-a", Branding: models.Branding{SupportEmail: "help"}}},
+			expectedError: fmt.Errorf("%w: branding %s of %q", ErrorInvalidTenant, "supportEmail", "brand-a"),
+		},
+		{
+			name:          "invalid id",
+			tenants:       []models.Tenant{{Id: "Brand A"}},
+			expectedError: fmt.Errorf("%w: id %q", ErrorInvalidTenant, "Brand A"),
+		},
+		{
+			name:          "duplicated id",
+			tenants:       []models.Tenant{{Id: "brand-a"}, {Id: "brand-a"}},
+			expectedError: fmt.Errorf("%w: duplicated id %q", ErrorInvalidTenant, "brand-a"),
+		},
+		{
+			name:          "duplicated host",
+			tenants:       []models.Tenant{{Id: "brand-a", Hosts: []string{"a.example.com"}}, {Id: "brand-b", Hosts: []string{"A.example.com"}}},
+			expectedError: fmt.Errorf("%w: host %q of %q is used by %q", ErrorInvalidTenant, "a.example.com", "brand-b", "brand-a"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			_, err := InitTenantService(tC.tenants, nil)
+			require.Equal(t, tC.expectedError, err)
+		})
+	}
+}
+
+func TestTenantService(t *testing.T) {
+	engine, err := pricing.InitEngine(pricing.DefaultRateCard())
+	require.NoError(t, err)
+
+	brandA := models.Tenant{Id: "brand-a", Name: "Brand A", Hosts: []string{"brand-a.example.com"}}
+	service, err := InitTenantService([]models.Tenant{brandA, {Id: "brand-b"}}, map[string]pricing.Engine{"brand-a": engine})
+	require.NoError(t, err)
+	require.True(t, service.MultiTenant())
+
+	tenant, err := service.GetTenant("brand-a")
+	require.NoError(t, err)
+	require.Equal(t, brandA, tenant)
+	_, err = service.GetTenant("brand-c")
+	require.Equal(t, ErrorTenantNotFound, err)
+
+	// the port and the case of the host are ignored
+	tenant, err = service.GetTenantByHost("Brand-A.example.com:8080")
+	require.NoError(t, err)
+	require.Equal(t, brandA, tenant)
+	_, err = service.GetTenantByHost("localhost:8080")
+	require.Equal(t, ErrorTenantNotFound, err)
+
+	require.Equal(t, engine, service.PricingEngine("brand-a"))
+	require.Nil(t, service.PricingEngine("brand-b"))
+
+	single, err := InitTenantService(nil, nil)
+	require.NoError(t, err)
+	require.False(t, single.MultiTenant())
+}
+
+func TestLoadTenantsFile(t *testing.T) {
+	tenants, err := LoadTenantsFile("./fixtures/tenants.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []models.Tenant{
+		{Id: "brand-a", Name: "Brand A", Hosts: []string{"brand-a.shipments.example.com"}, RateCardPath: "ratecards/brand-a.yaml", Branding: models.Branding{DisplayName: "Brand A Parcels", LogoURL: "https://cdn.example.com/brand-a.png", Color: "#1a73e8", SupportEmail: "help@brand-a.com"}},
+		{Id: "brand-b", Name: "Brand B"},
+	}, tenants)
+
+	_, err = LoadTenantsFile("./fixtures/tenants.txt")
+	require.Equal(t, ErrorUnsupportedTenantFormat, err)
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+)
+
+type AddTrackingEventInput struct {
+	OccurredAt time.Time            `json:"occurredAt"`
+	Code       string               `json:"code" binding:"required"`
+	Location   models.EventLocation `json:"location"`
+	Note       string               `json:"note"`
+}
+
+const (
+	// max length of the free-text note of the event
+	maxEventNote = 500
+	// max length of the facility and city names
+	maxEventLocation = 100
+)
+
+// event codes are upper snake case, e.g. ARRIVED_AT_HUB
+var eventCodeRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,31}$`)
+
+func (i AddTrackingEventInput) Validate() error {
+	// check event code
+	if !eventCodeRegexp.MatchString(i.Code) {
+		return errors.New("invalid event code")
+	}
+
+	// check time (events can not be scanned in the future)
+	if i.OccurredAt.After(time.Now()) {
+		return errors.New("invalid event time")
+	}
+
+	// check location (all parts are optional)
+	if len(i.Location.Facility) > maxEventLocation || len(i.Location.City) > maxEventLocation {
+		return errors.New("invalid event location")
+	}
+	if i.Location.CountryCode != "" {
+		if err := helpers.ValidateCountryCode(i.Location.CountryCode); err != nil {
+			return err
+		}
+	}
+
+	// check note
+	if len(i.Note) > maxEventNote {
+		return errors.New("too long event note")
+	}
+
+	return nil
+}
+
+// append a scan event to the shipment
+func (s *shipmentService) AddTrackingEvent(id uint, inp AddTrackingEventInput) (models.TrackingEvent, error) {
+	if err := inp.Validate(); err != nil {
+		return models.TrackingEvent{}, err
+	}
+
+	// event may be added only to an existing shipment
+	if _, err := s.shipmentRepository.GetShipmentByID(id); err != nil {
+		return models.TrackingEvent{}, err
+	}
+
+	// event time is the time of the request if it is not given
+	occurredAt := inp.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	event := models.TrackingEvent{
+		OccurredAt: occurredAt.UTC(),
+		Code:       inp.Code,
+		Location:   inp.Location,
+		Note:       inp.Note,
+	}
+
+	return s.trackingEventRepository.CreateTrackingEvent(id, event)
+}
+
+// get the events of the shipment in chronological order
+func (s *shipmentService) GetTrackingEvents(id uint) ([]models.TrackingEvent, error) {
+	if _, err := s.shipmentRepository.GetShipmentByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.trackingEventRepository.GetTrackingEvents(id)
+}
+
+// get the shipment with its events by the tracking number
+func (s *shipmentService) TrackShipment(number string) (models.Shipment, []models.TrackingEvent, error) {
+	shipment, err := s.GetShipmentByTrackingNumber(number)
+	if err != nil {
+		return models.Shipment{}, nil, err
+	}
+
+	events, err := s.trackingEventRepository.GetTrackingEvents(shipment.Id)
+	if err != nil {
+		return models.Shipment{}, nil, err
+	}
+
+	return shipment, events, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/Taras-Rm/shipment/tracking"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddTrackingEventInput_Validate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         AddTrackingEventInput
+		expectedError error
+	}{
+		{
+			name: "Ok",
+			input: AddTrackingEventInput{
+				OccurredAt: time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC),
+				Code:       "ARRIVED_AT_HUB",
+				Location:   models.EventLocation{Facility: "Hub 3", City: "Warsaw", CountryCode: "PL"},
+				Note:       "arrived on time",
+			},
+		},
+		{
+			name:  "Ok without time and location",
+			input: AddTrackingEventInput{Code: "PICKED_UP"},
+		},
+		{
+			name:          "invalid code",
+			input:         AddTrackingEventInput{Code: "arrived at hub"},
+			expectedError: errors.New("invalid event code"),
+		},
+		{
+			name:          "time in the future",
+			input:         AddTrackingEventInput{Code: "PICKED_UP", OccurredAt: time.Now().Add(time.Hour)},
+			expectedError: errors.New("invalid event time"),
+		},
+		{
+			name:          "too long city",
+			input:         AddTrackingEventInput{Code: "PICKED_UP", Location: models.EventLocation{City: strings.Repeat("a", 101)}},
+			expectedError: errors.New("invalid event location"),
+		},
+		{
+			name:          "not existing country",
+			input:         AddTrackingEventInput{Code: "PICKED_UP", Location: models.EventLocation{CountryCode: "QQ"}},
+			expectedError: helpers.ErrorNotExistingCountryCode,
+		},
+		{
+			name:          "too long note",
+			input:         AddTrackingEventInput{Code: "PICKED_UP", Note: strings.Repeat("a", 501)},
+			expectedError: errors.New("too long event note"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			err := tC.input.Validate()
+			require.Equal(t, tC.expectedError, err)
+		})
+	}
+}
+
+func TestService_AddTrackingEvent(t *testing.T) {
+	type mockBehaviur func(s *mock_repositories.MockShipmentRepository, e *mock_repositories.MockTrackingEventRepository)
+
+	occurredAt := time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC)
+	event := models.TrackingEvent{
+		OccurredAt: occurredAt,
+		Code:       "ARRIVED_AT_HUB",
+		Location:   models.EventLocation{City: "Warsaw", CountryCode: "PL"},
+	}
+
+	testCases := []struct {
+		name          string
+		input         AddTrackingEventInput
+		mockBehaviur  mockBehaviur
+		expectedEvent models.TrackingEvent
+		expectedError error
+	}{
+		{
+			name:  "Ok",
+			input: AddTrackingEventInput{OccurredAt: occurredAt, Code: "ARRIVED_AT_HUB", Location: event.Location},
+			mockBehaviur: func(s *mock_repositories.MockShipmentRepository, e *mock_repositories.MockTrackingEventRepository) {
+				created := event
+				created.Id = 7
+
+				s.EXPECT().GetShipmentByID(uint(2)).Return(models.Shipment{Id: 2}, nil)
+				e.EXPECT().CreateTrackingEvent(uint(2), event).Return(created, nil)
+			},
+			expectedEvent: models.TrackingEvent{Id: 7, OccurredAt: occurredAt, Code: "ARRIVED_AT_HUB", Location: event.Location},
+		},
+		{
+			name:          "invalid event",
+			input:         AddTrackingEventInput{Code: "hub"},
+			mockBehaviur:  func(s *mock_repositories.MockShipmentRepository, e *mock_repositories.MockTrackingEventRepository) {},
+			expectedError: errors.New("invalid event code"),
+		},
+		{
+			name:  "shipment not found",
+			input: AddTrackingEventInput{OccurredAt: occurredAt, Code: "ARRIVED_AT_HUB"},
+			mockBehaviur: func(s *mock_repositories.MockShipmentRepository, e *mock_repositories.MockTrackingEventRepository) {
+				s.EXPECT().GetShipmentByID(uint(2)).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedError: repositories.ErrorShipmentNotFound,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+			eventRepo := mock_repositories.NewMockTrackingEventRepository(c)
+			tC.mockBehaviur(shipmentRepo, eventRepo)
+
+			service := initTestServiceWithEvents(t, shipmentRepo, eventRepo)
+
+			// Call method
+			actual, err := service.AddTrackingEvent(2, tC.input)
+
+			// Require
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedEvent, actual)
+		})
+	}
+
+	t.Run("event time defaults to now", func(t *testing.T) {
+		c := gomock.NewController(t)
+		defer c.Finish()
+
+		shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+		eventRepo := mock_repositories.NewMockTrackingEventRepository(c)
+		shipmentRepo.EXPECT().GetShipmentByID(uint(2)).Return(models.Shipment{Id: 2}, nil)
+		eventRepo.EXPECT().CreateTrackingEvent(uint(2), gomock.Any()).DoAndReturn(func(id uint, event models.TrackingEvent) (models.TrackingEvent, error) {
+			return event, nil
+		})
+
+		service := initTestServiceWithEvents(t, shipmentRepo, eventRepo)
+
+		before := time.Now()
+		actual, err := service.AddTrackingEvent(2, AddTrackingEventInput{Code: "PICKED_UP"})
+		require.NoError(t, err)
+		require.False(t, actual.OccurredAt.Before(before))
+		require.Equal(t, time.UTC, actual.OccurredAt.Location())
+	})
+}
+
+func TestService_GetTrackingEvents(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	events := []models.TrackingEvent{
+		{Id: 1, OccurredAt: time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC), Code: "PICKED_UP"},
+		{Id: 2, OccurredAt: time.Date(2022, 1, 11, 9, 0, 0, 0, time.UTC), Code: "ARRIVED_AT_HUB"},
+	}
+
+	shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+	eventRepo := mock_repositories.NewMockTrackingEventRepository(c)
+	shipmentRepo.EXPECT().GetShipmentByID(uint(2)).Return(models.Shipment{Id: 2}, nil)
+	eventRepo.EXPECT().GetTrackingEvents(uint(2)).Return(events, nil)
+	shipmentRepo.EXPECT().GetShipmentByID(uint(3)).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+
+	service := initTestServiceWithEvents(t, shipmentRepo, eventRepo)
+
+	actual, err := service.GetTrackingEvents(2)
+	require.NoError(t, err)
+	require.Equal(t, events, actual)
+
+	_, err = service.GetTrackingEvents(3)
+	require.Equal(t, repositories.ErrorShipmentNotFound, err)
+}
+
+func TestService_TrackShipment(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	shipment := models.Shipment{Id: 2, TrackingNumber: testTrackingNumber, Status: models.StatusPickedUp}
+	events := []models.TrackingEvent{
+		{Id: 1, OccurredAt: time.Date(2022, 1, 10, 8, 30, 0, 0, time.UTC), Code: "PICKED_UP"},
+	}
+
+	shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+	eventRepo := mock_repositories.NewMockTrackingEventRepository(c)
+	shipmentRepo.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(shipment, nil)
+	eventRepo.EXPECT().GetTrackingEvents(uint(2)).Return(events, nil)
+
+	service := initTestServiceWithEvents(t, shipmentRepo, eventRepo)
+
+	actualShipment, actualEvents, err := service.TrackShipment("sh169090604ua")
+	require.NoError(t, err)
+	require.Equal(t, shipment, actualShipment)
+	require.Equal(t, events, actualEvents)
+
+	// number with a wrong check digit is not looked up
+	_, _, err = service.TrackShipment("SH169090605UA")
+	require.Equal(t, tracking.ErrorInvalidTrackingNumber, err)
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/Taras-Rm/shipment/repositories"
+)
+
+// attempts to generate a tracking number which is not used yet
+const maxTrackingNumberAttempts = 5
+
+var ErrorTrackingNumberNotGenerated error = errors.New("failed to generate unique tracking number")
+
+// generate tracking number for a new shipment
+func (s *shipmentService) newTrackingNumber() (string, error) {
+	return s.newTrackingNumberExcept(nil)
+}
+
+// generate tracking number which is not used yet and is not one of the given numbers
+func (s *shipmentService) newTrackingNumberExcept(taken map[string]bool) (string, error) {
+	for i := 0; i < maxTrackingNumberAttempts; i++ {
+		number, err := s.trackingNumbers.Generate()
+		if err != nil {
+			return "", err
+		}
+		if taken[number] {
+			continue
+		}
+
+		// the unique index protects from a collision with a concurrent request
+		_, err = s.allShipments.GetShipmentByTrackingNumber(number)
+		if errors.Is(err, repositories.ErrorShipmentNotFound) {
+			return number, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "", ErrorTrackingNumberNotGenerated
+}
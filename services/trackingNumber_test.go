@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/Taras-Rm/shipment/tracking"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_newTrackingNumber(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockShipmentRepository)
+
+	testCases := []struct {
+		name           string
+		mockBehaviur   mockBehaviur
+		expectedNumber string
+		expectedError  error
+	}{
+		{
+			name: "Ok",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedNumber: testTrackingNumber,
+		},
+		{
+			name: "Ok after collision",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				gomock.InOrder(
+					r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{Id: 1}, nil),
+					r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound),
+				)
+			},
+			expectedNumber: testTrackingNumber,
+		},
+		{
+			name: "all numbers are used",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{Id: 1}, nil).Times(maxTrackingNumberAttempts)
+			},
+			expectedError: ErrorTrackingNumberNotGenerated,
+		},
+		{
+			name: "some db error",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, errors.New("some db error"))
+			},
+			expectedError: errors.New("some db error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockShipmentRepository(c)
+			tC.mockBehaviur(repo)
+
+			service := initTestService(t, repo).(*shipmentService)
+
+			// Call method
+			number, err := service.newTrackingNumber()
+
+			// Require
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedNumber, number)
+		})
+	}
+}
+
+func TestService_newTrackingNumberExcept(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// the only generated number is already taken by the batch (repository is not asked)
+	repo := mock_repositories.NewMockShipmentRepository(c)
+	service := initTestService(t, repo).(*shipmentService)
+
+	number, err := service.newTrackingNumberExcept(map[string]bool{testTrackingNumber: true})
+	require.Equal(t, ErrorTrackingNumberNotGenerated, err)
+	require.Equal(t, "", number)
+}
+
+func TestService_GetShipmentByTrackingNumber(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockShipmentRepository)
+
+	testCases := []struct {
+		name             string
+		number           string
+		mockBehaviur     mockBehaviur
+		expectedShipment models.Shipment
+		expectedError    error
+	}{
+		{
+			name:   "Ok",
+			number: testTrackingNumber,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{Id: 2, TrackingNumber: testTrackingNumber}, nil)
+			},
+			expectedShipment: models.Shipment{Id: 2, TrackingNumber: testTrackingNumber},
+		},
+		{
+			name:   "Ok with spaces and lower case",
+			number: "sh 1690 9060 4ua",
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{Id: 2, TrackingNumber: testTrackingNumber}, nil)
+			},
+			expectedShipment: models.Shipment{Id: 2, TrackingNumber: testTrackingNumber},
+		},
+		{
+			name:          "wrong check digit",
+			number:        "SH169090605UA",
+			mockBehaviur:  func(r *mock_repositories.MockShipmentRepository) {},
+			expectedError: tracking.ErrorInvalidTrackingNumber,
+		},
+		{
+			name:   "not found",
+			number: testTrackingNumber,
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByTrackingNumber(testTrackingNumber).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedError: repositories.ErrorShipmentNotFound,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repositories.NewMockShipmentRepository(c)
+			tC.mockBehaviur(repo)
+
+			service := initTestService(t, repo)
+
+			// Call method
+			actual, err := service.GetShipmentByTrackingNumber(tC.number)
+
+			// Require
+			require.Equal(t, tC.expectedError, err)
+			require.Equal(t, tC.expectedShipment, actual)
+		})
+	}
+}
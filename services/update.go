@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Taras-Rm/shipment/models"
+)
+
+var (
+	ErrorShipmentNotEditable error = errors.New("shipment can't be edited in status")
+)
+
+// partial update of the shipment (only given fields are changed)
+type PatchShipmentInput struct {
+	FromName        *string       `json:"fromName"`
+	FromEmail       *string       `json:"fromEmail"`
+	FromAddress     *string       `json:"fromAddress"`
+	FromCountryCode *string       `json:"fromCountryCode"`
+	ToName          *string       `json:"toName"`
+	ToEmail         *string       `json:"toEmail"`
+	ToAddress       *string       `json:"toAddress"`
+	ToCountryCode   *string       `json:"toCountryCode"`
+	Weight          *float64      `json:"weight"`
+	Length          *float64      `json:"length"`
+	Width           *float64      `json:"width"`
+	Height          *float64      `json:"height"`
+	Pieces          *[]PieceInput `json:"pieces"`
+	Currency        *string       `json:"currency"`
+}
+
+// merge the given fields into the shipment input
+func (i PatchShipmentInput) Apply(inp AddShipmentInput) AddShipmentInput {
+	setString(&inp.FromName, i.FromName)
+	setString(&inp.FromEmail, i.FromEmail)
+	setString(&inp.FromAddress, i.FromAddress)
+	setString(&inp.FromCountryCode, i.FromCountryCode)
+	setString(&inp.ToName, i.ToName)
+	setString(&inp.ToEmail, i.ToEmail)
+	setString(&inp.ToAddress, i.ToAddress)
+	setString(&inp.ToCountryCode, i.ToCountryCode)
+	setString(&inp.Currency, i.Currency)
+
+	// single parcel and pieces replace each other
+	parcel := i.Weight != nil || i.Length != nil || i.Width != nil || i.Height != nil
+	if i.Pieces != nil && !parcel {
+		inp.Weight, inp.Length, inp.Width, inp.Height = 0, 0, 0, 0
+	}
+	if parcel && i.Pieces == nil {
+		inp.Pieces = nil
+	}
+	setFloat(&inp.Weight, i.Weight)
+	setFloat(&inp.Length, i.Length)
+	setFloat(&inp.Width, i.Width)
+	setFloat(&inp.Height, i.Height)
+	if i.Pieces != nil {
+		inp.Pieces = *i.Pieces
+	}
+
+	return inp
+}
+
+func setString(field *string, value *string) {
+	if value != nil {
+		*field = *value
+	}
+}
+
+func setFloat(field *float64, value *float64) {
+	if value != nil {
+		*field = *value
+	}
+}
+
+// input which would add the stored shipment (base of partial updates)
+func shipmentInput(shipment models.Shipment) AddShipmentInput {
+	inp := AddShipmentInput{
+		FromName:        shipment.FromName,
+		FromEmail:       shipment.FromEmail,
+		FromAddress:     shipment.FromAddress,
+		FromCountryCode: shipment.FromCountryCode,
+		ToName:          shipment.ToName,
+		ToEmail:         shipment.ToEmail,
+		ToAddress:       shipment.ToAddress,
+		ToCountryCode:   shipment.ToCountryCode,
+	}
+
+	// currency was requested only when the price was converted
+	if shipment.FxRate != nil {
+		inp.Currency = shipment.Price.Currency
+	}
+
+	// shipment of a single parcel has the same dimensions as its only piece
+	single := len(shipment.Pieces) == 0 || (len(shipment.Pieces) == 1 &&
+		shipment.Pieces[0].Length == shipment.Length &&
+		shipment.Pieces[0].Width == shipment.Width &&
+		shipment.Pieces[0].Height == shipment.Height)
+	if single {
+		inp.Weight = shipment.Weight
+		inp.Length = shipment.Length
+		inp.Width = shipment.Width
+		inp.Height = shipment.Height
+		return inp
+	}
+
+	for _, piece := range shipment.Pieces {
+		inp.Pieces = append(inp.Pieces, PieceInput{
+			Weight: piece.Weight,
+			Length: piece.Length,
+			Width:  piece.Width,
+			Height: piece.Height,
+		})
+	}
+	return inp
+}
+
+// check that the price of the shipment depends on the changed fields
+func repriceNeeded(shipment models.Shipment, inp AddShipmentInput) bool {
+	if inp.FromCountryCode != shipment.FromCountryCode || inp.ToCountryCode != shipment.ToCountryCode {
+		return true
+	}
+
+	// rate card currency is used when no currency is given
+	sameCurrency := inp.Currency == shipment.Price.Currency || (inp.Currency == "" && shipment.FxRate == nil)
+	if !sameCurrency {
+		return true
+	}
+
+	return !reflect.DeepEqual(inp.Parcels(), shipmentInput(shipment).Parcels())
+}
+
+// replace all data of the shipment
+func (s *shipmentService) UpdateShipment(id uint, inp AddShipmentInput) (models.Shipment, error) {
+	shipment, err := s.shipmentRepository.GetShipmentByID(id)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	return s.updateShipment(shipment, inp)
+}
+
+// change only the given fields of the shipment
+func (s *shipmentService) PatchShipment(id uint, inp PatchShipmentInput) (models.Shipment, error) {
+	shipment, err := s.shipmentRepository.GetShipmentByID(id)
+	if err != nil {
+		return models.Shipment{}, err
+	}
+
+	return s.updateShipment(shipment, inp.Apply(shipmentInput(shipment)))
+}
+
+func (s *shipmentService) updateShipment(shipment models.Shipment, inp AddShipmentInput) (models.Shipment, error) {
+	if !s.policy.Editable(shipment.Status) {
+		return models.Shipment{}, fmt.Errorf("%w %q", ErrorShipmentNotEditable, shipment.Status)
+	}
+
+	// the updated shipment has to be valid as a new one
+	if err := inp.Validate(); err != nil {
+		return models.Shipment{}, err
+	}
+
+	updated := shipment
+	updated.FromName = inp.FromName
+	updated.FromEmail = inp.FromEmail
+	updated.FromAddress = inp.FromAddress
+	updated.FromCountryCode = inp.FromCountryCode
+	updated.ToName = inp.ToName
+	updated.ToEmail = inp.ToEmail
+	updated.ToAddress = inp.ToAddress
+	updated.ToCountryCode = inp.ToCountryCode
+
+	// price is calculated again by the current rate card
+	if repriceNeeded(shipment, inp) {
+		breakdown, pieces, err := s.price(inp)
+		if err != nil {
+			return models.Shipment{}, err
+		}
+
+		updated.Weight = 0
+		for _, piece := range pieces {
+			updated.Weight += piece.Weight
+		}
+		updated.Length = inp.Length
+		updated.Width = inp.Width
+		updated.Height = inp.Height
+		updated.Price = breakdown.Total
+		updated.PriceBreakdown = &breakdown
+		updated.FxRate = breakdown.FxRate
+		updated.Pieces = pieces
+	}
+
+	// update fails if the status was changed in the meantime
+	return s.shipmentRepository.UpdateShipment(updated, shipment.Status)
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/money"
+	"github.com/Taras-Rm/shipment/repositories"
+	mock_repositories "github.com/Taras-Rm/shipment/repositories/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchShipmentInput_Apply(t *testing.T) {
+	name := "Tom"
+	weight := 5.0
+	pieces := []PieceInput{{Weight: 1}, {Weight: 2}}
+
+	testCases := []struct {
+		name     string
+		patch    PatchShipmentInput
+		input    AddShipmentInput
+		expected AddShipmentInput
+	}{
+		{
+			name:     "nothing is given",
+			input:    AddShipmentInput{FromName: "Mark", Weight: 1, Length: 10, Width: 10, Height: 10},
+			expected: AddShipmentInput{FromName: "Mark", Weight: 1, Length: 10, Width: 10, Height: 10},
+		},
+		{
+			name:     "name",
+			patch:    PatchShipmentInput{FromName: &name},
+			input:    AddShipmentInput{FromName: "Mark", ToName: "Iryna", Weight: 1},
+			expected: AddShipmentInput{FromName: "Tom", ToName: "Iryna", Weight: 1},
+		},
+		{
+			name:     "weight keeps dimensions",
+			patch:    PatchShipmentInput{Weight: &weight},
+			input:    AddShipmentInput{Weight: 1, Length: 10, Width: 10, Height: 10},
+			expected: AddShipmentInput{Weight: 5, Length: 10, Width: 10, Height: 10},
+		},
+		{
+			name:     "pieces replace single parcel",
+			patch:    PatchShipmentInput{Pieces: &pieces},
+			input:    AddShipmentInput{Weight: 1, Length: 10, Width: 10, Height: 10},
+			expected: AddShipmentInput{Pieces: pieces},
+		},
+		{
+			name:     "weight replaces pieces",
+			patch:    PatchShipmentInput{Weight: &weight},
+			input:    AddShipmentInput{Pieces: pieces},
+			expected: AddShipmentInput{Weight: 5},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			require.Equal(t, tC.expected, tC.patch.Apply(tC.input))
+		})
+	}
+}
+
+func TestService_UpdateShipment(t *testing.T) {
+	type mockBehaviur func(r *mock_repositories.MockShipmentRepository)
+
+	// shipment which is stored before the update
+	stored := models.Shipment{
+		Id:              1,
+		TrackingNumber:  testTrackingNumber,
+		FromName:        "Mark",
+		FromEmail:       "testFrom@g.c",
+		FromAddress:     "Lviv, 45",
+		FromCountryCode: "UA",
+		ToName:          "Iryna",
+		ToEmail:         "testTo@g.c",
+		ToAddress:       "Toronto, 34",
+		ToCountryCode:   "CA",
+		Weight:          234.4,
+		Price:           money.New(500000, "EUR"),
+		Status:          models.StatusCreated,
+		PriceBreakdown:  &uaToCaBreakdown,
+		Pieces:          []models.Piece{{Weight: 234.4, BillableWeight: 234.4, Price: money.New(500000, "EUR")}},
+	}
+	storedInput := shipmentInput(stored)
+
+	renamed := stored
+	renamed.FromName = "Tom"
+
+	converted := stored
+	converted.Price = money.New(5228350, "SEK")
+	converted.PriceBreakdown = &uaToCaBreakdownSEK
+	converted.FxRate = &testFxRates[0]
+	converted.Pieces = []models.Piece{{Weight: 234.4, BillableWeight: 234.4, Price: money.New(5228350, "SEK")}}
+
+	labelPrinted := stored
+	labelPrinted.Status = models.StatusLabelPrinted
+
+	name := "Tom"
+	email := "test"
+	currency := "SEK"
+
+	testCases := []struct {
+		name          string
+		update        func(s ShipmentService) (models.Shipment, error)
+		mockBehaviur  mockBehaviur
+		expected      models.Shipment
+		expectedError error
+	}{
+		{
+			name: "patch without new price",
+			update: func(s ShipmentService) (models.Shipment, error) {
+				return s.PatchShipment(1, PatchShipmentInput{FromName: &name})
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(stored, nil)
+				r.EXPECT().UpdateShipment(renamed, models.StatusCreated).Return(renamed, nil)
+			},
+			expected: renamed,
+		},
+		{
+			name: "patch with new price",
+			update: func(s ShipmentService) (models.Shipment, error) {
+				return s.PatchShipment(1, PatchShipmentInput{Currency: &currency})
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(stored, nil)
+				r.EXPECT().UpdateShipment(converted, models.StatusCreated).Return(converted, nil)
+			},
+			expected: converted,
+		},
+		{
+			name: "put of the same data",
+			update: func(s ShipmentService) (models.Shipment, error) {
+				return s.UpdateShipment(1, storedInput)
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(stored, nil)
+				r.EXPECT().UpdateShipment(stored, models.StatusCreated).Return(stored, nil)
+			},
+			expected: stored,
+		},
+		{
+			name: "invalid merged shipment",
+			update: func(s ShipmentService) (models.Shipment, error) {
+				return s.PatchShipment(1, PatchShipmentInput{ToEmail: &email})
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(stored, nil)
+			},
+			expectedError: &ValidationError{Fields: []models.FieldError{{Path: "toEmail", Code: CodeInvalidEmail, Message: "invalid email"}}},
+		},
+		{
+			name: "past the editable status",
+			update: func(s ShipmentService) (models.Shipment, error) {
+				return s.UpdateShipment(1, storedInput)
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(labelPrinted, nil)
+			},
+			expectedError: ErrorShipmentNotEditable,
+		},
+		{
+			name: "shipment not found",
+			update: func(s ShipmentService) (models.Shipment, error) {
+				return s.PatchShipment(1, PatchShipmentInput{FromName: &name})
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(models.Shipment{}, repositories.ErrorShipmentNotFound)
+			},
+			expectedError: repositories.ErrorShipmentNotFound,
+		},
+		{
+			name: "status changed concurrently",
+			update: func(s ShipmentService) (models.Shipment, error) {
+				return s.PatchShipment(1, PatchShipmentInput{FromName: &name})
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(stored, nil)
+				r.EXPECT().UpdateShipment(renamed, models.StatusCreated).Return(models.Shipment{}, repositories.ErrorStatusConflict)
+			},
+			expectedError: repositories.ErrorStatusConflict,
+		},
+		{
+			name: "some internal error",
+			update: func(s ShipmentService) (models.Shipment, error) {
+				return s.UpdateShipment(1, storedInput)
+			},
+			mockBehaviur: func(r *mock_repositories.MockShipmentRepository) {
+				r.EXPECT().GetShipmentByID(uint(1)).Return(models.Shipment{}, errors.New("some internal error"))
+			},
+			expectedError: errors.New("some internal error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			// Init deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			shipmentRepo := mock_repositories.NewMockShipmentRepository(c)
+			tC.mockBehaviur(shipmentRepo)
+
+			service := initTestService(t, shipmentRepo)
+
+			shipment, err := tC.update(service)
+			if tC.expectedError != nil {
+				if !errors.Is(err, tC.expectedError) {
+					require.Equal(t, tC.expectedError, err)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tC.expected, shipment)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/Taras-Rm/shipment/models"
+)
+
+// codes of the invalid fields
+const (
+	CodeRequired            = "required"
+	CodeInvalidType         = "invalid_type"
+	CodeInvalidValue        = "invalid_value"
+	CodeInvalidName         = "invalid_name"
+	CodeInvalidEmail        = "invalid_email"
+	CodeInvalidAddress      = "invalid_address"
+	CodeInvalidCountryCode  = "invalid_country_code"
+	CodeUnknownCountryCode  = "unknown_country_code"
+	CodeInvalidCurrencyCode = "invalid_currency_code"
+	CodeUnknownCurrencyCode = "unknown_currency_code"
+	CodeInvalidWeight       = "invalid_weight"
+	CodeInvalidDimensions   = "invalid_dimensions"
+	CodeTooManyPieces       = "too_many_pieces"
+	CodeNotAllowed          = "not_allowed"
+)
+
+// codes of the errors of the helper validators
+var validatorCodes = map[error]string{
+	helpers.ErrorInvalidName:             CodeInvalidName,
+	helpers.ErrorInvalidEmail:            CodeInvalidEmail,
+	helpers.ErrorInvalidAddress:          CodeInvalidAddress,
+	helpers.ErrorInvalidCountryCode:      CodeInvalidCountryCode,
+	helpers.ErrorNotExistingCountryCode:  CodeUnknownCountryCode,
+	helpers.ErrorInvalidCurrencyCode:     CodeInvalidCurrencyCode,
+	helpers.ErrorNotExistingCurrencyCode: CodeUnknownCurrencyCode,
+}
+
+// every invalid field of the request (not only the first one)
+type ValidationError struct {
+	Fields []models.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Path+": "+field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) add(path, code, message string) {
+	e.Fields = append(e.Fields, models.FieldError{Path: path, Code: code, Message: message})
+}
+
+// check the required string field by the helper validator
+func (e *ValidationError) check(path, value string, validate func(string) error) {
+	if value == "" {
+		e.add(path, CodeRequired, "is required")
+		return
+	}
+
+	if err := validate(value); err != nil {
+		code, ok := validatorCodes[err]
+		if !ok {
+			code = CodeInvalidValue
+		}
+		e.add(path, code, err.Error())
+	}
+}
+
+// nil if all fields are valid
+func (e *ValidationError) errorOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
@@ -25,7 +25,22 @@ func ConnectDB() (*gorm.DB, error) {
 	}
 
 	// Auto Migrate creating a table
-	err = db.AutoMigrate(&repositories.ShipmentModel{})
+	err = db.AutoMigrate(
+		&repositories.ShipmentModel{},
+		&repositories.PriceComponentModel{},
+		&repositories.ShipmentPieceModel{},
+		&repositories.TrackingEventModel{},
+		&repositories.RateCardModel{},
+		&repositories.FxRateModel{},
+		&repositories.IdempotencyKeyModel{},
+		&repositories.APIKeyModel{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// index of the full-text search
+	err = repositories.MigrateShipmentSearch(db)
 	if err != nil {
 		return nil, err
 	}
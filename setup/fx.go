@@ -0,0 +1,42 @@
+package setup
+
+import (
+	"fmt"
+
+	"github.com/Taras-Rm/shipment/config"
+	"github.com/Taras-Rm/shipment/fx"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+func InitFx(fxRateRepository repositories.FxRateRepository) (fx.Converter, error) {
+	var (
+		rates []models.FxRate
+		err   error
+	)
+
+	// get fx rates from the configured source
+	switch source := config.GetFxRatesSource(); source {
+	case "":
+	case "file":
+		rates, err = fx.LoadRatesFile(config.GetFxRatesPath())
+	case "db":
+		rates, err = fxRateRepository.GetAllFxRates()
+	default:
+		err = fmt.Errorf("unknown fx rates source %q", source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the rates are validated by the converter
+	converter, err := fx.InitConverter(rates)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("%d fx rates are loaded", len(rates))
+
+	return converter, nil
+}
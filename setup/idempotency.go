@@ -0,0 +1,37 @@
+package setup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Taras-Rm/shipment/config"
+	"github.com/Taras-Rm/shipment/repositories"
+	"github.com/Taras-Rm/shipment/services"
+	"github.com/sirupsen/logrus"
+)
+
+// interval of deleting the expired idempotency keys
+const idempotencyCleanupInterval = time.Hour
+
+func InitIdempotency(idempotencyRepository repositories.IdempotencyRepository) (services.IdempotencyService, error) {
+	ttl, err := time.ParseDuration(config.GetIdempotencyKeyTTL())
+	if err != nil {
+		return nil, fmt.Errorf("idempotency key ttl: %w", err)
+	}
+
+	idempotencyService, err := services.InitIdempotencyService(idempotencyRepository, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	// expired keys are not replayed anyway, they are deleted to keep the table small
+	go func() {
+		for range time.Tick(idempotencyCleanupInterval) {
+			if err := idempotencyService.DeleteExpiredKeys(); err != nil {
+				logrus.Error("can`t delete expired idempotency keys: ", err)
+			}
+		}
+	}()
+
+	return idempotencyService, nil
+}
@@ -0,0 +1,56 @@
+package setup
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Taras-Rm/shipment/config"
+	"github.com/Taras-Rm/shipment/jwt"
+	"github.com/sirupsen/logrus"
+)
+
+// verifier of the staff tokens (no token is accepted if no key is configured)
+func InitJWT() (jwt.Verifier, error) {
+	keys := jwt.Keys{
+		Secret: []byte(config.GetJWTSecret()),
+		RSA:    map[string]*rsa.PublicKey{},
+	}
+
+	// the single public key is used for the tokens without "kid"
+	if path := config.GetJWTPublicKeyPath(); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwt.ParseRSAPublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("jwt public key: %w", err)
+		}
+		keys.RSA[""] = key
+	}
+
+	if path := config.GetJWKSPath(); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		jwks, err := jwt.ParseJWKS(data)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: %w", err)
+		}
+		for kid, key := range jwks {
+			if _, ok := keys.RSA[kid]; ok {
+				return nil, fmt.Errorf("jwks: key %q is already configured", kid)
+			}
+			keys.RSA[kid] = key
+		}
+	}
+
+	if len(keys.Secret) == 0 && len(keys.RSA) == 0 {
+		logrus.Warn("no jwt keys are configured, staff tokens are not accepted")
+	}
+
+	return jwt.InitVerifier(keys, config.GetJWTIssuer(), config.GetJWTAudience(), time.Now), nil
+}
@@ -0,0 +1,23 @@
+package setup
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Taras-Rm/shipment/config"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/services"
+)
+
+func InitShipmentPolicy() (services.ShipmentPolicy, error) {
+	fee, err := strconv.ParseFloat(config.GetCancellationFeePercent(), 64)
+	if err != nil {
+		return services.ShipmentPolicy{}, fmt.Errorf("cancellation fee: %w", err)
+	}
+
+	return services.InitShipmentPolicy(
+		models.ShipmentStatus(config.GetEditableUntilStatus()),
+		models.ShipmentStatus(config.GetFreeCancellationUntilStatus()),
+		fee,
+	)
+}
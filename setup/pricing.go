@@ -0,0 +1,85 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Taras-Rm/shipment/config"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/pricing"
+	"github.com/Taras-Rm/shipment/repositories"
+	"github.com/Taras-Rm/shipment/services"
+	"github.com/sirupsen/logrus"
+)
+
+// interval of checking the db for a new effective rate card
+const rateCardRefreshInterval = time.Minute
+
+func InitPricing(rateCardRepository repositories.RateCardRepository) (pricing.Engine, error) {
+	var (
+		card models.RateCard
+		err  error
+	)
+
+	// get rate card from the configured source
+	switch source := config.GetRateCardSource(); source {
+	case "":
+		card = pricing.DefaultRateCard()
+	case "file":
+		card, err = pricing.LoadRateCardFile(config.GetRateCardPath())
+	case "db":
+		card, err = loadStoredRateCard(rateCardRepository)
+	default:
+		err = fmt.Errorf("unknown rate card source %q", source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the rate card is validated by the engine
+	engine, err := pricing.InitEngine(card)
+	if err != nil {
+		return nil, fmt.Errorf("rate card %q: %w", card.Version, err)
+	}
+
+	logrus.Infof("rate card %q is loaded", card.Version)
+
+	return engine, nil
+}
+
+// get the active rate card of the db (an empty db gets the built-in rate card as its first version)
+func loadStoredRateCard(rateCardRepository repositories.RateCardRepository) (models.RateCard, error) {
+	card, err := rateCardRepository.GetActiveRateCard(time.Now())
+	if !errors.Is(err, repositories.ErrorRateCardNotFound) {
+		return card, err
+	}
+
+	card = pricing.DefaultRateCard()
+	if err := rateCardRepository.CreateRateCard(card); err != nil {
+		return models.RateCard{}, fmt.Errorf("first rate card %q: %w", card.Version, err)
+	}
+
+	logrus.Infof("rate card %q is saved as the first version", card.Version)
+
+	return card, nil
+}
+
+// new rate card versions are saved and switched to without restart (only with the db source)
+func InitRateCards(rateCardRepository repositories.RateCardRepository, pricingEngine *pricing.SwitchableEngine) services.RateCardService {
+	stored := config.GetRateCardSource() == "db"
+	rateCardService := services.InitRateCardService(rateCardRepository, pricingEngine, stored)
+
+	// versions created by other instances or becoming effective later are picked up here
+	if stored {
+		go func() {
+			for range time.Tick(rateCardRefreshInterval) {
+				if err := rateCardService.RefreshRateCard(); err != nil {
+					logrus.Error("can`t refresh rate card: ", err)
+				}
+			}
+		}()
+	}
+
+	return rateCardService
+}
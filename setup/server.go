@@ -1,16 +1,44 @@
 package setup
 
-import "github.com/gin-gonic/gin"
+import (
+	"strings"
+
+	"github.com/Taras-Rm/shipment/config"
+	"github.com/gin-gonic/gin"
+)
 
 func ServerStart() *gin.Engine {
 
 	handler := gin.Default()
 
-	handler.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH")
+	handler.Use(cors(strings.Split(config.GetCORSAllowedOrigins(), ",")))
+
+	return handler
+}
+
+// allow cross-origin requests from the given origins ("*" for any origin)
+// requests are authenticated by the API key header, so credentials (cookies) are never allowed
+func cors(allowedOrigins []string) gin.HandlerFunc {
+	allowed := map[string]bool{}
+	for _, origin := range allowedOrigins {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowed[origin] = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin != "" && (allowed[origin] || allowed["*"]) {
+			if !allowed["*"] {
+				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+				c.Writer.Header().Add("Vary", "Origin")
+			} else {
+				c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+			}
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Idempotency-Key, X-API-Key, X-Tenant-ID")
+			c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH")
+			c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Disposition, Idempotent-Replayed, X-Import-Created, X-Import-Failed")
+		}
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -18,7 +46,5 @@ func ServerStart() *gin.Engine {
 		}
 
 		c.Next()
-	})
-
-	return handler
+	}
 }
@@ -0,0 +1,56 @@
+package setup
+
+import (
+	"fmt"
+
+	"github.com/Taras-Rm/shipment/config"
+	"github.com/Taras-Rm/shipment/models"
+	"github.com/Taras-Rm/shipment/pricing"
+	"github.com/Taras-Rm/shipment/services"
+	"github.com/sirupsen/logrus"
+)
+
+func InitTenants() (services.TenantService, error) {
+	var (
+		tenants []models.Tenant
+		err     error
+	)
+
+	if path := config.GetTenantsPath(); path != "" {
+		tenants, err = services.LoadTenantsFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// rate cards of the tenants are validated at startup too
+	pricingEngines := map[string]pricing.Engine{}
+	for _, tenant := range tenants {
+		if tenant.RateCardPath == "" {
+			continue
+		}
+
+		card, err := pricing.LoadRateCardFile(tenant.RateCardPath)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", tenant.Id, err)
+		}
+		engine, err := pricing.InitEngine(card)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: rate card %q: %w", tenant.Id, card.Version, err)
+		}
+		pricingEngines[tenant.Id] = engine
+
+		logrus.Infof("rate card %q of tenant %q is loaded", card.Version, tenant.Id)
+	}
+
+	tenantService, err := services.InitTenantService(tenants, pricingEngines)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tenants) != 0 {
+		logrus.Infof("%d tenants are loaded", len(tenants))
+	}
+
+	return tenantService, nil
+}
@@ -0,0 +1,12 @@
+package setup
+
+import (
+	"crypto/rand"
+
+	"github.com/Taras-Rm/shipment/config"
+	"github.com/Taras-Rm/shipment/tracking"
+)
+
+func InitTracking() (tracking.Generator, error) {
+	return tracking.InitGenerator(config.GetTrackingPrefix(), config.GetTrackingCountry(), rand.Reader)
+}
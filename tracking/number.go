@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Taras-Rm/shipment/helpers"
+)
+
+var (
+	ErrorInvalidPrefix         error = errors.New("tracking number prefix must be two letters")
+	ErrorInvalidTrackingNumber error = errors.New("invalid tracking number")
+)
+
+// S10 tracking number: service prefix, 8 digit serial number, check digit and country code (e.g. SH123456785SE)
+var numberRegexp = regexp.MustCompile(`^[A-Z]{2}[0-9]{9}[A-Z]{2}$`)
+
+var prefixRegexp = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// weights of the serial number digits (S10 check digit)
+var checkWeights = [8]int{8, 6, 4, 2, 3, 5, 9, 7}
+
+const serialNumbers = 100000000
+
+type Generator interface {
+	Generate() (string, error)
+}
+
+type generator struct {
+	prefix  string
+	country string
+	random  io.Reader
+}
+
+// serial numbers are read from the random source, so the numbers can not be enumerated
+func InitGenerator(prefix, country string, random io.Reader) (Generator, error) {
+	if !prefixRegexp.MatchString(prefix) {
+		return nil, ErrorInvalidPrefix
+	}
+	if err := helpers.ValidateCountryCode(country); err != nil {
+		return nil, fmt.Errorf("tracking number country: %w", err)
+	}
+
+	return &generator{prefix: prefix, country: country, random: random}, nil
+}
+
+// generate a new tracking number
+func (g *generator) Generate() (string, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(g.random, buf[:]); err != nil {
+		return "", err
+	}
+	serial := fmt.Sprintf("%08d", binary.BigEndian.Uint32(buf[:])%serialNumbers)
+
+	return g.prefix + serial + string(CheckDigit(serial)) + g.country, nil
+}
+
+// calculate check digit of the 8 digit serial number
+func CheckDigit(serial string) byte {
+	sum := 0
+	for i := range checkWeights {
+		sum += int(serial[i]-'0') * checkWeights[i]
+	}
+
+	check := 11 - sum%11
+	switch check {
+	case 10:
+		check = 0
+	case 11:
+		check = 5
+	}
+
+	return byte('0' + check)
+}
+
+// bring tracking number to the canonical form (upper case without spaces)
+func Normalize(number string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(number), ""))
+}
+
+// check format and check digit of the tracking number
+func Validate(number string) error {
+	if !numberRegexp.MatchString(number) {
+		return ErrorInvalidTrackingNumber
+	}
+	if CheckDigit(number[2:10]) != number[10] {
+		return ErrorInvalidTrackingNumber
+	}
+
+	return nil
+}
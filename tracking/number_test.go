@@ -0,0 +1,136 @@
+package tracking
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Taras-Rm/shipment/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDigit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		serial   string
+		expected byte
+	}{
+		{
+			name:     "UPU example",
+			serial:   "47312482",
+			expected: '9',
+		},
+		{
+			name:     "ordinary serial",
+			serial:   "12345678",
+			expected: '5',
+		},
+		{
+			name:     "remainder is 0",
+			serial:   "00000000",
+			expected: '5',
+		},
+		{
+			name:     "check digit is 10",
+			serial:   "00060000",
+			expected: '0',
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			require.Equal(t, string(tC.expected), string(CheckDigit(tC.serial)))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		number string
+		err    error
+	}{
+		{
+			name:   "valid number",
+			number: "RA473124829SE",
+		},
+		{
+			name:   "wrong check digit",
+			number: "RA473124824SE",
+			err:    ErrorInvalidTrackingNumber,
+		},
+		{
+			name:   "swapped digits",
+			number: "RA437124829SE",
+			err:    ErrorInvalidTrackingNumber,
+		},
+		{
+			name:   "lower case",
+			number: "ra473124829se",
+			err:    ErrorInvalidTrackingNumber,
+		},
+		{
+			name:   "too short",
+			number: "RA47312484SE",
+			err:    ErrorInvalidTrackingNumber,
+		},
+		{
+			name:   "shipment ID",
+			number: "42",
+			err:    ErrorInvalidTrackingNumber,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			require.Equal(t, tC.err, Validate(tC.number))
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	require.Equal(t, "RA473124829SE", Normalize(" ra 473 124 829 se "))
+}
+
+func TestGenerator(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// 0x01020304 = 16909060
+		generator, err := InitGenerator("SH", "UA", bytes.NewReader([]byte{1, 2, 3, 4, 0, 0, 0, 1}))
+		require.NoError(t, err)
+
+		number, err := generator.Generate()
+		require.NoError(t, err)
+		require.Equal(t, "SH169090604UA", number)
+		require.NoError(t, Validate(number))
+
+		number, err = generator.Generate()
+		require.NoError(t, err)
+		require.Equal(t, "SH000000014UA", number)
+	})
+
+	t.Run("serial is limited to 8 digits", func(t *testing.T) {
+		generator, err := InitGenerator("SH", "UA", bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff}))
+		require.NoError(t, err)
+
+		number, err := generator.Generate()
+		require.NoError(t, err)
+		require.NoError(t, Validate(number))
+	})
+
+	t.Run("random source is exhausted", func(t *testing.T) {
+		generator, err := InitGenerator("SH", "UA", bytes.NewReader([]byte{1, 2}))
+		require.NoError(t, err)
+
+		_, err = generator.Generate()
+		require.Error(t, err)
+	})
+
+	t.Run("invalid prefix", func(t *testing.T) {
+		_, err := InitGenerator("S1", "UA", bytes.NewReader(nil))
+		require.Equal(t, ErrorInvalidPrefix, err)
+	})
+
+	t.Run("invalid country", func(t *testing.T) {
+		_, err := InitGenerator("SH", "QQ", bytes.NewReader(nil))
+		require.ErrorIs(t, err, helpers.ErrorNotExistingCountryCode)
+	})
+}